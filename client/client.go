@@ -0,0 +1,262 @@
+// Package client talks to Redis compatible servers using the rheltypes
+// encoding, with pooling, pipelining and pub/sub support.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const defaultReadBuffer = 16 * 1024
+
+// aLongTimeAgo is a deadline in the past, setting it interrupts pending
+// reads and writes.
+var aLongTimeAgo = time.Unix(1, 0)
+
+// ErrClosed is returned when using a Conn or a Pool after Close.
+var ErrClosed = errors.New("client: closed")
+
+// Conn is a single connection to a server. It isn't safe for concurrent
+// use, share a Pool instead.
+//
+// Error replies are returned as rheltypes.Error values, they leave the Conn
+// usable. Any other error, e.g. a timeout, breaks it and is returned by
+// every later call.
+type Conn struct {
+	conn   net.Conn
+	parser *rheltypes.Parser
+	buf    []byte
+	opts   options
+	err    error
+}
+
+// Dial connects to the server at addr and negotiates the protocol, if
+// asked to.
+func Dial(ctx context.Context, addr string, opts ...Option) (*Conn, error) {
+	o := newOptions(opts)
+
+	dialer := net.Dialer{Timeout: o.dialTimeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	c := newConn(conn, o)
+
+	if o.protocol != rheltypes.Resp2 {
+		_, err = c.Do(ctx, "HELLO", strconv.Itoa(int(o.protocol)))
+		if err != nil {
+			c.Close()
+
+			return nil, fmt.Errorf("failed to negotiate protocol: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// NewConn wraps an established connection, no handshake is performed.
+func NewConn(conn net.Conn, opts ...Option) *Conn {
+	return newConn(conn, newOptions(opts))
+}
+
+func newConn(conn net.Conn, o options) *Conn {
+	return &Conn{
+		conn:   conn,
+		parser: rheltypes.NewParser(),
+		buf:    make([]byte, defaultReadBuffer),
+		opts:   o,
+	}
+}
+
+// Err returns the error that broke the connection, if any.
+func (c *Conn) Err() error {
+	return c.err
+}
+
+func (c *Conn) Close() error {
+	if c.err == nil {
+		c.err = ErrClosed
+	}
+
+	return c.conn.Close()
+}
+
+// Hijack hands the underlying connection over to the caller, along with
+// the parser holding the bytes already read from it. Its deadlines are
+// cleared and the Conn can't be used afterwards.
+func (c *Conn) Hijack() (net.Conn, *rheltypes.Parser) {
+	c.err = ErrClosed
+	c.conn.SetDeadline(time.Time{})
+
+	return c.conn, c.parser
+}
+
+// Do sends a command and waits for its reply.
+func (c *Conn) Do(
+	ctx context.Context,
+	args ...string,
+) (rheltypes.RhelType, error) {
+	return c.DoArray(ctx, rheltypes.NewArrayFromStrings(args))
+}
+
+// DoArray sends an already built command, e.g. one of the Render helpers,
+// and waits for its reply.
+func (c *Conn) DoArray(
+	ctx context.Context,
+	cmd rheltypes.Array,
+) (rheltypes.RhelType, error) {
+	return c.do(ctx, cmd, c.opts.readTimeout)
+}
+
+// DoBlocking sends a blocking command, e.g. BLPOP, that may wait up to block
+// on the server before replying. The read timeout is extended accordingly,
+// a zero block waits as long as the context allows.
+func (c *Conn) DoBlocking(
+	ctx context.Context,
+	block time.Duration,
+	args ...string,
+) (rheltypes.RhelType, error) {
+	timeout := block + c.opts.readTimeout
+	if block == 0 {
+		timeout = 0
+	}
+
+	return c.do(ctx, rheltypes.NewArrayFromStrings(args), timeout)
+}
+
+func (c *Conn) do(
+	ctx context.Context,
+	cmd rheltypes.Array,
+	timeout time.Duration,
+) (rheltypes.RhelType, error) {
+	if err := c.send(ctx, cmd.Serialize()); err != nil {
+		return nil, err
+	}
+
+	return c.receive(ctx, timeout)
+}
+
+// Receive waits for the next value sent by the server, e.g. a pub/sub
+// message, as long as the context allows.
+func (c *Conn) Receive(ctx context.Context) (rheltypes.RhelType, error) {
+	return c.receive(ctx, 0)
+}
+
+func (c *Conn) send(ctx context.Context, content []byte) (err error) {
+	if c.err != nil {
+		return c.err
+	}
+
+	defer c.watch(ctx, &err)()
+
+	c.conn.SetWriteDeadline(deadline(ctx, 0))
+
+	if _, err = c.conn.Write(content); err != nil {
+		return c.fail(fmt.Errorf("error sending command: %w", err))
+	}
+
+	return nil
+}
+
+// receive reads a single reply, error replies are returned as errors.
+func (c *Conn) receive(
+	ctx context.Context,
+	timeout time.Duration,
+) (reply rheltypes.RhelType, err error) {
+	if reply, err = c.read(ctx, timeout); err != nil {
+		return nil, err
+	}
+
+	if replyErr, failed := reply.(rheltypes.Error); failed {
+		return nil, replyErr
+	}
+
+	return reply, nil
+}
+
+func (c *Conn) read(
+	ctx context.Context,
+	timeout time.Duration,
+) (reply rheltypes.RhelType, err error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	defer c.watch(ctx, &err)()
+
+	c.conn.SetReadDeadline(deadline(ctx, timeout))
+
+	for {
+		frame, err := c.parser.Next()
+		if err == nil {
+			return frame.Value, nil
+		} else if !errors.Is(err, rheltypes.ErrIncomplete) {
+			return nil, c.fail(err)
+		}
+
+		n, err := c.conn.Read(c.buf)
+		if err != nil {
+			return nil, c.fail(fmt.Errorf("error reading reply: %w", err))
+		}
+
+		c.parser.Feed(c.buf[:n])
+	}
+}
+
+func (c *Conn) fail(err error) error {
+	c.err = err
+
+	return err
+}
+
+// watch interrupts the pending I/O when ctx is done, the returned function
+// stops watching and reports the context error in place of the I/O one.
+func (c *Conn) watch(ctx context.Context, err *error) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	exited := make(chan bool)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.conn.SetDeadline(aLongTimeAgo)
+			exited <- true
+		case <-done:
+			exited <- false
+		}
+	}()
+
+	return func() {
+		close(done)
+
+		if interrupted := <-exited; interrupted && *err != nil {
+			*err = c.fail(ctx.Err())
+		}
+	}
+}
+
+// deadline returns the earliest of the context deadline and timeout, the
+// zero time means none.
+func deadline(ctx context.Context, timeout time.Duration) (d time.Time) {
+	if timeout > 0 {
+		d = time.Now().Add(timeout)
+	}
+
+	if ctxDeadline, ok := ctx.Deadline(); ok && (d.IsZero() ||
+		ctxDeadline.Before(d)) {
+		d = ctxDeadline
+	}
+
+	return d
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const (
+	defaultDialTimeout = 5 * time.Second
+	defaultReadTimeout = 5 * time.Second
+	defaultMaxIdle     = 8
+)
+
+type options struct {
+	dialTimeout time.Duration
+	readTimeout time.Duration
+	protocol    rheltypes.Protocol
+	maxIdle     int
+	maxActive   int
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		dialTimeout: defaultDialTimeout,
+		readTimeout: defaultReadTimeout,
+		protocol:    rheltypes.Resp2,
+		maxIdle:     defaultMaxIdle,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Option configures connections created by Dial and NewPool.
+type Option func(*options)
+
+// WithDialTimeout bounds the time spent connecting, 5s by default.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.dialTimeout = timeout
+	}
+}
+
+// WithReadTimeout bounds the time spent waiting for a reply, 5s by default.
+// Zero waits as long as the context allows.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.readTimeout = timeout
+	}
+}
+
+// WithProtocol negotiates the protocol with HELLO once connected, RESP2
+// connections don't send it.
+func WithProtocol(proto rheltypes.Protocol) Option {
+	return func(o *options) {
+		o.protocol = proto
+	}
+}
+
+// WithMaxIdle sets how many idle connections a Pool keeps, 8 by default.
+func WithMaxIdle(n int) Option {
+	return func(o *options) {
+		o.maxIdle = n
+	}
+}
+
+// WithMaxActive caps the connections a Pool hands out at once, Get waits
+// for one to be returned past it. Zero, the default, means no limit.
+func WithMaxActive(n int) Option {
+	return func(o *options) {
+		o.maxActive = n
+	}
+}
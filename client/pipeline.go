@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// Pipeline queues commands and sends them in a single write, their replies
+// are read back in order.
+type Pipeline struct {
+	conn *Conn
+	cmds []rheltypes.Array
+}
+
+func (c *Conn) Pipeline() *Pipeline {
+	return &Pipeline{conn: c}
+}
+
+func (p *Pipeline) Queue(args ...string) *Pipeline {
+	return p.QueueArray(rheltypes.NewArrayFromStrings(args))
+}
+
+func (p *Pipeline) QueueArray(cmd rheltypes.Array) *Pipeline {
+	p.cmds = append(p.cmds, cmd)
+
+	return p
+}
+
+func (p *Pipeline) Len() int {
+	return len(p.cmds)
+}
+
+// Exec sends the queued commands and returns one reply for each of them.
+// Error replies are kept among the replies as rheltypes.Error values, the
+// returned error only reports a broken connection. The pipeline is empty
+// afterwards.
+func (p *Pipeline) Exec(
+	ctx context.Context,
+) (replies []rheltypes.RhelType, err error) {
+	cmds := p.cmds
+	p.cmds = nil
+
+	var content []byte
+
+	for _, cmd := range cmds {
+		content = append(content, cmd.Serialize()...)
+	}
+
+	if err = p.conn.send(ctx, content); err != nil {
+		return nil, err
+	}
+
+	replies = make([]rheltypes.RhelType, len(cmds))
+
+	timeout := p.conn.opts.readTimeout
+
+	for i := range cmds {
+		if replies[i], err = p.conn.read(ctx, timeout); err != nil {
+			return replies[:i], err
+		}
+	}
+
+	return replies, nil
+}
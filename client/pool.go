@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// Pool shares connections to a single server between goroutines.
+type Pool struct {
+	addr   string
+	opts   []Option
+	o      options
+	active chan struct{}
+	mu     sync.Mutex
+	idle   []*Conn
+	closed bool
+}
+
+func NewPool(addr string, opts ...Option) *Pool {
+	p := &Pool{
+		addr: addr,
+		opts: opts,
+		o:    newOptions(opts),
+	}
+
+	if p.o.maxActive > 0 {
+		p.active = make(chan struct{}, p.o.maxActive)
+	}
+
+	return p
+}
+
+// Get returns an idle connection or dials a new one, it waits for one to be
+// returned when WithMaxActive connections are already in use. Every Conn
+// obtained from Get must be given back with Put.
+func (p *Pool) Get(ctx context.Context) (*Conn, error) {
+	if p.active != nil {
+		select {
+		case p.active <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c, err := p.get(ctx)
+	if err != nil {
+		p.release()
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (p *Pool) get(ctx context.Context) (*Conn, error) {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return nil, ErrClosed
+	}
+
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+
+		return c, nil
+	}
+
+	p.mu.Unlock()
+
+	return Dial(ctx, p.addr, p.opts...)
+}
+
+// Put gives c back to the pool, broken connections and the ones past
+// WithMaxIdle are closed.
+func (p *Pool) Put(c *Conn) {
+	defer p.release()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c.Err() != nil || p.closed || len(p.idle) >= p.o.maxIdle {
+		c.Close()
+
+		return
+	}
+
+	p.idle = append(p.idle, c)
+}
+
+func (p *Pool) release() {
+	if p.active != nil {
+		<-p.active
+	}
+}
+
+// Do runs a single command on a pooled connection.
+func (p *Pool) Do(
+	ctx context.Context,
+	args ...string,
+) (rheltypes.RhelType, error) {
+	c, err := p.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer p.Put(c)
+
+	return c.Do(ctx, args...)
+}
+
+// Close closes the idle connections, the ones in use are closed when they
+// are put back.
+func (p *Pool) Close() (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	for _, c := range p.idle {
+		if closeErr := c.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	p.idle = nil
+
+	return err
+}
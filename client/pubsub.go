@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// Message is a value received by a subscribed connection. Kind is either
+// "message" along with its Channel and Payload, "pmessage" along with the
+// Pattern matching its Channel too, or the confirmation of a request, e.g.
+// "subscribe" for a Channel or "psubscribe" for a Pattern, with the number
+// of subscriptions left in Count.
+type Message struct {
+	Kind    string
+	Pattern string
+	Channel string
+	Payload string
+	Count   int
+}
+
+// PubSub is a connection subscribed to channels, it is meant to be used by
+// a single goroutine running its receive loop.
+type PubSub struct {
+	conn *Conn
+}
+
+func NewPubSub(conn *Conn) *PubSub {
+	return &PubSub{conn: conn}
+}
+
+func (p *PubSub) Conn() *Conn {
+	return p.conn
+}
+
+// Subscribe asks for the messages of channels, confirmations are received
+// like messages.
+func (p *PubSub) Subscribe(ctx context.Context, channels ...string) error {
+	return p.send(ctx, "SUBSCRIBE", channels)
+}
+
+func (p *PubSub) Unsubscribe(ctx context.Context, channels ...string) error {
+	return p.send(ctx, "UNSUBSCRIBE", channels)
+}
+
+// PSubscribe asks for the messages of the channels matching patterns.
+func (p *PubSub) PSubscribe(ctx context.Context, patterns ...string) error {
+	return p.send(ctx, "PSUBSCRIBE", patterns)
+}
+
+func (p *PubSub) PUnsubscribe(ctx context.Context, patterns ...string) error {
+	return p.send(ctx, "PUNSUBSCRIBE", patterns)
+}
+
+func (p *PubSub) send(
+	ctx context.Context,
+	name string,
+	channels []string,
+) error {
+	cmd := rheltypes.NewArrayFromStrings(append([]string{name}, channels...))
+
+	return p.conn.send(ctx, cmd.Serialize())
+}
+
+// Receive waits for the next message as long as the context allows.
+func (p *PubSub) Receive(ctx context.Context) (msg Message, err error) {
+	values, err := Values(p.conn.Receive(ctx))
+	if err != nil {
+		return msg, err
+	}
+
+	strs := make([]string, len(values))
+
+	for i, value := range values {
+		strs[i] = value.String()
+	}
+
+	if len(strs) == 0 {
+		return msg, fmt.Errorf("client: empty pub/sub message")
+	}
+
+	msg.Kind = strs[0]
+
+	switch msg.Kind {
+	case "pmessage":
+		if len(strs) != 4 {
+			return msg, fmt.Errorf("client: malformed pmessage %q", strs)
+		}
+
+		msg.Pattern, msg.Channel, msg.Payload = strs[1], strs[2], strs[3]
+
+		return msg, nil
+	case "psubscribe", "punsubscribe":
+		if len(strs) > 1 {
+			msg.Pattern = strs[1]
+		}
+	default:
+		if len(strs) > 1 {
+			msg.Channel = strs[1]
+		}
+	}
+
+	if len(strs) > 2 {
+		if count, isCount := values[2].(rheltypes.Integer); isCount {
+			msg.Count = int(count)
+		} else {
+			msg.Payload = strs[2]
+		}
+	}
+
+	return msg, nil
+}
+
+// Messages is the receive loop, it yields every published message until
+// ctx is done or the connection breaks, the failure being yielded last.
+// Messages matching patterns are yielded too.
+// Confirmations are skipped.
+func (p *PubSub) Messages(ctx context.Context) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		for {
+			msg, err := p.Receive(ctx)
+			if err != nil {
+				yield(msg, err)
+
+				return
+			}
+
+			if msg.Kind != "message" && msg.Kind != "pmessage" {
+				continue
+			}
+
+			if !yield(msg, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (p *PubSub) Close() error {
+	return p.conn.Close()
+}
@@ -0,0 +1,94 @@
+package client_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/client"
+	"github.com/codecrafters-io/redis-starter-go/server"
+)
+
+// startServer serves a fresh server on a local port until the test ends.
+func startServer(t *testing.T) string {
+	t.Helper()
+
+	srv, err := server.New()
+	if err != nil {
+		t.Fatalf("failed to create server: %s", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	go srv.Serve(l)
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		srv.Shutdown(ctx)
+	})
+
+	return l.Addr().String()
+}
+
+func TestPubSubPSubscribe(t *testing.T) {
+	addr := startServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := client.Dial(ctx, addr)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+
+	sub := client.NewPubSub(conn)
+	defer sub.Close()
+
+	if err = sub.PSubscribe(ctx, "news.*"); err != nil {
+		t.Fatalf("failed to psubscribe: %s", err)
+	}
+
+	msg, err := sub.Receive(ctx)
+	if err != nil {
+		t.Fatalf("failed to receive the confirmation: %s", err)
+	}
+
+	want := client.Message{Kind: "psubscribe", Pattern: "news.*", Count: 1}
+	if msg != want {
+		t.Fatalf("got confirmation %+v, want %+v", msg, want)
+	}
+
+	pub, err := client.Dial(ctx, addr)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer pub.Close()
+
+	if _, err = pub.Do(ctx, "PUBLISH", "news.tech", "hello"); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+
+	for msg, err := range sub.Messages(ctx) {
+		if err != nil {
+			t.Fatalf("failed to receive the message: %s", err)
+		}
+
+		want = client.Message{
+			Kind:    "pmessage",
+			Pattern: "news.*",
+			Channel: "news.tech",
+			Payload: "hello",
+		}
+		if msg != want {
+			t.Fatalf("got message %+v, want %+v", msg, want)
+		}
+
+		break
+	}
+}
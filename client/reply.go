@@ -0,0 +1,200 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// ErrNil is returned when decoding a null reply, e.g. GET of a missing key.
+var ErrNil = errors.New("client: nil reply")
+
+// The helpers below decode a reply into a Go value, they take the results
+// of Do directly:
+//
+//	value, err := client.String(conn.Do(ctx, "GET", "key"))
+
+// IsNull reports whether reply is a RESP2 or RESP3 null.
+func IsNull(reply rheltypes.RhelType) bool {
+	switch value := reply.(type) {
+	case nil, rheltypes.Null:
+		return true
+	case rheltypes.BulkString:
+		return value.IsNull()
+	case rheltypes.Array:
+		return value == nil
+	default:
+		return false
+	}
+}
+
+func String(reply rheltypes.RhelType, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	switch value := reply.(type) {
+	case rheltypes.BulkString, rheltypes.SimpleString, rheltypes.Integer,
+		rheltypes.Double, rheltypes.VerbatimString, rheltypes.BigNumber:
+		if IsNull(value) {
+			return "", ErrNil
+		}
+
+		return value.String(), nil
+	default:
+		return "", decodeError(reply, "string")
+	}
+}
+
+func Int(reply rheltypes.RhelType, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+
+	switch value := reply.(type) {
+	case rheltypes.Integer:
+		return int(value), nil
+	case rheltypes.BulkString, rheltypes.SimpleString:
+		if IsNull(value) {
+			return 0, ErrNil
+		}
+
+		return strconv.Atoi(value.String())
+	default:
+		return 0, decodeError(reply, "integer")
+	}
+}
+
+func Float(reply rheltypes.RhelType, err error) (float64, error) {
+	if err != nil {
+		return 0, err
+	}
+
+	switch value := reply.(type) {
+	case rheltypes.Double:
+		return float64(value), nil
+	case rheltypes.Integer:
+		return float64(value), nil
+	case rheltypes.BulkString, rheltypes.SimpleString:
+		if IsNull(value) {
+			return 0, ErrNil
+		}
+
+		double, err := rheltypes.ParseDouble(value.String())
+
+		return float64(double), err
+	default:
+		return 0, decodeError(reply, "float")
+	}
+}
+
+// Bool decodes RESP3 booleans as well as the integer and OK replies used
+// for them by RESP2.
+func Bool(reply rheltypes.RhelType, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+
+	switch value := reply.(type) {
+	case rheltypes.Boolean:
+		return bool(value), nil
+	case rheltypes.Integer:
+		return value != 0, nil
+	case rheltypes.SimpleString:
+		return value == "OK", nil
+	default:
+		if IsNull(value) {
+			return false, ErrNil
+		}
+
+		return false, decodeError(reply, "boolean")
+	}
+}
+
+// Values decodes any aggregate reply, maps are flattened into their keys
+// and values.
+func Values(
+	reply rheltypes.RhelType,
+	err error,
+) ([]rheltypes.RhelType, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := reply.(type) {
+	case rheltypes.Array:
+		if value == nil {
+			return nil, ErrNil
+		}
+
+		return value, nil
+	case rheltypes.Set:
+		return value, nil
+	case rheltypes.Push:
+		return value, nil
+	case rheltypes.Map:
+		values := make([]rheltypes.RhelType, 0, len(value)*2)
+
+		for _, entry := range value {
+			values = append(values, entry.Key, entry.Value)
+		}
+
+		return values, nil
+	default:
+		if IsNull(value) {
+			return nil, ErrNil
+		}
+
+		return nil, decodeError(reply, "aggregate")
+	}
+}
+
+// Strings decodes an aggregate reply of strings, null elements are
+// returned as empty strings.
+func Strings(reply rheltypes.RhelType, err error) ([]string, error) {
+	values, err := Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(values))
+
+	for i, value := range values {
+		if strs[i], err = String(value, nil); err != nil &&
+			!errors.Is(err, ErrNil) {
+			return nil, err
+		}
+	}
+
+	return strs, nil
+}
+
+// StringMap decodes a RESP3 map or its flattened RESP2 form.
+func StringMap(reply rheltypes.RhelType, err error) (map[string]string, error) {
+	strs, err := Strings(reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(strs)%2 != 0 {
+		return nil, fmt.Errorf("client: odd number of map elements")
+	}
+
+	m := make(map[string]string, len(strs)/2)
+
+	for i := 0; i < len(strs); i += 2 {
+		m[strs[i]] = strs[i+1]
+	}
+
+	return m, nil
+}
+
+func decodeError(reply rheltypes.RhelType, target string) error {
+	if IsNull(reply) {
+		return ErrNil
+	}
+
+	return fmt.Errorf("client: can't decode %T reply as %s", reply, target)
+}
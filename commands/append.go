@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdAppend struct {
+	BaseCommand
+}
+
+func NewCmdAppend() CmdAppend {
+	return CmdAppend{BaseCommand: BaseCommand("APPEND")}
+}
+
+func (c CmdAppend) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+	}
+}
+
+// Exec appends the value to the string at key, created empty if missing,
+// and replies the new length. The expiration of the key is kept.
+func (c CmdAppend) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	key := args.At(0).String()
+	suffix := args.At(1).String()
+	length := 0
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		text := ""
+
+		if found {
+			if text, err = stringValue(current.Value); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+		}
+
+		if len(text)+len(suffix) > maxStringLength {
+			err = errStringTooLong
+
+			return current, rheltypes.ComputeKeep
+		}
+
+		current.Value = rheltypes.NewBulkString(text + suffix)
+		length = len(text) + len(suffix)
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	session.notify(notifyString, "append", key)
+
+	return rheltypes.Integer(length), nil
+}
@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type ArgKind int
+
+const (
+	ArgString ArgKind = iota
+	ArgKey
+	ArgInteger
+	ArgFloat
+)
+
+func (k ArgKind) validate(arg rheltypes.RhelType) error {
+	switch k {
+	case ArgInteger:
+		if _, err := arg.Integer(); err != nil {
+			return rheltypes.ErrNotInteger
+		}
+	case ArgFloat:
+		if _, err := rheltypes.ParseDouble(arg.String()); err != nil {
+			return rheltypes.ErrNotFloat
+		}
+	}
+
+	return nil
+}
+
+// OptionSpec declares an optional token, e.g. PX, and the values following
+// it. A Rest option consumes every argument after its token.
+type OptionSpec struct {
+	Token  string
+	Values []ArgKind
+	Rest   bool
+}
+
+// ArgSpec declares the arguments accepted by a command. Arity and key
+// positions follow the Redis conventions: arity counts the command name and
+// a negative one is a minimum, keys are found from FirstKey to LastKey every
+// KeyStep arguments, with the command name at position 0 and a negative
+// LastKey counted from the end.
+type ArgSpec struct {
+	Arity int
+	// MaxArity bounds a negative Arity when set, e.g. for a single optional
+	// argument.
+	MaxArity int
+	FirstKey int
+	LastKey  int
+	KeyStep  int
+	// Args are the leading positional arguments, checked when present.
+	Args []ArgKind
+	// Variadic commands take any arguments after Args, other ones only
+	// accept the declared Options.
+	Variadic bool
+	Options  []OptionSpec
+}
+
+var anyArgsSpec = ArgSpec{Arity: -1, Variadic: true}
+
+// ParsedArgs holds the arguments split according to their ArgSpec.
+type ParsedArgs struct {
+	Args    rheltypes.Array
+	Rest    rheltypes.Array
+	Options map[string]rheltypes.Array
+}
+
+func (a ParsedArgs) Has(token string) bool {
+	_, found := a.Options[token]
+
+	return found
+}
+
+// Option returns the first value following token, if any.
+func (a ParsedArgs) Option(token string) (value rheltypes.RhelType) {
+	return a.Options[token].First()
+}
+
+func newArityError(name string) error {
+	return rheltypes.NewGenericError(fmt.Errorf(
+		"wrong number of arguments for '%s' command",
+		strings.ToLower(name),
+	))
+}
+
+func (s ArgSpec) checkArity(name string, args rheltypes.Array) error {
+	switch n := len(args) + 1; {
+	case s.Arity > 0 && n != s.Arity, s.Arity < 0 && n < -s.Arity,
+		s.MaxArity > 0 && n > s.MaxArity:
+		return newArityError(name)
+	default:
+		return nil
+	}
+}
+
+func (s ArgSpec) option(token string) (opt OptionSpec, found bool) {
+	for _, opt := range s.Options {
+		if opt.Token == token {
+			return opt, true
+		}
+	}
+
+	return opt, false
+}
+
+// Parse validates args against the spec and splits them into positional
+// arguments, the variadic rest and options.
+func (s ArgSpec) Parse(
+	name string,
+	args rheltypes.Array,
+) (parsed ParsedArgs, err error) {
+	if err = s.checkArity(name, args); err != nil {
+		return
+	}
+
+	n := min(len(s.Args), len(args))
+
+	for i, kind := range s.Args[:n] {
+		if err = kind.validate(args[i]); err != nil {
+			return
+		}
+	}
+
+	parsed.Args = args[:n]
+
+	if s.Variadic {
+		parsed.Rest = args[n:]
+
+		return
+	}
+
+	parsed.Options = make(map[string]rheltypes.Array, len(s.Options))
+
+	for i := n; i < len(args); i++ {
+		token := strings.ToUpper(args[i].String())
+
+		opt, found := s.option(token)
+		if !found {
+			return parsed, rheltypes.ErrSyntax
+		}
+
+		if opt.Rest {
+			parsed.Options[token] = args[i+1:]
+
+			break
+		}
+
+		if i+len(opt.Values) >= len(args) {
+			return parsed, rheltypes.ErrSyntax
+		}
+
+		values := args[i+1 : i+1+len(opt.Values)]
+
+		for j, kind := range opt.Values {
+			if err = kind.validate(values[j]); err != nil {
+				return
+			}
+		}
+
+		parsed.Options[token] = values
+		i += len(opt.Values)
+	}
+
+	return parsed, nil
+}
+
+// Keys returns the key names found in args, args don't include the command
+// name.
+func (s ArgSpec) Keys(args rheltypes.Array) (keys []string) {
+	if s.FirstKey <= 0 {
+		return nil
+	}
+
+	last := s.LastKey
+	if last < 0 {
+		last += len(args) + 1
+	}
+
+	step := max(s.KeyStep, 1)
+
+	for pos := s.FirstKey; pos <= min(last, len(args)); pos += step {
+		keys = append(keys, args[pos-1].String())
+	}
+
+	return keys
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdBitCount struct {
+	BaseCommand
+}
+
+func NewCmdBitCount() CmdBitCount {
+	return CmdBitCount{BaseCommand: BaseCommand("BITCOUNT")}
+}
+
+func (c CmdBitCount) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+		Variadic: true,
+	}
+}
+
+// Exec replies the number of bits set in the string at key, or within the
+// range given in bytes or in bits.
+func (c CmdBitCount) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	r := bitRange{Start: 0, End: -1}
+
+	switch {
+	case len(spec.Rest) == 1:
+		return nil, c.ErrWrap(rheltypes.ErrSyntax)
+	case len(spec.Rest) > 1:
+		if r, err = parseBitRange(spec.Rest); err != nil {
+			return nil, c.ErrWrap(err)
+		}
+	}
+
+	text, _, err := getString(session.DB(), spec.Args[0].String())
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	first, last, ok := r.bits(len(text))
+	if !ok {
+		return rheltypes.Integer(0), nil
+	}
+
+	return rheltypes.Integer(countBits(text, first, last)), nil
+}
@@ -0,0 +1,456 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var (
+	errBitfieldType = rheltypes.NewGenericError(errors.New(
+		"Invalid bitfield type. Use something like i16 u8. Note that u64 " +
+			"is not supported but i64 is.",
+	))
+	errBitfieldOverflow = rheltypes.NewGenericError(
+		errors.New("Invalid OVERFLOW type specified"),
+	)
+	errBitfieldReadonly = rheltypes.NewGenericError(
+		errors.New("BITFIELD_RO only supports the GET subcommand"),
+	)
+)
+
+// bitfieldSpec is shared by BITFIELD and BITFIELD_RO.
+var bitfieldSpec = ArgSpec{
+	Arity:    -2,
+	FirstKey: 1,
+	LastKey:  1,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey},
+	Variadic: true,
+}
+
+type CmdBitfield struct {
+	BaseCommand
+}
+
+func NewCmdBitfield() CmdBitfield {
+	return CmdBitfield{BaseCommand: BaseCommand("BITFIELD")}
+}
+
+func (c CmdBitfield) Spec() ArgSpec {
+	return bitfieldSpec
+}
+
+// Exec gets, sets and increments integer fields of any width up to 64 bits
+// at any offset of the string at key.
+func (c CmdBitfield) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execBitfield(c.BaseCommand, session, args, false)
+}
+
+// bitfieldOverflow is how SET and INCRBY handle values out of the range of
+// a field.
+type bitfieldOverflow int
+
+const (
+	overflowWrap bitfieldOverflow = iota
+	overflowSat
+	overflowFail
+)
+
+// bitfieldOp is a GET, SET or INCRBY of BITFIELD, on the field of Bits at
+// Offset, signed or not. Value is the one to set or the increment.
+type bitfieldOp struct {
+	Op       string
+	Signed   bool
+	Bits     int
+	Offset   int
+	Value    int64
+	Overflow bitfieldOverflow
+}
+
+// parseBitfieldOps reads the operations of BITFIELD, each one applying
+// the last OVERFLOW given before it, WRAP by default.
+func parseBitfieldOps(
+	args rheltypes.Array,
+	readonly bool,
+) (ops []bitfieldOp, err error) {
+	overflow := overflowWrap
+
+	for len(args) > 0 {
+		op := strings.ToUpper(args[0].String())
+
+		var n int
+
+		switch op {
+		case "GET":
+			n = 2
+		case "SET", "INCRBY":
+			n = 3
+		case "OVERFLOW":
+			n = 1
+		default:
+			return nil, rheltypes.ErrSyntax
+		}
+
+		if len(args) <= n {
+			return nil, rheltypes.ErrSyntax
+		}
+
+		operands := args[1 : n+1]
+		args = args[n+1:]
+
+		if op == "OVERFLOW" {
+			if overflow, err = parseOverflow(operands[0]); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		parsed := bitfieldOp{Op: op, Overflow: overflow}
+
+		parsed.Signed, parsed.Bits, err = parseBitfieldType(operands[0])
+		if err != nil {
+			return nil, err
+		}
+
+		parsed.Offset, err = parseBitfieldOffset(operands[1], parsed.Bits)
+		if err != nil {
+			return nil, err
+		}
+
+		if op != "GET" {
+			if parsed.Value, err = parseIncrement(operands[2]); err != nil {
+				return nil, err
+			}
+
+			if readonly {
+				return nil, errBitfieldReadonly
+			}
+		}
+
+		ops = append(ops, parsed)
+	}
+
+	return ops, nil
+}
+
+func parseOverflow(arg rheltypes.RhelType) (bitfieldOverflow, error) {
+	switch strings.ToUpper(arg.String()) {
+	case "WRAP":
+		return overflowWrap, nil
+	case "SAT":
+		return overflowSat, nil
+	case "FAIL":
+		return overflowFail, nil
+	default:
+		return 0, errBitfieldOverflow
+	}
+}
+
+// parseBitfieldType reads a type like i16 or u8, signed fields having up
+// to 64 bits and unsigned ones 63.
+func parseBitfieldType(
+	arg rheltypes.RhelType,
+) (signed bool, bits int, err error) {
+	text := arg.String()
+	if len(text) < 2 {
+		return false, 0, errBitfieldType
+	}
+
+	switch text[0] {
+	case 'i', 'I':
+		signed = true
+	case 'u', 'U':
+	default:
+		return false, 0, errBitfieldType
+	}
+
+	n, ok := rheltypes.ParseIntString(text[1:])
+
+	switch {
+	case !ok, n < 1, signed && n > 64, !signed && n > 63:
+		return false, 0, errBitfieldType
+	}
+
+	return signed, int(n), nil
+}
+
+// parseBitfieldOffset reads the offset of a field, in bits or, prefixed
+// with #, in fields of bits.
+func parseBitfieldOffset(arg rheltypes.RhelType, bits int) (int, error) {
+	text := arg.String()
+
+	scale := 1
+	if strings.HasPrefix(text, "#") {
+		text, scale = text[1:], bits
+	}
+
+	n, ok := rheltypes.ParseIntString(text)
+	if !ok || n < 0 || n > maxStringLength*8/rheltypes.IntString(scale) {
+		return 0, errBitOffset
+	}
+
+	offset := int(n) * scale
+	if (offset+bits-1)>>3 >= maxStringLength {
+		return 0, errBitOffset
+	}
+
+	return offset, nil
+}
+
+// execBitfield applies the operations of BITFIELD or BITFIELD_RO to the
+// string at key and replies the result of each one. Writes grow the string
+// to hold their fields, even if they fail.
+func execBitfield(
+	c BaseCommand,
+	session *Session,
+	args rheltypes.Array,
+	readonly bool,
+) (rheltypes.RhelType, error) {
+	spec, err := bitfieldSpec.Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	key := spec.Args[0].String()
+
+	ops, err := parseBitfieldOps(spec.Rest, readonly)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	// length is the size the writes need, 0 if there are none.
+	length := 0
+
+	for _, op := range ops {
+		if op.Op != "GET" {
+			length = max(length, (op.Offset+op.Bits-1)>>3+1)
+		}
+	}
+
+	replies := make(rheltypes.Array, len(ops))
+
+	if length == 0 {
+		text, _, err := getString(session.DB(), key)
+		if err != nil {
+			return nil, c.ErrWrap(err)
+		}
+
+		for i, op := range ops {
+			// Copy only the bytes holding the field.
+			start := min(op.Offset>>3, len(text))
+			end := min((op.Offset+op.Bits-1)>>3+1, len(text))
+			op.Offset -= start * 8
+
+			replies[i] = rheltypes.Integer(op.get([]byte(text[start:end])))
+		}
+
+		return replies, nil
+	}
+
+	changes := 0
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		text := ""
+
+		if found {
+			if text, err = stringValue(current.Value); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+		}
+
+		buf := make([]byte, max(len(text), length))
+		copy(buf, text)
+
+		for i, op := range ops {
+			var changed bool
+
+			if replies[i], changed = op.apply(buf); changed {
+				changes++
+			}
+		}
+
+		current.Value = rheltypes.NewBulkString(string(buf))
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	if changes > 0 {
+		session.notify(notifyString, "setbit", key)
+	}
+
+	return replies, nil
+}
+
+// apply runs op on p and returns its reply, null if a write fails.
+func (op bitfieldOp) apply(p []byte) (reply rheltypes.RhelType, changed bool) {
+	old := op.get(p)
+
+	switch op.Op {
+	case "SET":
+		value, ok := op.add(op.Value, 0)
+		if !ok {
+			return rheltypes.Null{}, false
+		}
+
+		op.set(p, value)
+
+		return rheltypes.Integer(old), true
+	case "INCRBY":
+		value, ok := op.add(old, op.Value)
+		if !ok {
+			return rheltypes.Null{}, false
+		}
+
+		op.set(p, value)
+
+		return rheltypes.Integer(value), true
+	default:
+		return rheltypes.Integer(old), false
+	}
+}
+
+// get reads the field of op from p.
+func (op bitfieldOp) get(p []byte) int64 {
+	var value uint64
+
+	for i := op.Offset; i < op.Offset+op.Bits; i++ {
+		var bit uint64
+		if i>>3 < len(p) {
+			bit = uint64(p[i>>3]>>(7-i&7)) & 1
+		}
+
+		value = value<<1 | bit
+	}
+
+	if op.Signed && op.Bits < 64 && value>>(op.Bits-1) == 1 {
+		value |= math.MaxUint64 << op.Bits
+	}
+
+	return int64(value)
+}
+
+// set writes value to the field of op in p, which has to hold it.
+func (op bitfieldOp) set(p []byte, value int64) {
+	for i := range op.Bits {
+		offset := op.Offset + i
+		mask := byte(1) << (7 - offset&7)
+
+		if uint64(value)>>(op.Bits-1-i)&1 == 1 {
+			p[offset>>3] |= mask
+		} else {
+			p[offset>>3] &^= mask
+		}
+	}
+}
+
+// add returns value increased by incr in the range of the field of op, the
+// way its OVERFLOW handles overflows. ok is false if it fails.
+func (op bitfieldOp) add(value, incr int64) (sum int64, ok bool) {
+	if op.Signed {
+		return addSignedField(value, incr, op.Bits, op.Overflow)
+	}
+
+	result, ok := addUnsignedField(uint64(value), incr, op.Bits, op.Overflow)
+
+	return int64(result), ok
+}
+
+// addUnsignedField is add for unsigned fields. A value set, added to 0,
+// overflows if it doesn't fit, negative ones included.
+func addUnsignedField(
+	value uint64,
+	incr int64,
+	bits int,
+	overflow bitfieldOverflow,
+) (uint64, bool) {
+	highest := uint64(1)<<bits - 1
+	maxIncr := int64(highest - value)
+	minIncr := -int64(value)
+
+	var limit uint64
+
+	switch {
+	case value > highest || incr > 0 && incr > maxIncr:
+		limit = highest
+	case incr < 0 && incr < minIncr:
+		limit = 0
+	default:
+		return value + uint64(incr), true
+	}
+
+	switch overflow {
+	case overflowWrap:
+		return (value + uint64(incr)) & highest, true
+	case overflowSat:
+		return limit, true
+	default:
+		return 0, false
+	}
+}
+
+// addSignedField is add for signed fields, wrapping around in two's
+// complement.
+func addSignedField(
+	value, incr int64,
+	bits int,
+	overflow bitfieldOverflow,
+) (int64, bool) {
+	highest := int64(math.MaxInt64)
+	if bits < 64 {
+		highest = 1<<(bits-1) - 1
+	}
+
+	lowest := -highest - 1
+	maxIncr := highest - value
+	minIncr := lowest - value
+
+	var limit int64
+
+	switch {
+	case value > highest,
+		bits != 64 && incr > maxIncr,
+		value >= 0 && incr > 0 && incr > maxIncr:
+		limit = highest
+	case value < lowest,
+		bits != 64 && incr < minIncr,
+		value < 0 && incr < 0 && incr < minIncr:
+		limit = lowest
+	default:
+		return value + incr, true
+	}
+
+	switch overflow {
+	case overflowWrap:
+		sum := uint64(value) + uint64(incr)
+
+		if bits < 64 {
+			mask := uint64(math.MaxUint64) << bits
+
+			if sum>>(bits-1)&1 == 1 {
+				sum |= mask
+			} else {
+				sum &^= mask
+			}
+		}
+
+		return int64(sum), true
+	case overflowSat:
+		return limit, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdBitfieldRo struct {
+	BaseCommand
+}
+
+func NewCmdBitfieldRo() CmdBitfieldRo {
+	return CmdBitfieldRo{BaseCommand: BaseCommand("BITFIELD_RO")}
+}
+
+func (c CmdBitfieldRo) Spec() ArgSpec {
+	return bitfieldSpec
+}
+
+// Exec is BITFIELD restricted to GET, so that it can run on replicas.
+func (c CmdBitfieldRo) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execBitfield(c.BaseCommand, session, args, true)
+}
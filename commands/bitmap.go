@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"errors"
+	"math/bits"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// Bitmaps are string values seen as arrays of bits, the most significant
+// bit of the first byte being bit 0.
+
+var (
+	errBitOffset = rheltypes.NewGenericError(
+		errors.New("bit offset is not an integer or out of range"),
+	)
+	errBitValue = rheltypes.NewGenericError(
+		errors.New("bit is not an integer or out of range"),
+	)
+)
+
+// parseBitOffset reads the offset of a bit, which has to fit in a string
+// of maxStringLength bytes.
+func parseBitOffset(arg rheltypes.RhelType) (int, error) {
+	n, ok := rheltypes.ParseIntString(arg.String())
+	if !ok || n < 0 || n>>3 >= maxStringLength {
+		return 0, errBitOffset
+	}
+
+	return int(n), nil
+}
+
+// parseBit reads a bit value, "0" or "1".
+func parseBit(arg rheltypes.RhelType, invalid error) (byte, error) {
+	switch arg.String() {
+	case "0":
+		return 0, nil
+	case "1":
+		return 1, nil
+	default:
+		return 0, invalid
+	}
+}
+
+// bitAt returns the bit of text at offset, 0 past its end.
+func bitAt(text string, offset int) byte {
+	if offset>>3 >= len(text) {
+		return 0
+	}
+
+	return text[offset>>3] >> (7 - offset&7) & 1
+}
+
+// bitRange is the optional range of BITCOUNT and BITPOS, in bytes unless
+// Bit is set.
+type bitRange struct {
+	Start, End int
+	Bit        bool
+}
+
+// parseBitRange reads a range from its start and end, followed by its
+// optional unit.
+func parseBitRange(args rheltypes.Array) (r bitRange, err error) {
+	if r.Start, err = args[0].Integer(); err != nil {
+		return r, rheltypes.ErrNotInteger
+	}
+
+	if r.End, err = args[1].Integer(); err != nil {
+		return r, rheltypes.ErrNotInteger
+	}
+
+	r.Bit, err = parseBitUnit(args[2:])
+
+	return r, err
+}
+
+// parseBitUnit reads the optional BYTE or BIT unit of a range.
+func parseBitUnit(args rheltypes.Array) (bit bool, err error) {
+	switch {
+	case len(args) == 0:
+		return false, nil
+	case len(args) > 1:
+		return false, rheltypes.ErrSyntax
+	}
+
+	switch strings.ToUpper(args[0].String()) {
+	case "BYTE":
+		return false, nil
+	case "BIT":
+		return true, nil
+	default:
+		return false, rheltypes.ErrSyntax
+	}
+}
+
+// bits returns the range as the offsets of its first and last bits in a
+// string of length bytes, negative offsets counting from the end. ok is
+// false if the range is empty.
+func (r bitRange) bits(length int) (first, last int, ok bool) {
+	start, end, total := r.Start, r.End, length
+	if r.Bit {
+		total *= 8
+	}
+
+	if start < 0 && end < 0 && start > end {
+		return 0, 0, false
+	}
+
+	if start < 0 {
+		start += total
+	}
+
+	if end < 0 {
+		end += total
+	}
+
+	start, end = max(start, 0), min(max(end, 0), total-1)
+
+	if start > end {
+		return 0, 0, false
+	}
+
+	if !r.Bit {
+		start, end = start*8, end*8+7
+	}
+
+	return start, end, true
+}
+
+// bitMask returns the mask of the bits of byte i within first and last.
+func bitMask(i, first, last int) byte {
+	mask := byte(0xff)
+
+	if i == first>>3 {
+		mask &= 0xff >> (first & 7)
+	}
+
+	if i == last>>3 {
+		mask &= 0xff << (7 - last&7)
+	}
+
+	return mask
+}
+
+// countBits returns the number of bits set in text from first to last.
+func countBits(text string, first, last int) (count int) {
+	for i := first >> 3; i <= last>>3; i++ {
+		count += bits.OnesCount8(text[i] & bitMask(i, first, last))
+	}
+
+	return count
+}
+
+// findBit returns the offset of the first bit of text equal to bit from
+// first to last, -1 if there is none.
+func findBit(text string, bit byte, first, last int) int {
+	for i := first >> 3; i <= last>>3; i++ {
+		b := text[i]
+		if bit == 0 {
+			b = ^b
+		}
+
+		if b &= bitMask(i, first, last); b != 0 {
+			return i*8 + bits.LeadingZeros8(b)
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"testing"
+)
+
+// The expected replies come from the examples of the Redis documentation.
+
+func TestSetBitGetBit(t *testing.T) {
+	runReplyTests(t, newTestSession(), []replyTest{
+		{[]string{"SETBIT", "mykey", "7", "1"}, ":0\r\n"},
+		{[]string{"SETBIT", "mykey", "7", "0"}, ":1\r\n"},
+		{[]string{"GET", "mykey"}, "$1\r\n\x00\r\n"},
+		{[]string{"SETBIT", "mykey", "7", "1"}, ":0\r\n"},
+		{[]string{"GETBIT", "mykey", "0"}, ":0\r\n"},
+		{[]string{"GETBIT", "mykey", "7"}, ":1\r\n"},
+		{[]string{"GETBIT", "mykey", "100"}, ":0\r\n"},
+		{[]string{"SETBIT", "grow", "17", "1"}, ":0\r\n"},
+		{[]string{"GET", "grow"}, "$3\r\n\x00\x00\x40\r\n"},
+		{[]string{"SETBIT", "mykey", "-1", "1"},
+			"-ERR bit offset is not an integer or out of range\r\n"},
+		{[]string{"SETBIT", "mykey", "0", "2"},
+			"-ERR bit is not an integer or out of range\r\n"},
+	})
+}
+
+func TestBitCount(t *testing.T) {
+	runReplyTests(t, newTestSession(), []replyTest{
+		{[]string{"SET", "mykey", "foobar"}, "+OK\r\n"},
+		{[]string{"BITCOUNT", "mykey"}, ":26\r\n"},
+		{[]string{"BITCOUNT", "mykey", "0", "0"}, ":4\r\n"},
+		{[]string{"BITCOUNT", "mykey", "1", "1"}, ":6\r\n"},
+		{[]string{"BITCOUNT", "mykey", "1", "1", "BYTE"}, ":6\r\n"},
+		{[]string{"BITCOUNT", "mykey", "5", "30", "BIT"}, ":17\r\n"},
+		{[]string{"BITCOUNT", "mykey", "-2", "-1"}, ":7\r\n"},
+		{[]string{"BITCOUNT", "mykey", "-5", "-1", "BIT"}, ":2\r\n"},
+		{[]string{"BITCOUNT", "mykey", "5", "2"}, ":0\r\n"},
+		{[]string{"BITCOUNT", "mykey", "0", "100"}, ":26\r\n"},
+		{[]string{"BITCOUNT", "missing"}, ":0\r\n"},
+	})
+}
+
+func TestBitPos(t *testing.T) {
+	runReplyTests(t, newTestSession(), []replyTest{
+		{[]string{"SET", "mykey", "\xff\xf0\x00"}, "+OK\r\n"},
+		{[]string{"BITPOS", "mykey", "0"}, ":12\r\n"},
+		{[]string{"SET", "mykey", "\x00\xff\xf0"}, "+OK\r\n"},
+		{[]string{"BITPOS", "mykey", "1", "0"}, ":8\r\n"},
+		{[]string{"BITPOS", "mykey", "1", "2"}, ":16\r\n"},
+		{[]string{"BITPOS", "mykey", "1", "2", "-1", "BYTE"}, ":16\r\n"},
+		{[]string{"BITPOS", "mykey", "1", "7", "15", "BIT"}, ":8\r\n"},
+		{[]string{"SET", "mykey", "\x00\x00\x00"}, "+OK\r\n"},
+		{[]string{"BITPOS", "mykey", "1"}, ":-1\r\n"},
+		{[]string{"BITPOS", "mykey", "1", "7", "-3", "BIT"}, ":-1\r\n"},
+
+		// Looking for a clear bit among set ones finds the first bit past
+		// the string, unless the end of the range is given.
+		{[]string{"SET", "ones", "\xff\xff\xff"}, "+OK\r\n"},
+		{[]string{"BITPOS", "ones", "0"}, ":24\r\n"},
+		{[]string{"BITPOS", "ones", "0", "1"}, ":24\r\n"},
+		{[]string{"BITPOS", "ones", "0", "0", "-1"}, ":-1\r\n"},
+		{[]string{"BITPOS", "ones", "0", "0", "-1", "BIT"}, ":-1\r\n"},
+		{[]string{"BITPOS", "ones", "1", "2", "1"}, ":-1\r\n"},
+		{[]string{"BITPOS", "missing", "0"}, ":0\r\n"},
+		{[]string{"BITPOS", "missing", "1"}, ":-1\r\n"},
+	})
+}
+
+func TestBitOp(t *testing.T) {
+	runReplyTests(t, newTestSession(), []replyTest{
+		{[]string{"SET", "key1", "foobar"}, "+OK\r\n"},
+		{[]string{"SET", "key2", "abcdef"}, "+OK\r\n"},
+		{[]string{"BITOP", "AND", "dest", "key1", "key2"}, ":6\r\n"},
+		{[]string{"GET", "dest"}, "$6\r\n`bc`ab\r\n"},
+		{[]string{"SET", "a", "\xf0"}, "+OK\r\n"},
+		{[]string{"SET", "b", "\x0f\x01"}, "+OK\r\n"},
+		{[]string{"BITOP", "OR", "dest", "a", "b"}, ":2\r\n"},
+		{[]string{"GET", "dest"}, "$2\r\n\xff\x01\r\n"},
+		{[]string{"BITOP", "XOR", "dest", "a", "b", "a"}, ":2\r\n"},
+		{[]string{"GET", "dest"}, "$2\r\n\x0f\x01\r\n"},
+		{[]string{"BITOP", "AND", "dest", "a", "b"}, ":2\r\n"},
+		{[]string{"GET", "dest"}, "$2\r\n\x00\x00\r\n"},
+		{[]string{"BITOP", "NOT", "dest", "a"}, ":1\r\n"},
+		{[]string{"GET", "dest"}, "$1\r\n\x0f\r\n"},
+		{[]string{"BITOP", "NOT", "dest", "a", "b"},
+			"-ERR BITOP NOT must be called with a single source key.\r\n"},
+		{[]string{"BITOP", "AND", "dest", "missing"}, ":0\r\n"},
+		{[]string{"EXISTS", "dest"}, ":0\r\n"},
+	})
+}
+
+func TestBitfield(t *testing.T) {
+	runReplyTests(t, newTestSession(), []replyTest{
+		{[]string{"BITFIELD", "mykey", "INCRBY", "i5", "100", "1",
+			"GET", "u4", "0"}, "*2\r\n:1\r\n:0\r\n"},
+
+		// The overflow example of the documentation, run four times.
+		{[]string{"BITFIELD", "ov", "INCRBY", "u2", "100", "1",
+			"OVERFLOW", "SAT", "INCRBY", "u2", "102", "1"},
+			"*2\r\n:1\r\n:1\r\n"},
+		{[]string{"BITFIELD", "ov", "INCRBY", "u2", "100", "1",
+			"OVERFLOW", "SAT", "INCRBY", "u2", "102", "1"},
+			"*2\r\n:2\r\n:2\r\n"},
+		{[]string{"BITFIELD", "ov", "INCRBY", "u2", "100", "1",
+			"OVERFLOW", "SAT", "INCRBY", "u2", "102", "1"},
+			"*2\r\n:3\r\n:3\r\n"},
+		{[]string{"BITFIELD", "ov", "INCRBY", "u2", "100", "1",
+			"OVERFLOW", "SAT", "INCRBY", "u2", "102", "1"},
+			"*2\r\n:0\r\n:3\r\n"},
+		{[]string{"BITFIELD", "ov", "OVERFLOW", "FAIL",
+			"INCRBY", "u2", "102", "1"}, "*1\r\n$-1\r\n"},
+
+		{[]string{"BITFIELD", "f", "SET", "u8", "#1", "255",
+			"GET", "u8", "8", "GET", "u16", "0"},
+			"*3\r\n:0\r\n:255\r\n:255\r\n"},
+		{[]string{"BITFIELD_RO", "f", "GET", "i8", "8"}, "*1\r\n:-1\r\n"},
+		{[]string{"BITFIELD", "f", "GET", "u64", "0"},
+			"-ERR Invalid bitfield type. Use something like i16 u8. " +
+				"Note that u64 is not supported but i64 is.\r\n"},
+	})
+}
+
+func TestBitfieldOverflow64(t *testing.T) {
+	const (
+		maxI64 = "9223372036854775807"
+		minI64 = "-9223372036854775808"
+		maxU63 = "9223372036854775807"
+	)
+
+	runReplyTests(t, newTestSession(), []replyTest{
+		{[]string{"BITFIELD", "i", "SET", "i64", "0", maxI64},
+			"*1\r\n:0\r\n"},
+		{[]string{"BITFIELD", "i", "INCRBY", "i64", "0", "1"},
+			"*1\r\n:" + minI64 + "\r\n"},
+		{[]string{"BITFIELD", "i", "OVERFLOW", "SAT",
+			"INCRBY", "i64", "0", "-1"}, "*1\r\n:" + minI64 + "\r\n"},
+		{[]string{"BITFIELD", "i", "OVERFLOW", "FAIL",
+			"INCRBY", "i64", "0", "-1"}, "*1\r\n$-1\r\n"},
+		{[]string{"BITFIELD", "i", "OVERFLOW", "WRAP",
+			"INCRBY", "i64", "0", "-1"}, "*1\r\n:" + maxI64 + "\r\n"},
+		{[]string{"BITFIELD", "i", "OVERFLOW", "SAT",
+			"INCRBY", "i64", "0", "1"}, "*1\r\n:" + maxI64 + "\r\n"},
+
+		{[]string{"BITFIELD", "u", "SET", "u63", "0", maxU63},
+			"*1\r\n:0\r\n"},
+		{[]string{"BITFIELD", "u", "OVERFLOW", "FAIL",
+			"INCRBY", "u63", "0", "1"}, "*1\r\n$-1\r\n"},
+		{[]string{"BITFIELD", "u", "OVERFLOW", "SAT",
+			"INCRBY", "u63", "0", "1"}, "*1\r\n:" + maxU63 + "\r\n"},
+		{[]string{"BITFIELD", "u", "INCRBY", "u63", "0", "1"},
+			"*1\r\n:0\r\n"},
+		{[]string{"BITFIELD", "u", "OVERFLOW", "SAT",
+			"INCRBY", "u63", "0", "-1"}, "*1\r\n:0\r\n"},
+		{[]string{"BITFIELD", "u", "OVERFLOW", "WRAP",
+			"INCRBY", "u63", "0", "-1"}, "*1\r\n:" + maxU63 + "\r\n"},
+		{[]string{"BITFIELD", "u", "OVERFLOW", "FAIL",
+			"INCRBY", "u63", "0", minI64}, "*1\r\n$-1\r\n"},
+	})
+}
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var (
+	errBitOpNot = rheltypes.NewGenericError(errors.New(
+		"BITOP NOT must be called with a single source key.",
+	))
+	errBitOpDiff = rheltypes.NewGenericError(errors.New(
+		"BITOP DIFF, DIFF1 and ANDOR must be called with at least two " +
+			"source keys.",
+	))
+)
+
+type CmdBitOp struct {
+	BaseCommand
+}
+
+func NewCmdBitOp() CmdBitOp {
+	return CmdBitOp{BaseCommand: BaseCommand("BITOP")}
+}
+
+func (c CmdBitOp) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -4,
+		FirstKey: 2,
+		LastKey:  -1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgString, ArgKey, ArgKey},
+		Variadic: true,
+	}
+}
+
+// Exec stores at the destination key the bitwise operation of the strings
+// at the source keys and replies its length, the one of the longest source.
+// Shorter and missing sources are padded with zero bytes. An empty result
+// deletes the destination.
+//
+// Besides AND, OR, XOR and NOT, DIFF keeps the bits of the first source set
+// in none of the others, DIFF1 the bits set in any of the others but not in
+// the first one, ANDOR the bits of the first source set in any of the
+// others and ONE the bits set in exactly one source.
+func (c CmdBitOp) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	op := strings.ToUpper(spec.Args[0].String())
+	dest := spec.Args[1].String()
+	keys := args[2:]
+
+	switch op {
+	case "AND", "OR", "XOR", "ONE":
+	case "NOT":
+		if len(keys) != 1 {
+			return nil, c.ErrWrap(errBitOpNot)
+		}
+	case "DIFF", "DIFF1", "ANDOR":
+		if len(keys) < 2 {
+			return nil, c.ErrWrap(errBitOpDiff)
+		}
+	default:
+		return nil, c.ErrWrap(rheltypes.ErrSyntax)
+	}
+
+	sources := make([]string, len(keys))
+
+	for i, key := range keys {
+		sources[i], _, err = getString(session.DB(), key.String())
+		if err != nil {
+			return nil, c.ErrWrap(err)
+		}
+	}
+
+	result := bitOp(op, sources)
+
+	if len(result) == 0 {
+		deleteKeys(session, rheltypes.Array{spec.Args[1]})
+
+		return rheltypes.Integer(0), nil
+	}
+
+	CmdSetArgs{
+		Key:   dest,
+		Value: rheltypes.NewBulkString(string(result)),
+	}.set(session)
+
+	return rheltypes.Integer(len(result)), nil
+}
+
+// bitOp returns the result of the operation op of BITOP over sources.
+func bitOp(op string, sources []string) []byte {
+	length := 0
+
+	for _, source := range sources {
+		length = max(length, len(source))
+	}
+
+	result := make([]byte, length)
+	first, others := sources[0], sources[1:]
+
+	switch op {
+	case "AND":
+		copy(result, first)
+
+		for _, source := range others {
+			for i := range result {
+				if i < len(source) {
+					result[i] &= source[i]
+				} else {
+					result[i] = 0
+				}
+			}
+		}
+	case "OR":
+		orBytes(result, sources)
+	case "XOR":
+		for _, source := range sources {
+			for i := range len(source) {
+				result[i] ^= source[i]
+			}
+		}
+	case "NOT":
+		for i := range result {
+			result[i] = ^first[i]
+		}
+	case "DIFF", "DIFF1", "ANDOR":
+		union := make([]byte, length)
+		orBytes(union, others)
+		copy(result, first)
+
+		for i := range result {
+			switch op {
+			case "DIFF":
+				result[i] &^= union[i]
+			case "DIFF1":
+				result[i] = ^result[i] & union[i]
+			case "ANDOR":
+				result[i] &= union[i]
+			}
+		}
+	case "ONE":
+		many := make([]byte, length)
+
+		for _, source := range sources {
+			for i := range len(source) {
+				many[i] |= result[i] & source[i]
+				result[i] |= source[i]
+			}
+		}
+
+		for i := range result {
+			result[i] &^= many[i]
+		}
+	}
+
+	return result
+}
+
+// orBytes sets in dst the bits set in any of sources.
+func orBytes(dst []byte, sources []string) {
+	for _, source := range sources {
+		for i := range len(source) {
+			dst[i] |= source[i]
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var errBitPosValue = rheltypes.NewGenericError(
+	errors.New("The bit argument must be 1 or 0."),
+)
+
+type CmdBitPos struct {
+	BaseCommand
+}
+
+func NewCmdBitPos() CmdBitPos {
+	return CmdBitPos{BaseCommand: BaseCommand("BITPOS")}
+}
+
+func (c CmdBitPos) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+		Variadic: true,
+	}
+}
+
+// Exec replies the offset of the first bit of the string at key equal to
+// the given one, within the range given in bytes or in bits, -1 if there
+// is none. Without an end, the string is seen as padded with zero bits,
+// so the first clear bit is found past its end if need be.
+func (c CmdBitPos) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	bit, err := parseBit(spec.Args[1], errBitPosValue)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	r := bitRange{Start: 0, End: -1}
+	endGiven := len(spec.Rest) > 1
+
+	switch {
+	case len(spec.Rest) == 1:
+		if r.Start, err = spec.Rest[0].Integer(); err != nil {
+			return nil, c.ErrWrap(rheltypes.ErrNotInteger)
+		}
+	case endGiven:
+		if r, err = parseBitRange(spec.Rest); err != nil {
+			return nil, c.ErrWrap(err)
+		}
+	}
+
+	text, found, err := getString(session.DB(), spec.Args[0].String())
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	switch {
+	case !found && bit == 1:
+		return rheltypes.Integer(-1), nil
+	case !found:
+		return rheltypes.Integer(0), nil
+	}
+
+	first, last, ok := r.bits(len(text))
+	if !ok {
+		return rheltypes.Integer(-1), nil
+	}
+
+	pos := findBit(text, bit, first, last)
+	if pos < 0 && bit == 0 && !endGiven {
+		pos = len(text) * 8
+	}
+
+	return rheltypes.Integer(pos), nil
+}
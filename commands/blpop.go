@@ -2,7 +2,7 @@ package commands
 
 import (
 	"fmt"
-	"log"
+	"math"
 
 	"github.com/codecrafters-io/redis-starter-go/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
@@ -16,29 +16,116 @@ func NewCmdBLPop() CmdBLPop {
 	return CmdBLPop{BaseCommand: BaseCommand("BLPOP")}
 }
 
+func (c CmdBLPop) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  -2,
+		KeyStep:  1,
+		Variadic: true,
+	}
+}
+
 const milisecondInSecond = 1000
 
+// Exec pops the head of the first non empty list among the keys, waiting
+// up to the timeout, in seconds, for one to be pushed to otherwise. It
+// replies a null array when it times out. Within a transaction it replies
+// at once, the transaction holding its locks.
 func (c CmdBLPop) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
-	key := args.At(0).String()
+	keys := args[:len(args)-1]
 
-	timeout, _ := args.At(1).Float()
+	timeout, err := rheltypes.ParseDouble(args.At(-1).String())
+	if err != nil {
+		return nil, rheltypes.NewGenericError(
+			fmt.Errorf("timeout is not a float or out of range"),
+		)
+	} else if timeout < 0 {
+		return nil, rheltypes.NewGenericError(
+			fmt.Errorf("timeout is negative"),
+		)
+	}
 
-	lastMsg, err := pubsub.ReadLast(key, int(timeout*milisecondInSecond))
+	if session.Transaction() != nil {
+		switch value, err = popFirstList(session, keys); {
+		case err != nil:
+			return nil, c.ErrWrap(err)
+		case value == nil:
+			return rheltypes.NullArray{}, nil
+		default:
+			return value, nil
+		}
+	}
 
-	if err != nil {
-		return nil, c.ErrWrap(fmt.Errorf("failed to read last: %w", err))
-	} else if lastMsg == nil {
-		log.Println("returning null string")
+	// Waiting on the keys starts before they are first checked, so that no
+	// push is missed in between.
+	wake := make(chan struct{}, 1)
+
+	for _, key := range keys {
+		sub := session.Instance().Broker().Subscribe(key.String(), true)
+		defer sub.Close()
 
-		return rheltypes.NewNullBulkString(), nil
+		go func() {
+			for range sub.Messages {
+				select {
+				case wake <- struct{}{}:
+				default:
+				}
+			}
+		}()
 	}
 
-	last := lastMsg.(rheltypes.RhelType)
+	ctx, cancel := pubsub.CreateContextFromTimeout(
+		int(math.Ceil(timeout * milisecondInSecond)),
+	)
+	defer cancel()
+
+	var set keyLockSet
+
+	for _, key := range keys {
+		set.add(session.SelectedDB(), key.String())
+	}
+
+	for {
+		unlock := session.instance.locks.lock(set)
+		value, err = popFirstList(session, keys)
+		unlock()
+
+		if value != nil || err != nil {
+			return value, c.ErrWrap(err)
+		}
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return rheltypes.NullArray{}, nil
+		}
+	}
+}
+
+// popFirstList pops the head of the first non empty list among keys and
+// replies it along with its key, nil if they are all empty.
+func popFirstList(
+	session *Session,
+	keys rheltypes.Array,
+) (rheltypes.RhelType, error) {
+	for _, key := range keys {
+		popped, left, err := popList(session, key.String(), 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if popped == nil {
+			continue
+		}
+
+		signalList(session, key.String(), left)
+
+		return rheltypes.Array{key, popped[0]}, nil
+	}
 
-	return rheltypes.Array{
-		rheltypes.NewBulkString(key),
-		last,
-	}, nil
+	return nil, nil
 }
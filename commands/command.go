@@ -4,47 +4,14 @@ import (
 	"encoding/hex"
 	"fmt"
 	"iter"
+	"maps"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
-const (
-	defaultMapCapacity        = 1024
-	defaultMapCleanupInterval = 1 * time.Minute
-)
-
-var (
-	dataMap    *rheltypes.SafeMap
-	configMap  *rheltypes.SafeMap
-	dataOnce   sync.Once
-	configOnce sync.Once
-)
-
-func GetDataMapInstance() *rheltypes.SafeMap {
-	dataOnce.Do(func() {
-		dataMap = rheltypes.NewSafeMap(defaultMapCleanupInterval)
-	})
-
-	return dataMap
-}
-
-func GetConfigMapInstance() *rheltypes.SafeMap {
-	configOnce.Do(func() {
-		configMap = rheltypes.NewSafeMap(0)
-	})
-
-	return configMap
-}
-
-func CloseMaps() {
-	GetConfigMapInstance().Close()
-	GetDataMapInstance().Close()
-}
-
 type CommandError struct {
 	content []byte
 	message error
@@ -59,10 +26,15 @@ type RhelCommand interface {
 	isRhelCommand()
 	Name() string
 	ErrWrap(input error) error
-	Exec(args rheltypes.Array) (rheltypes.RhelType, error)
-	Resend() bool
-	ReplicaRespond() bool
-	AllowedInSubscription() bool
+	// Exec runs the command against its arguments. Failures meant for the
+	// client are rheltypes.Error values, returned either as the reply or
+	// anywhere in the error chain, and keep their code, e.g. -WRONGTYPE.
+	// Any other error is sent back as a generic -ERR reply. The session
+	// gives access to the client and the server instance.
+	Exec(session *Session, args rheltypes.Array) (rheltypes.RhelType, error)
+	// Spec declares the accepted arguments, they are validated against it
+	// before Exec is called.
+	Spec() ArgSpec
 }
 
 type BaseCommand string
@@ -80,76 +52,32 @@ func (c BaseCommand) ErrWrap(input error) (err error) {
 }
 
 func (c BaseCommand) Exec(
+	session *Session,
 	value rheltypes.Array,
 ) (rheltypes.RhelType, error) {
-	return nil, c.ErrWrap(fmt.Errorf("command %q not found", c.Name()))
+	return nil, newUnknownCommandError(c.Name(), value)
 }
 
-func (c BaseCommand) Resend() bool { return false }
-
-func (c BaseCommand) ReplicaRespond() bool { return false }
-
-func (c BaseCommand) AllowedInSubscription() bool { return false }
+func (c BaseCommand) Spec() ArgSpec { return anyArgsSpec }
 
 func (BaseCommand) isRhelCommand() {}
 
-var commandMap = map[string]func() RhelCommand{
-	"BLPOP":       func() RhelCommand { return NewCmdBLPop() },
-	"CONFIG":      func() RhelCommand { return NewCmdConfig() },
-	"DISCARD":     func() RhelCommand { return NewCmdDiscard() },
-	"ECHO":        func() RhelCommand { return NewCmdEcho() },
-	"EXEC":        func() RhelCommand { return NewCmdExec() },
-	"GET":         func() RhelCommand { return NewCmdGet() },
-	"INCR":        func() RhelCommand { return NewCmdIncr() },
-	"INFO":        func() RhelCommand { return NewCmdInfo() },
-	"KEYS":        func() RhelCommand { return NewCmdKeys() },
-	"LLEN":        func() RhelCommand { return NewCmdLLen() },
-	"LPOP":        func() RhelCommand { return NewCmdLPop() },
-	"LPUSH":       func() RhelCommand { return NewCmdLPush() },
-	"LRANGE":      func() RhelCommand { return NewCmdLRange() },
-	"MULTI":       func() RhelCommand { return NewCmdMulti() },
-	"PING":        func() RhelCommand { return NewCmdPing() },
-	"PSYNC":       func() RhelCommand { return NewCmdPsync() },
-	"PUBLISH":     func() RhelCommand { return NewCmdPublish() },
-	"REPLCONF":    func() RhelCommand { return NewCmdReplconf() },
-	"RPUSH":       func() RhelCommand { return NewCmdRPush() },
-	"SET":         func() RhelCommand { return NewCmdSet() },
-	"SUBSCRIBE":   func() RhelCommand { return NewCmdSubscribe() },
-	"TYPE":        func() RhelCommand { return NewCmdType() },
-	"UNSUBSCRIBE": func() RhelCommand { return NewCmdUnsubscribe() },
-	"WAIT":        func() RhelCommand { return NewCmdWait() },
-	"XADD":        func() RhelCommand { return NewCmdXAdd() },
-	"XRANGE":      func() RhelCommand { return NewCmdXRange() },
-	"XREAD":       func() RhelCommand { return NewCmdXRead() },
-	"ZADD":        func() RhelCommand { return NewCmdZAdd() },
-	"ZCARD":       func() RhelCommand { return NewCmdZCard() },
-	"ZRANGE":      func() RhelCommand { return NewCmdZRange() },
-	"ZRANK":       func() RhelCommand { return NewCmdZRank() },
-	"ZREM":        func() RhelCommand { return NewCmdZRem() },
-	"ZSCORE":      func() RhelCommand { return NewCmdZScore() },
-}
-
-func NewRhelCommand(name string) RhelCommand {
-	if factory, exists := commandMap[strings.ToUpper(name)]; exists {
-		return factory()
-	}
-
-	return BaseCommand(name)
-}
-
 type ParsedCommand struct {
-	cmd   RhelCommand
-	args  rheltypes.Array
-	err   error
-	size  int
-	ack   int
-	multi bool
-	exec  bool
-	sub   bool
+	cmd     RhelCommand
+	entry   *CommandEntry
+	session *Session
+	args    rheltypes.Array
+	raw     []byte
+	err     error
+	size    int
+	ack     int
+	multi   bool
+	exec    bool
+	sub     bool
 }
 
 func newParsedCommandErr(err error) (parsed *ParsedCommand) {
-	parsed = &ParsedCommand{err: fmt.Errorf("failed to parse command: %w", err)}
+	parsed = &ParsedCommand{err: err}
 
 	return
 }
@@ -158,13 +86,13 @@ func newParsedCommandFromArray(args rheltypes.Array) (parsed *ParsedCommand) {
 	parsed = &ParsedCommand{
 		cmd:  NewRhelCommand(args[0].String()),
 		args: args[1:],
-		size: args.Size(),
 	}
+	parsed.entry, _ = commandRegistry.Lookup(parsed.cmd.Name())
 
 	switch parsed.cmd.(type) {
 	case CmdReplconf:
-		if parsed.args.Cmd() == "ACK" {
-			parsed.ack, parsed.err = parsed.args.At(1).Integer()
+		if ack := parsed.args.At(1); parsed.args.Cmd() == "ACK" && ack != nil {
+			parsed.ack, parsed.err = ack.Integer()
 		}
 	case CmdMulti:
 		parsed.multi = true
@@ -179,45 +107,44 @@ func newParsedCommandFromArray(args rheltypes.Array) (parsed *ParsedCommand) {
 func newParsedCommand(raw rheltypes.RhelType) (parsed *ParsedCommand) {
 	switch value := raw.(type) {
 	case rheltypes.Array:
+		if len(value) == 0 {
+			return nil
+		}
+
 		parsed = newParsedCommandFromArray(value)
 
 	case rheltypes.SimpleString, rheltypes.BulkString:
 
 	default:
-		parsed = newParsedCommandErr(
+		parsed = newParsedCommandErr(rheltypes.NewProtocolError(
+			fmt.Sprintf("expected '*', got '%c'", value.Serialize()[0]),
 			fmt.Errorf("expected array, got %T", value),
-		)
+		))
 	}
 
 	return
 }
 
-func newParsedCommandFromBytes(
-	command []byte,
+func newParsedCommandFromParser(
+	parser *rheltypes.Parser,
 ) iter.Seq[*ParsedCommand] {
 	return func(yield func(*ParsedCommand) bool) {
-		tokens := rheltypes.NewTokenIterator(command)
-
-		for {
-			rawValue, err := rheltypes.RhelEncode(tokens)
+		for frame, err := range parser.Frames() {
 			if err != nil {
-				yield(
-					newParsedCommandErr(fmt.Errorf("encoding error: %w", err)),
-				)
-
-				return
-			}
+				yield(newParsedCommandErr(err))
 
-			if tokens.IsDone() {
 				return
 			}
 
-			parsed := newParsedCommand(rawValue)
+			parsed := newParsedCommand(frame.Value)
 
 			if parsed == nil {
 				continue
 			}
 
+			parsed.raw = frame.Raw
+			parsed.size = len(frame.Raw)
+
 			if !yield(parsed) || parsed.err != nil {
 				return
 			}
@@ -225,18 +152,36 @@ func newParsedCommandFromBytes(
 	}
 }
 
-func (p *ParsedCommand) Commit(t **Transaction) (err error) {
+func (p *ParsedCommand) Commit(session *Session) (err error) {
+	t := &session.transaction
+
+	p.session = session
+
 	switch p.cmd.(type) {
 	case CmdMulti:
 		*t = NewTransaction()
-	case CmdSubscribe:
+	case CmdSubscribe, CmdPSubscribe:
 		if *t == nil {
 			*t = NewTransaction()
 		}
 
 		p.sub = true
 	case CmdUnsubscribe:
-		p.args.Append(rheltypes.Integer((*t).subscriptions[p.args.First().String()]))
+		if *t == nil {
+			break
+		}
+
+		if id, ok := (*t).subscriptions[p.args.First().String()]; ok {
+			p.args.Append(rheltypes.Integer(id))
+		}
+	case CmdPUnsubscribe:
+		if *t == nil {
+			break
+		}
+
+		if id, ok := (*t).patterns[p.args.First().String()]; ok {
+			p.args.Append(rheltypes.Integer(id))
+		}
 	case CmdDiscard:
 		if *t == nil {
 			p.args = nil
@@ -244,10 +189,17 @@ func (p *ParsedCommand) Commit(t **Transaction) (err error) {
 
 		*t = nil
 	case CmdExec:
-		if *t != nil {
-			_, p.args, err = (*t).Exec()
-		} else {
+		if *t == nil {
 			p.args = nil
+		} else if (*t).aborted {
+			err = rheltypes.ErrExecAbort
+		} else {
+			set := (*t).keyLockSet(session.SelectedDB())
+			unlock := session.instance.locks.lock(set)
+
+			_, p.args, err = (*t).Exec()
+
+			unlock()
 		}
 
 		*t = nil
@@ -268,32 +220,87 @@ func (p *ParsedCommand) Exec(t **Transaction) (result *CommandResult) {
 
 	cmd := p.cmd
 
-	result.result, result.Err = cmd.Exec(p.args)
-	if err := result.Err; err != nil {
-		result.Err = fmt.Errorf(
-			"failed to run command %s: %w",
-			cmd.Name(),
-			err,
-		)
+	reply, err := p.run()
+	if err != nil {
+		result.result = newErrorReply(err)
 
 		return result
 	}
 
+	result.result = reply
+
 	if *t != nil {
 		(*t).digestSubscription(cmd, result)
 		result.Sub = (*t).SubStart
 	}
 
-	result.Resend = cmd.Resend()
-	result.ReplicaRespond = cmd.ReplicaRespond()
+	result.Resend = p.entry.Propagated() && result.Succeeded()
+	result.ReplicaRespond = p.entry.Has(flagMasterReply)
+	result.Command = p.raw
 	result.Size = p.size
 	result.Ack = p.ack
 
 	return result
 }
 
+// keyLockSet returns the locks the command holds to run atomically in the
+// database db. Blocking commands would hold them while waiting, they lock
+// their keys themselves while not.
+func (p *ParsedCommand) keyLockSet(db int) (set keyLockSet) {
+	if p.entry != nil && p.entry.Has(FlagBlocking) {
+		return set
+	}
+
+	return p.queuedLockSet(db)
+}
+
+// queuedLockSet returns the locks the command holds within a transaction,
+// where blocking commands don't block.
+func (p *ParsedCommand) queuedLockSet(db int) (set keyLockSet) {
+	switch {
+	case p.entry == nil:
+		return set
+	case p.entry.Has(flagExclusive):
+		set.exclusive = true
+
+		return set
+	}
+
+	for _, key := range p.cmd.Spec().Keys(p.args) {
+		set.add(db, key)
+	}
+
+	return set
+}
+
+// verify checks whether the command can run for the session at all, before
+// it is executed or queued in a transaction.
+func (p *ParsedCommand) verify(session *Session) error {
+	if p.entry == nil {
+		return newUnknownCommandError(p.cmd.Name(), p.args)
+	}
+
+	if _, err := p.cmd.Spec().Parse(p.cmd.Name(), p.args); err != nil {
+		return err
+	}
+
+	if err := session.verifyWrite(p.entry); err != nil {
+		return err
+	}
+
+	return session.verifyMemory(p.entry)
+}
+
+// run executes the command, failures of any kind are returned as errors and
+// are meant to be sent back to the client as error replies.
+func (p *ParsedCommand) run() (reply rheltypes.RhelType, err error) {
+	defer recoverCommand(p.cmd, &err)
+
+	return p.cmd.Exec(p.session, p.args)
+}
+
 func (p *ParsedCommand) verifySubscription(t **Transaction) bool {
-	return p.cmd.AllowedInSubscription() || *t == nil || !(*t).IsSubscribed()
+	return p.entry.Has(flagSubscribed) || *t == nil || !(*t).IsSubscribed()
 }
 
 func (p *ParsedCommand) newErrorForbiddenCommand() rheltypes.Error {
@@ -307,13 +314,16 @@ func (p *ParsedCommand) newErrorForbiddenCommand() rheltypes.Error {
 
 type CommandResult struct {
 	result         rheltypes.RhelType
+	Command        []byte
 	KeepConnection bool
 	Resend         bool
 	ReplicaRespond bool
-	Err            error
 	Size           int
 	Ack            int
 	Sub            bool
+	// Err is set when the connection can't be used anymore, e.g. after a
+	// protocol error, the reply is still meant to be sent before closing.
+	Err error
 }
 
 func newCommandResultQueued() (result *CommandResult) {
@@ -322,16 +332,31 @@ func newCommandResultQueued() (result *CommandResult) {
 	return
 }
 
+// NewCommandErrorResponse replies a request that can't be run, a protocol
+// error is replied with its short reason only, its details are left to the
+// server log through Err.
 func NewCommandErrorResponse(content []byte, message error) *CommandResult {
-	return &CommandResult{Err: CommandError{content: content, message: message}}
+	return &CommandResult{
+		result: newProtocolErrorReply(message),
+		Err:    CommandError{content: content, message: message},
+	}
 }
 
-func (r CommandResult) Serialize() []byte {
-	if r.result == nil {
-		return nil
-	}
+func newCommandResultError(err error) (result *CommandResult) {
+	result = &CommandResult{result: newErrorReply(err)}
+
+	return
+}
+
+// Succeeded reports whether the command ran without replying with an error.
+func (r CommandResult) Succeeded() bool {
+	_, failed := r.result.(rheltypes.Error)
+
+	return r.Err == nil && !failed
+}
 
-	return r.result.Serialize()
+func (r CommandResult) Serialize(proto rheltypes.Protocol) []byte {
+	return rheltypes.SerializeProtocol(r.result, proto)
 }
 
 const defaultTransactionCapacity = 16
@@ -339,7 +364,9 @@ const defaultTransactionCapacity = 16
 type Transaction struct {
 	cmds          []*ParsedCommand
 	subscriptions map[string]int
+	patterns      map[string]int
 	lock          sync.RWMutex
+	aborted       bool
 	SubStart      bool
 }
 
@@ -347,6 +374,7 @@ func NewTransaction() *Transaction {
 	return &Transaction{
 		cmds:          make([]*ParsedCommand, 0, defaultTransactionCapacity),
 		subscriptions: make(map[string]int, defaultTransactionCapacity),
+		patterns:      make(map[string]int),
 	}
 }
 
@@ -357,12 +385,6 @@ func (t *Transaction) Exec() (results []*CommandResult, responses rheltypes.Arra
 	for i, c := range t.cmds {
 		r := c.Exec(&t)
 
-		if r.Err != nil {
-			err = r.Err
-
-			return
-		}
-
 		results[i] = r
 		responses[i] = r.result
 	}
@@ -370,6 +392,21 @@ func (t *Transaction) Exec() (results []*CommandResult, responses rheltypes.Arra
 	return
 }
 
+// keyLockSet returns the locks held while the queued commands run, every
+// one of them in the database db. A transaction changing the selected
+// database runs alone.
+func (t *Transaction) keyLockSet(db int) (set keyLockSet) {
+	for _, c := range t.cmds {
+		if _, isSelect := c.cmd.(CmdSelect); isSelect {
+			set.exclusive = true
+		}
+
+		set.merge(c.queuedLockSet(db))
+	}
+
+	return set
+}
+
 func (t *Transaction) IsSubscribed() bool {
 	if t == nil {
 		return false
@@ -381,15 +418,27 @@ func (t *Transaction) IsSubscribed() bool {
 	return t.numSubscriptions() > 0
 }
 
-func (t *Transaction) IterSubscriptions() iter.Seq2[string, *pubsub.Subscription] {
+// IterSubscriptions yields the subscriptions to channels then to patterns,
+// named after their channel or pattern, as they were when called.
+func (t *Transaction) IterSubscriptions(
+	broker *pubsub.StreamManager,
+) iter.Seq2[string, *pubsub.Subscription] {
 	t.lock.RLock()
-	defer t.lock.RUnlock()
+	subscriptions := maps.Clone(t.subscriptions)
+	patterns := maps.Clone(t.patterns)
+	t.lock.RUnlock()
 
 	return func(yield func(string, *pubsub.Subscription) bool) {
-		st := pubsub.GetStreamManager()
+		for name, id := range subscriptions {
+			sub := broker.GetSubscription(name, id)
+			if sub != nil && !yield(name, sub) {
+				return
+			}
+		}
 
-		for name, id := range t.subscriptions {
-			if !yield(name, st.GetSubscription(name, id)) {
+		for pattern, id := range patterns {
+			sub := broker.GetPatternSubscription(pattern, id)
+			if sub != nil && !yield(pattern, sub) {
 				return
 			}
 		}
@@ -397,7 +446,7 @@ func (t *Transaction) IterSubscriptions() iter.Seq2[string, *pubsub.Subscription
 }
 
 func (t *Transaction) numSubscriptions() int {
-	return len(t.subscriptions)
+	return len(t.subscriptions) + len(t.patterns)
 }
 
 func (t *Transaction) digestSubscription(
@@ -407,26 +456,33 @@ func (t *Transaction) digestSubscription(
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	subscriptions := t.subscriptions
+
+	switch cmd.(type) {
+	case CmdPSubscribe, CmdPUnsubscribe:
+		subscriptions = t.patterns
+	}
+
 	switch cmd.(type) {
-	case CmdSubscribe:
-		arr := result.result.(rheltypes.Array)
+	case CmdSubscribe, CmdPSubscribe:
+		arr := result.result.(rheltypes.Push)
 		key := arr.At(1).String()
 		id, _ := arr.At(cmdSubscribeResultNumPos).Integer()
 
-		(*t).subscriptions[key] = id
+		subscriptions[key] = id
 
 		(*t).SubStart = (*t).numSubscriptions() == 1
 
-		arr.Set(cmdSubscribeResultNumPos, rheltypes.Integer(t.numSubscriptions()))
+		arr[cmdSubscribeResultNumPos] = rheltypes.Integer(t.numSubscriptions())
 
 		result.result = arr
-	case CmdUnsubscribe:
-		arr := result.result.(rheltypes.Array)
+	case CmdUnsubscribe, CmdPUnsubscribe:
+		arr := result.result.(rheltypes.Push)
 		key := arr.At(1).String()
 
-		delete((*t).subscriptions, key)
+		delete(subscriptions, key)
 
-		arr.Set(cmdSubscribeResultNumPos, rheltypes.Integer(t.numSubscriptions()))
+		arr[cmdSubscribeResultNumPos] = rheltypes.Integer(t.numSubscriptions())
 	case CmdPing:
 		if t.numSubscriptions() == 0 {
 			return
@@ -436,22 +492,42 @@ func (t *Transaction) digestSubscription(
 	}
 }
 
+// ExecuteCommand runs every complete command buffered in the parser,
+// incomplete ones are left for the next call.
 func ExecuteCommand(
-	command []byte,
-	tran **Transaction,
+	parser *rheltypes.Parser,
+	session *Session,
 ) iter.Seq[*CommandResult] {
 	return func(yield func(*CommandResult) bool) {
-		for parsed := range newParsedCommandFromBytes(command) {
+		tran := &session.transaction
+
+		for parsed := range newParsedCommandFromParser(parser) {
+			command := parsed.raw
+
 			if err := parsed.err; err != nil {
 				yield(NewCommandErrorResponse(command, err))
 
 				return
 			}
 
-			if err := parsed.Commit(tran); err != nil {
-				yield(NewCommandErrorResponse(command, err))
+			if err := parsed.verify(session); err != nil {
+				if *tran != nil && !parsed.exec {
+					(*tran).aborted = true
+				}
 
-				return
+				if !yield(newCommandResultError(err)) {
+					return
+				}
+
+				continue
+			}
+
+			if err := parsed.Commit(session); err != nil {
+				if !yield(newCommandResultError(err)) {
+					return
+				}
+
+				continue
 			}
 
 			var result *CommandResult
@@ -459,8 +535,13 @@ func ExecuteCommand(
 			if *tran != nil && !parsed.multi && !parsed.sub {
 				(*tran).cmds = append((*tran).cmds, parsed)
 				result = newCommandResultQueued()
-			} else if result = parsed.Exec(tran); result.Err != nil {
-				result = NewCommandErrorResponse(command, result.Err)
+			} else {
+				set := parsed.keyLockSet(session.SelectedDB())
+				unlock := session.instance.locks.lock(set)
+
+				result = parsed.Exec(tran)
+
+				unlock()
 			}
 
 			if !yield(result) || result.Err != nil {
@@ -469,8 +550,25 @@ func ExecuteCommand(
 
 			switch p := parsed.cmd.(type) {
 			case CmdPsync:
+				if !result.Succeeded() {
+					continue
+				}
+
+				file, err := p.RenderFile(session)
+				if err != nil {
+					// The replica is waiting for the file after FULLRESYNC.
+					result = newCommandResultError(err)
+					result.Err = err
+					yield(result)
+
+					return
+				}
+
 				if !yield(
-					&CommandResult{result: p.RenderFile(), KeepConnection: true},
+					&CommandResult{
+						result:         file,
+						KeepConnection: true,
+					},
 				) {
 					return
 				}
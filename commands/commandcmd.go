@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdCommand struct {
+	BaseCommand
+}
+
+func NewCmdCommand() CmdCommand {
+	return CmdCommand{BaseCommand: BaseCommand("COMMAND")}
+}
+
+func (c CmdCommand) Spec() ArgSpec {
+	return ArgSpec{Arity: -1, Variadic: true}
+}
+
+func (c CmdCommand) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	if len(args) == 0 {
+		return c.Info(nil), nil
+	}
+
+	switch subcmd := args.Cmd(); subcmd {
+	case "COUNT":
+		return rheltypes.Integer(commandRegistry.Count()), nil
+	case "INFO":
+		return c.Info(args[1:]), nil
+	case "DOCS":
+		return c.Docs(args[1:]), nil
+	case "LIST":
+		return c.List(args[1:])
+	case "GETKEYS":
+		return c.GetKeys(args[1:])
+	default:
+		return nil, rheltypes.NewErrorf(
+			rheltypes.GenericErrorType,
+			"unknown subcommand '%s'. Try COMMAND HELP.",
+			args.First(),
+		)
+	}
+}
+
+// Info describes the given commands, every one of them when names is empty.
+// Unknown commands are replied with a null.
+func (c CmdCommand) Info(names rheltypes.Array) (infos rheltypes.Array) {
+	if len(names) == 0 {
+		for entry := range commandRegistry.All() {
+			infos = append(infos, renderCommandInfo(entry))
+		}
+
+		return infos
+	}
+
+	infos = make(rheltypes.Array, len(names))
+
+	for i, name := range names {
+		if entry, found := commandRegistry.Lookup(name.String()); found {
+			infos[i] = renderCommandInfo(entry)
+		} else {
+			infos[i] = rheltypes.Null{}
+		}
+	}
+
+	return infos
+}
+
+// Docs documents the given commands, every one of them when names is empty.
+// Unknown commands are left out.
+func (c CmdCommand) Docs(names rheltypes.Array) (docs rheltypes.Map) {
+	if len(names) == 0 {
+		for entry := range commandRegistry.All() {
+			docs.Add(strings.ToLower(entry.Name), renderCommandDocs(entry))
+		}
+
+		return docs
+	}
+
+	for _, name := range names {
+		if entry, found := commandRegistry.Lookup(name.String()); found {
+			docs.Add(strings.ToLower(entry.Name), renderCommandDocs(entry))
+		}
+	}
+
+	return docs
+}
+
+func (c CmdCommand) List(
+	args rheltypes.Array,
+) (names rheltypes.Array, err error) {
+	match := func(*CommandEntry) bool { return true }
+
+	switch {
+	case len(args) == 0:
+	case len(args) == 3 && strings.EqualFold(args[0].String(), "FILTERBY"):
+		filter := args[2].String()
+
+		switch strings.ToUpper(args[1].String()) {
+		case "MODULE":
+			match = func(*CommandEntry) bool { return false }
+		case "ACLCAT":
+			match = func(entry *CommandEntry) bool {
+				return slices.Contains(entry.ACLCategories(), "@"+filter)
+			}
+		case "PATTERN":
+			match = func(entry *CommandEntry) bool {
+				return globMatch(filter, strings.ToLower(entry.Name))
+			}
+		default:
+			return nil, rheltypes.ErrSyntax
+		}
+	default:
+		return nil, rheltypes.ErrSyntax
+	}
+
+	names = rheltypes.Array{}
+
+	for entry := range commandRegistry.All() {
+		if match(entry) {
+			names.Append(rheltypes.NewBulkString(strings.ToLower(entry.Name)))
+		}
+	}
+
+	return names, nil
+}
+
+// GetKeys extracts the keys from a full command, e.g. GETKEYS SET k v.
+func (c CmdCommand) GetKeys(
+	args rheltypes.Array,
+) (keys rheltypes.Array, err error) {
+	if len(args) == 0 {
+		return nil, newArityError("command|getkeys")
+	}
+
+	entry, found := commandRegistry.Lookup(args.First().String())
+	if !found {
+		return nil, rheltypes.NewGenericError(
+			fmt.Errorf("Invalid command specified"),
+		)
+	}
+
+	spec := entry.Spec()
+
+	if err = spec.checkArity(entry.Name, args[1:]); err != nil {
+		return nil, rheltypes.NewGenericError(
+			fmt.Errorf("Invalid number of arguments specified for command"),
+		)
+	}
+
+	found = false
+	keys = rheltypes.Array{}
+
+	for _, key := range spec.Keys(args[1:]) {
+		keys.Append(rheltypes.NewBulkString(key))
+		found = true
+	}
+
+	if !found {
+		return nil, rheltypes.NewGenericError(
+			fmt.Errorf("The command has no key arguments"),
+		)
+	}
+
+	return keys, nil
+}
+
+func renderCommandInfo(entry *CommandEntry) rheltypes.Array {
+	spec := entry.Spec()
+
+	return rheltypes.Array{
+		rheltypes.NewBulkString(strings.ToLower(entry.Name)),
+		rheltypes.Integer(spec.Arity),
+		newSimpleStringSet(entry.Flags.Names()),
+		rheltypes.Integer(spec.FirstKey),
+		rheltypes.Integer(spec.LastKey),
+		rheltypes.Integer(spec.KeyStep),
+		newSimpleStringSet(entry.ACLCategories()),
+		// Tips, key specifications and subcommands aren't tracked.
+		rheltypes.Array{},
+		rheltypes.Array{},
+		rheltypes.Array{},
+	}
+}
+
+func renderCommandDocs(entry *CommandEntry) (docs rheltypes.Map) {
+	docs.Add("summary", rheltypes.NewBulkString(entry.Docs.Summary))
+	docs.Add("since", rheltypes.NewBulkString(entry.Docs.Since))
+	docs.Add("group", rheltypes.NewBulkString(entry.Docs.Group))
+
+	if entry.Docs.Complexity != "" {
+		docs.Add(
+			"complexity",
+			rheltypes.NewBulkString(entry.Docs.Complexity),
+		)
+	}
+
+	return docs
+}
+
+func newSimpleStringSet(values []string) rheltypes.Set {
+	set := make(rheltypes.Set, len(values))
+
+	for i, value := range values {
+		set[i] = rheltypes.SimpleString(value)
+	}
+
+	return set
+}
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
@@ -15,28 +17,69 @@ func NewCmdConfig() CmdConfig {
 	return CmdConfig{BaseCommand: BaseCommand("CONFIG")}
 }
 
-const defaultGetValueLength = 2
+func (c CmdConfig) Spec() ArgSpec {
+	return ArgSpec{Arity: -2, Variadic: true}
+}
+
+const defaultGetValueLength = 1
+
+var errUnknownConfig = errors.New("unknown option")
 
 func (c CmdConfig) Get(
+	session *Session,
 	args rheltypes.Array,
-) (value rheltypes.Array, err error) {
+) (value rheltypes.Map, err error) {
 	key := args.At(0)
 	if key == nil {
-		return nil, c.ErrWrap(fmt.Errorf("missing get key"))
+		return nil, newArityError("config|get")
 	}
 
-	config := GetConfigMapInstance()
+	config := session.Instance().Config()
 
-	value = append(make(rheltypes.Array, 0, defaultGetValueLength), key)
+	value = make(rheltypes.Map, 0, defaultGetValueLength)
 
 	if foundValue, found := config.Get(key.String()); found {
-		value = append(value, foundValue)
+		value.Add(key.String(), foundValue)
 	}
 
 	return
 }
 
+// Set changes the parameters given as name and value pairs, they are
+// applied in order and the first invalid one stops the command.
+func (c CmdConfig) Set(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return nil, newArityError("config|set")
+	}
+
+	for pair := range slices.Chunk(args, 2) {
+		name := pair[0].String()
+
+		err := session.Instance().SetConfig(name, pair[1].String())
+
+		switch {
+		case errors.Is(err, errUnknownConfig):
+			return nil, rheltypes.NewGenericError(fmt.Errorf(
+				"Unknown option or number of arguments for CONFIG SET - '%s'",
+				name,
+			))
+		case err != nil:
+			return nil, rheltypes.NewGenericError(fmt.Errorf(
+				"CONFIG SET failed (possibly related to argument '%s') - %w",
+				name,
+				err,
+			))
+		}
+	}
+
+	return rheltypes.SimpleString("OK"), nil
+}
+
 func (c CmdConfig) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	cmd := args.At(0)
@@ -46,7 +89,9 @@ func (c CmdConfig) Exec(
 
 	switch subcmd := strings.ToUpper(cmd.String()); subcmd {
 	case "GET":
-		return c.Get(args[1:])
+		return c.Get(session, args[1:])
+	case "SET":
+		return c.Set(session, args[1:])
 	default:
 		return nil, c.ErrWrap(fmt.Errorf("unknown config command %s", subcmd))
 	}
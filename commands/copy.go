@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdCopy struct {
+	BaseCommand
+}
+
+func NewCmdCopy() CmdCopy {
+	return CmdCopy{BaseCommand: BaseCommand("COPY")}
+}
+
+func (c CmdCopy) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  2,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgKey},
+		Options: []OptionSpec{
+			{Token: "DB", Values: []ArgKind{ArgInteger}},
+			{Token: "REPLACE"},
+		},
+	}
+}
+
+// Exec copies the value and its expiration to the destination key, in the
+// selected database unless DB is given. Nothing is copied when the
+// destination exists, unless REPLACE is given.
+func (c CmdCopy) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	src := spec.Args[0].String()
+	dst := spec.Args[1].String()
+	replace := spec.Has("REPLACE")
+	index := session.SelectedDB()
+
+	if db := spec.Option("DB"); db != nil {
+		index, _ = db.Integer()
+	}
+
+	instance := session.Instance()
+
+	if index < 0 || index >= instance.Databases() {
+		return nil, c.ErrWrap(rheltypes.ErrDbIndexOutOfRange)
+	}
+
+	if src == dst && index == session.SelectedDB() {
+		return nil, c.ErrWrap(errMoveSameDb)
+	}
+
+	entry, found := session.DB().Entry(src)
+	if !found {
+		return rheltypes.Integer(0), nil
+	}
+
+	copied := false
+
+	instance.DB(index).Compute(dst, func(
+		current rheltypes.RhelMapValue,
+		exists bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		if exists && !replace {
+			return current, rheltypes.ComputeKeep
+		}
+
+		copied = true
+
+		return rheltypes.RhelMapValue{
+			Value:      rheltypes.CloneValue(entry.Value),
+			Expiration: entry.Expiration,
+		}, rheltypes.ComputeStore
+	})
+
+	if !copied {
+		return rheltypes.Integer(0), nil
+	}
+
+	instance.notify(notifyGeneric, "copy_to", index, dst)
+
+	return rheltypes.Integer(1), nil
+}
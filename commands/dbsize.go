@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdDbSize struct {
+	BaseCommand
+}
+
+func NewCmdDbSize() CmdDbSize {
+	return CmdDbSize{BaseCommand: BaseCommand("DBSIZE")}
+}
+
+func (c CmdDbSize) Spec() ArgSpec {
+	return ArgSpec{Arity: 1}
+}
+
+func (c CmdDbSize) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return rheltypes.Integer(session.DB().Size()), nil
+}
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdDecr struct {
+	BaseCommand
+}
+
+func NewCmdDecr() CmdDecr {
+	return CmdDecr{BaseCommand: BaseCommand("DECR")}
+}
+
+func (c CmdDecr) Spec() ArgSpec {
+	return incrSpec
+}
+
+func (c CmdDecr) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return incrBy(c.BaseCommand, session, args.First().String(), -1)
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"errors"
+	"math"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var errDecrOverflow = rheltypes.NewGenericError(
+	errors.New("decrement would overflow"),
+)
+
+type CmdDecrBy struct {
+	BaseCommand
+}
+
+func NewCmdDecrBy() CmdDecrBy {
+	return CmdDecrBy{BaseCommand: BaseCommand("DECRBY")}
+}
+
+func (c CmdDecrBy) Spec() ArgSpec {
+	return incrBySpec
+}
+
+// Exec subtracts the amount like INCRBY adds it, the lowest integer can't
+// be negated and is refused.
+func (c CmdDecrBy) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	delta, err := parseIncrement(args.At(1))
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	if delta == math.MinInt64 {
+		return nil, c.ErrWrap(errDecrOverflow)
+	}
+
+	return incrBy(c.BaseCommand, session, args.At(0).String(), -delta)
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// multiKeySpec is shared by the commands taking one or more keys.
+var multiKeySpec = ArgSpec{
+	Arity:    -2,
+	FirstKey: 1,
+	LastKey:  -1,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey},
+	Variadic: true,
+}
+
+func deleteKeys(session *Session, keys rheltypes.Array) (deleted int) {
+	for _, key := range keys {
+		if session.DB().Delete(key.String()) {
+			session.notify(notifyGeneric, "del", key.String())
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+type CmdDel struct {
+	BaseCommand
+}
+
+func NewCmdDel() CmdDel {
+	return CmdDel{BaseCommand: BaseCommand("DEL")}
+}
+
+// NewCmdUnlink returns DEL under the name UNLINK. Redis reclaims the large
+// values UNLINK removes in a background thread rather than within the
+// command, here the garbage collector reclaims the values removed by
+// either command in the background, so both share one implementation.
+func NewCmdUnlink() CmdDel {
+	return CmdDel{BaseCommand: BaseCommand("UNLINK")}
+}
+
+// Render returns the command deleting key, e.g. to propagate an eviction.
+func (c CmdDel) Render(key string) rheltypes.Array {
+	return rheltypes.NewArrayFromStrings([]string{c.Name(), key})
+}
+
+func (c CmdDel) Spec() ArgSpec {
+	return multiKeySpec
+}
+
+func (c CmdDel) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return rheltypes.Integer(deleteKeys(session, args)), nil
+}
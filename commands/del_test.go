@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDelUnlink(t *testing.T) {
+	session := newTestSession()
+
+	for _, name := range []string{"DEL", "UNLINK"} {
+		runReplyTests(t, session, []replyTest{
+			{[]string{"SET", "a", "1"}, "+OK\r\n"},
+			{[]string{"RPUSH", "l", "x", "y"}, ":2\r\n"},
+			{[]string{name, "a", "l", "missing", "a"}, ":2\r\n"},
+			{[]string{"EXISTS", "a", "l"}, ":0\r\n"},
+			{[]string{name, "a"}, ":0\r\n"},
+			{[]string{name}, "-ERR wrong number of arguments for '" +
+				strings.ToLower(name) + "' command\r\n"},
+		})
+	}
+}
@@ -14,7 +14,14 @@ func NewCmdDiscard() CmdDiscard {
 	return CmdDiscard{BaseCommand: BaseCommand("DISCARD")}
 }
 
-func (c CmdDiscard) Exec(args rheltypes.Array) (rheltypes.RhelType, error) {
+func (c CmdDiscard) Spec() ArgSpec {
+	return ArgSpec{Arity: 1}
+}
+
+func (c CmdDiscard) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (rheltypes.RhelType, error) {
 	if args == nil {
 		return rheltypes.NewGenericError(
 			fmt.Errorf("DISCARD without MULTI"),
@@ -14,7 +14,12 @@ func NewCmdEcho() CmdEcho {
 	return CmdEcho{BaseCommand: BaseCommand("ECHO")}
 }
 
+func (c CmdEcho) Spec() ArgSpec {
+	return ArgSpec{Arity: 2, Args: []ArgKind{ArgString}}
+}
+
 func (c CmdEcho) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	value = args.First()
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// newErrorReply converts an error returned by a command into the reply sent
+// to its client. Replies wrapped in the error chain are sent as they are,
+// anything else becomes a generic ERR reply.
+func newErrorReply(err error) rheltypes.Error {
+	var reply rheltypes.Error
+	if errors.As(err, &reply) {
+		return reply
+	}
+
+	return rheltypes.NewGenericError(err)
+}
+
+// newProtocolErrorReply replies the reason of a protocol error the way
+// Redis does, other errors are replied as they are.
+func newProtocolErrorReply(err error) rheltypes.Error {
+	var protocolErr rheltypes.ProtocolError
+	if !errors.As(err, &protocolErr) {
+		return newErrorReply(err)
+	}
+
+	return rheltypes.NewGenericError(
+		errors.New("Protocol error: " + protocolErr.Reason),
+	)
+}
+
+func newUnknownCommandError(name string, args rheltypes.Array) error {
+	var quoted strings.Builder
+
+	for _, arg := range args {
+		fmt.Fprintf(&quoted, "'%s' ", arg)
+	}
+
+	return rheltypes.NewGenericError(fmt.Errorf(
+		"unknown command '%s', with args beginning with: %s",
+		name,
+		quoted.String(),
+	))
+}
+
+// recoverCommand turns a panic raised by a command into an error, so a single
+// misbehaving command can't bring the whole server down.
+func recoverCommand(cmd RhelCommand, err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("command %s panicked: %v", cmd.Name(), r)
+	}
+}
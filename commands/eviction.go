@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const (
+	defaultMaxMemorySamples = 5
+	// evictionPoolSize is the number of best candidates kept between
+	// evictions, like the eviction pool of Redis.
+	evictionPoolSize = 16
+)
+
+var errInvalidMemory = errors.New("argument must be a memory value")
+
+// memoryUnits are the suffixes accepted by memory values, e.g. 100mb.
+var memoryUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"kb", 1 << 10},
+	{"mb", 1 << 20},
+	{"gb", 1 << 30},
+	{"k", 1e3},
+	{"m", 1e6},
+	{"g", 1e9},
+	{"b", 1},
+}
+
+// parseMemory parses a number of bytes the way Redis reads maxmemory.
+func parseMemory(value string) (int64, error) {
+	value = strings.ToLower(value)
+	factor := int64(1)
+
+	for _, unit := range memoryUnits {
+		if number, found := strings.CutSuffix(value, unit.suffix); found {
+			value, factor = number, unit.factor
+
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 || n > (1<<63-1)/factor {
+		return 0, errInvalidMemory
+	}
+
+	return n * factor, nil
+}
+
+// evictionCandidate is a key of the eviction pool along with its database.
+type evictionCandidate struct {
+	rheltypes.EvictionCandidate
+	db int
+}
+
+// evictor frees memory once the keyspace grows over maxmemory. The limits
+// are read by every command and kept as atomics, the pool is only used by
+// one eviction at a time. stale is set once the pool no longer matches the
+// keyspace or the policy, it is dropped by the next eviction.
+type evictor struct {
+	maxMemory atomic.Int64
+	policy    atomic.Int64
+	samples   atomic.Int64
+	evicted   atomic.Int64
+	stale     atomic.Bool
+	lock      sync.Mutex
+	pool      []evictionCandidate
+	next      int
+}
+
+// dropPool discards the candidates of the pool, e.g. after the policy
+// changed or a database was flushed or swapped. It doesn't wait for an
+// eviction in progress, which may hold the locks of the keyspace.
+func (e *evictor) dropPool() {
+	e.stale.Store(true)
+}
+
+// UsedMemory estimates the bytes held by the keys of every database.
+func (i *Instance) UsedMemory() (used int64) {
+	for index := range i.Databases() {
+		used += i.DB(index).UsedMemory()
+	}
+
+	return used
+}
+
+func (i *Instance) MaxMemory() int64 {
+	return i.eviction.maxMemory.Load()
+}
+
+func (i *Instance) EvictionPolicy() rheltypes.EvictionPolicy {
+	return rheltypes.EvictionPolicy(i.eviction.policy.Load())
+}
+
+// EvictedKeys counts the keys removed to stay under maxmemory.
+func (i *Instance) EvictedKeys() int64 {
+	return i.eviction.evicted.Load()
+}
+
+// freeMemory evicts keys until the used memory is back under maxmemory,
+// it fails with an OOM error when the policy can't free enough. Replicas
+// never evict, they remove the keys evicted by their master.
+func (i *Instance) freeMemory() error {
+	limit := i.MaxMemory()
+	if limit == 0 || i.UsedMemory() <= limit || i.IsReplica() {
+		return nil
+	}
+
+	policy := i.EvictionPolicy()
+	if policy == rheltypes.NoEviction {
+		return rheltypes.ErrOom
+	}
+
+	e := &i.eviction
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for i.UsedMemory() > limit {
+		candidate, found := i.nextEvictionCandidate(policy)
+		if !found {
+			return rheltypes.ErrOom
+		}
+
+		var set keyLockSet
+		set.add(candidate.db, candidate.Key)
+
+		unlock := i.locks.lock(set)
+
+		evicted := i.DB(candidate.db).Evict(candidate.Key, policy.Volatile())
+		if evicted {
+			e.evicted.Add(1)
+			i.propagateEviction(candidate.db, candidate.Key)
+			i.notify(notifyEvicted, "evicted", candidate.db, candidate.Key)
+		}
+
+		unlock()
+	}
+
+	return nil
+}
+
+// nextEvictionCandidate samples every database and returns the best key
+// to evict, random policies take the first key sampled, starting from the
+// database after the last one evicted from. The lock has to be held.
+func (i *Instance) nextEvictionCandidate(
+	policy rheltypes.EvictionPolicy,
+) (evictionCandidate, bool) {
+	e := &i.eviction
+	samples := int(e.samples.Load())
+
+	if e.stale.Swap(false) {
+		e.pool = nil
+	}
+
+	random := policy == rheltypes.AllKeysRandom ||
+		policy == rheltypes.VolatileRandom
+
+	for n := range i.Databases() {
+		db := (e.next + n) % i.Databases()
+
+		for _, sample := range i.DB(db).SampleEviction(policy, samples) {
+			if random {
+				e.next = db + 1
+
+				return evictionCandidate{sample, db}, true
+			}
+
+			e.addToPool(evictionCandidate{sample, db})
+		}
+	}
+
+	if len(e.pool) == 0 {
+		return evictionCandidate{}, false
+	}
+
+	best := e.pool[0]
+	e.pool = e.pool[1:]
+
+	return best, true
+}
+
+// addToPool keeps the best evictionPoolSize candidates, by decreasing
+// score. A key already in the pool gets its score updated.
+func (e *evictor) addToPool(candidate evictionCandidate) {
+	e.pool = slices.DeleteFunc(e.pool, func(c evictionCandidate) bool {
+		return c.db == candidate.db && c.Key == candidate.Key
+	})
+
+	pos, _ := slices.BinarySearchFunc(
+		e.pool,
+		candidate,
+		func(c, target evictionCandidate) int {
+			return cmp.Compare(target.Score, c.Score)
+		},
+	)
+
+	if pos < evictionPoolSize {
+		e.pool = slices.Insert(e.pool, pos, candidate)
+		e.pool = e.pool[:min(len(e.pool), evictionPoolSize)]
+	}
+}
+
+// propagateEviction sends the removal of an evicted key to the replicas.
+func (i *Instance) propagateEviction(db int, key string) {
+	selectCmd := NewCmdSelect().Render(db).Serialize()
+	delCmd := NewCmdDel().Render(key).Serialize()
+
+	// A replica that can't be reached is dropped from the pool, there is
+	// no one to report the failure to.
+	_ = i.replicas.ResendInDb(db, selectCmd, delCmd)
+}
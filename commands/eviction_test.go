@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// fillMemory sets keys of 1000 bytes up to twice maxmemory under
+// allkeys-lru, freeing memory after each one the way commands do, which
+// leaves keys without expiration in the eviction pool.
+func fillMemory(t *testing.T, i *Instance) {
+	t.Helper()
+
+	i.SetConfig("maxmemory", "100kb")
+	i.SetConfig("maxmemory-policy", "allkeys-lru")
+
+	value := rheltypes.NewBulkString(strings.Repeat("x", 1000))
+
+	for n := range 200 {
+		i.DB(0).Set(fmt.Sprint("key:", n), value)
+
+		if err := i.freeMemory(); err != nil {
+			t.Fatalf("failed to free memory: %s", err)
+		}
+	}
+
+	if i.EvictedKeys() == 0 {
+		t.Fatal("got no key evicted by allkeys-lru")
+	}
+}
+
+func TestVolatileEvictionKeepsPersistentKeys(t *testing.T) {
+	i := NewInstance(1)
+	fillMemory(t, i)
+
+	evicted, size := i.EvictedKeys(), i.DB(0).Size()
+
+	i.SetConfig("maxmemory-policy", "volatile-lru")
+	i.DB(0).Set("big", rheltypes.NewBulkString(strings.Repeat("x", 5000)))
+
+	if err := i.freeMemory(); !errors.Is(err, rheltypes.ErrOom) {
+		t.Fatalf("got error %v, want %v", err, rheltypes.ErrOom)
+	}
+
+	if got := i.EvictedKeys(); got != evicted {
+		t.Errorf("got %d keys evicted, want %d", got, evicted)
+	}
+
+	if got := i.DB(0).Size(); got != size+1 {
+		t.Errorf("got %d keys, want %d", got, size+1)
+	}
+}
+
+func TestEvictionPoolDroppedBySwapDB(t *testing.T) {
+	i := NewInstance(2)
+	fillMemory(t, i)
+
+	i.SwapDB(0, 1)
+
+	e := &i.eviction
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	candidate, found := i.nextEvictionCandidate(rheltypes.AllKeysLru)
+	if !found {
+		t.Fatal("got no candidate")
+	}
+
+	if _, exists := i.DB(candidate.db).Get(candidate.Key); !exists {
+		t.Errorf("got candidate %q missing from db %d", candidate.Key,
+			candidate.db)
+	}
+}
@@ -14,7 +14,14 @@ func NewCmdExec() CmdExec {
 	return CmdExec{BaseCommand: BaseCommand("EXEC")}
 }
 
-func (c CmdExec) Exec(args rheltypes.Array) (rheltypes.RhelType, error) {
+func (c CmdExec) Spec() ArgSpec {
+	return ArgSpec{Arity: 1}
+}
+
+func (c CmdExec) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (rheltypes.RhelType, error) {
 	if args == nil {
 		return rheltypes.NewGenericError(fmt.Errorf("EXEC without MULTI")), nil
 	} else {
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// newTestSession returns a session on a fresh instance of two databases.
+func newTestSession() *Session {
+	return NewSession(NewInstance(2))
+}
+
+// execute runs the command made of args and returns its serialized reply.
+func execute(t *testing.T, session *Session, args ...string) string {
+	t.Helper()
+
+	cmd := rheltypes.NewArrayFromStrings(args).Serialize()
+	parser := rheltypes.NewParserFromBytes(cmd)
+
+	for result := range ExecuteCommand(parser, session) {
+		return string(result.Serialize(session.protocol))
+	}
+
+	t.Fatalf("got no reply to %q", args)
+
+	return ""
+}
+
+// replyTest is a command along with the reply expected for it.
+type replyTest struct {
+	args []string
+	want string
+}
+
+// runReplyTests executes tests in order on session.
+func runReplyTests(t *testing.T, session *Session, tests []replyTest) {
+	t.Helper()
+
+	for _, test := range tests {
+		if got := execute(t, session, test.args...); got != test.want {
+			t.Errorf("%q replied %q, want %q", test.args, got, test.want)
+		}
+	}
+}
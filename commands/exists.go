@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// countKeys counts the keys that exist according to lookup, a key given
+// several times is counted each time.
+func countKeys(
+	lookup func(key string) (rheltypes.RhelMapValue, bool),
+	keys rheltypes.Array,
+) (count int) {
+	for _, key := range keys {
+		if _, found := lookup(key.String()); found {
+			count++
+		}
+	}
+
+	return count
+}
+
+type CmdExists struct {
+	BaseCommand
+}
+
+func NewCmdExists() CmdExists {
+	return CmdExists{BaseCommand: BaseCommand("EXISTS")}
+}
+
+func (c CmdExists) Spec() ArgSpec {
+	return multiKeySpec
+}
+
+func (c CmdExists) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return rheltypes.Integer(countKeys(session.DB().Peek, args)), nil
+}
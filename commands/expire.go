@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var (
+	errExpireNxConflict = rheltypes.NewGenericError(errors.New(
+		"NX and XX, GT or LT options at the same time are not compatible",
+	))
+	errExpireGtLtConflict = rheltypes.NewGenericError(errors.New(
+		"GT and LT options at the same time are not compatible",
+	))
+)
+
+// expireSpec is shared by the EXPIRE family, the time is given either in
+// seconds or milliseconds, relative or as a unix time.
+var expireSpec = ArgSpec{
+	Arity:    -3,
+	FirstKey: 1,
+	LastKey:  1,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey, ArgInteger},
+	Options: []OptionSpec{
+		{Token: "NX"},
+		{Token: "XX"},
+		{Token: "GT"},
+		{Token: "LT"},
+	},
+}
+
+// absoluteExpiration converts the time given to a command in unit into a
+// unix time in milliseconds, times it can't hold are invalid.
+func absoluteExpiration(
+	name string,
+	when int,
+	unit time.Duration,
+	absolute bool,
+) (expiration int64, err error) {
+	scale := int64(unit / time.Millisecond)
+
+	if int64(when) > math.MaxInt64/scale || int64(when) < math.MinInt64/scale {
+		return 0, newInvalidExpireError(name)
+	}
+
+	expiration = int64(when) * scale
+
+	if !absolute {
+		now := time.Now().UnixMilli()
+
+		if expiration > math.MaxInt64-now {
+			return 0, newInvalidExpireError(name)
+		}
+
+		expiration += now
+	}
+
+	return expiration, nil
+}
+
+// expireEvent is the event notified when a key is given expiration, which
+// deletes the key when it is in the past.
+func expireEvent(expiration int64) string {
+	if (rheltypes.RhelMapValue{Expiration: expiration}).IsExpired() {
+		return "del"
+	}
+
+	return "expire"
+}
+
+type cmdExpireArgs struct {
+	key        string
+	expiration int64
+	nx         bool
+	xx         bool
+	gt         bool
+	lt         bool
+}
+
+// accept tells whether the options allow replacing the current expiration,
+// a key without expiration has an infinite time to live for GT and LT.
+func (a cmdExpireArgs) accept(current int64) bool {
+	switch {
+	case a.nx && current != 0, a.xx && current == 0:
+		return false
+	case a.gt:
+		return current != 0 && a.expiration > current
+	case a.lt:
+		return current == 0 || a.expiration < current
+	default:
+		return true
+	}
+}
+
+func parseExpireArgs(
+	name string,
+	args rheltypes.Array,
+	unit time.Duration,
+	absolute bool,
+) (parsed cmdExpireArgs, err error) {
+	spec, err := expireSpec.Parse(name, args)
+	if err != nil {
+		return parsed, err
+	}
+
+	parsed.key = spec.Args[0].String()
+	parsed.nx = spec.Has("NX")
+	parsed.xx = spec.Has("XX")
+	parsed.gt = spec.Has("GT")
+	parsed.lt = spec.Has("LT")
+
+	if parsed.nx && (parsed.xx || parsed.gt || parsed.lt) {
+		return parsed, errExpireNxConflict
+	}
+
+	if parsed.gt && parsed.lt {
+		return parsed, errExpireGtLtConflict
+	}
+
+	when, _ := spec.Args[1].Integer()
+
+	expiration, err := absoluteExpiration(name, when, unit, absolute)
+	if err != nil {
+		return parsed, err
+	}
+
+	// An expiration in the past deletes the key, 0 would mean none at all.
+	parsed.expiration = max(expiration, 1)
+
+	return parsed, nil
+}
+
+// execExpire runs the commands of the EXPIRE family, replying 1 when the
+// expiration was set.
+func execExpire(
+	c BaseCommand,
+	session *Session,
+	args rheltypes.Array,
+	unit time.Duration,
+	absolute bool,
+) (rheltypes.RhelType, error) {
+	parsed, err := parseExpireArgs(c.Name(), args, unit, absolute)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	if !session.DB().Expire(parsed.key, parsed.expiration, parsed.accept) {
+		return rheltypes.Integer(0), nil
+	}
+
+	session.notify(notifyGeneric, expireEvent(parsed.expiration), parsed.key)
+
+	return rheltypes.Integer(1), nil
+}
+
+type CmdExpire struct {
+	BaseCommand
+}
+
+func NewCmdExpire() CmdExpire {
+	return CmdExpire{BaseCommand: BaseCommand("EXPIRE")}
+}
+
+func (c CmdExpire) Spec() ArgSpec {
+	return expireSpec
+}
+
+func (c CmdExpire) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execExpire(c.BaseCommand, session, args, time.Second, false)
+}
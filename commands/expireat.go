@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdExpireAt struct {
+	BaseCommand
+}
+
+func NewCmdExpireAt() CmdExpireAt {
+	return CmdExpireAt{BaseCommand: BaseCommand("EXPIREAT")}
+}
+
+func (c CmdExpireAt) Spec() ArgSpec {
+	return expireSpec
+}
+
+func (c CmdExpireAt) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execExpire(c.BaseCommand, session, args, time.Second, true)
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdExpireTime struct {
+	BaseCommand
+}
+
+func NewCmdExpireTime() CmdExpireTime {
+	return CmdExpireTime{BaseCommand: BaseCommand("EXPIRETIME")}
+}
+
+func (c CmdExpireTime) Spec() ArgSpec {
+	return ttlSpec
+}
+
+func (c CmdExpireTime) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return keyTtl(session, args, time.Second, true), nil
+}
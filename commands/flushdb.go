@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdFlushDb struct {
+	BaseCommand
+}
+
+func NewCmdFlushDb() CmdFlushDb {
+	return CmdFlushDb{BaseCommand: BaseCommand("FLUSHDB")}
+}
+
+// Spec accepts the ASYNC and SYNC modes, the database is always flushed
+// synchronously.
+func (c CmdFlushDb) Spec() ArgSpec {
+	return ArgSpec{
+		Arity: -1,
+		Options: []OptionSpec{
+			{Token: "ASYNC"},
+			{Token: "SYNC"},
+		},
+	}
+}
+
+func (c CmdFlushDb) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	session.DB().Flush()
+	session.Instance().eviction.dropPool()
+
+	return rheltypes.SimpleString("OK"), nil
+}
@@ -14,7 +14,18 @@ func NewCmdGet() CmdGet {
 	return CmdGet{BaseCommand: BaseCommand("GET")}
 }
 
+func (c CmdGet) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+	}
+}
+
 func (c CmdGet) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(0)
@@ -22,7 +33,7 @@ func (c CmdGet) Exec(
 		return nil, c.ErrWrap(fmt.Errorf("missing key"))
 	}
 
-	instance := GetDataMapInstance()
+	instance := session.DB()
 
 	var found bool
 
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdGetBit struct {
+	BaseCommand
+}
+
+func NewCmdGetBit() CmdGetBit {
+	return CmdGetBit{BaseCommand: BaseCommand("GETBIT")}
+}
+
+func (c CmdGetBit) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+	}
+}
+
+// Exec replies the bit of the string at key at offset, 0 past its end or
+// if key is missing.
+func (c CmdGetBit) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	offset, err := parseBitOffset(spec.Args[1])
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	text, _, err := getString(session.DB(), spec.Args[0].String())
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	return rheltypes.Integer(bitAt(text, offset)), nil
+}
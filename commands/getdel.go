@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdGetDel struct {
+	BaseCommand
+}
+
+func NewCmdGetDel() CmdGetDel {
+	return CmdGetDel{BaseCommand: BaseCommand("GETDEL")}
+}
+
+func (c CmdGetDel) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+	}
+}
+
+func (c CmdGetDel) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	value = rheltypes.NewNullBulkString()
+
+	key := args.At(0).String()
+	deleted := false
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		switch {
+		case !found:
+			return current, rheltypes.ComputeKeep
+		case !isStringValue(current.Value):
+			err = c.ErrWrap(rheltypes.ErrWrongType)
+
+			return current, rheltypes.ComputeKeep
+		}
+
+		value = current.Value
+		deleted = true
+
+		return current, rheltypes.ComputeDelete
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deleted {
+		session.notify(notifyGeneric, "del", key)
+	} else {
+		session.notify(notifyKeyMiss, "keymiss", key)
+	}
+
+	return value, nil
+}
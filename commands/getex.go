@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdGetEx struct {
+	BaseCommand
+}
+
+func NewCmdGetEx() CmdGetEx {
+	return CmdGetEx{BaseCommand: BaseCommand("GETEX")}
+}
+
+func (c CmdGetEx) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+		Options: []OptionSpec{
+			{Token: "EX", Values: []ArgKind{ArgInteger}},
+			{Token: "PX", Values: []ArgKind{ArgInteger}},
+			{Token: "EXAT", Values: []ArgKind{ArgInteger}},
+			{Token: "PXAT", Values: []ArgKind{ArgInteger}},
+			{Token: "PERSIST"},
+		},
+	}
+}
+
+// Exec replies the value like GET and changes its expiration when an
+// option is given.
+func (c CmdGetEx) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	expiration, hasExpiration, err := parseExpireOption(c.Name(), spec)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	persist := spec.Has("PERSIST")
+
+	if hasExpiration && persist {
+		return nil, c.ErrWrap(rheltypes.ErrSyntax)
+	}
+
+	key := spec.Args[0].String()
+	found := false
+	event := ""
+	value = rheltypes.NewNullBulkString()
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		exists bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		switch {
+		case !exists:
+			return current, rheltypes.ComputeKeep
+		case !isStringValue(current.Value):
+			err = c.ErrWrap(rheltypes.ErrWrongType)
+
+			return current, rheltypes.ComputeKeep
+		}
+
+		value = current.Value
+		found = true
+
+		switch {
+		case hasExpiration:
+			current.Expiration = expiration
+			event = expireEvent(expiration)
+		case persist && current.Expiration != 0:
+			current.Expiration = 0
+			event = "persist"
+		default:
+			return current, rheltypes.ComputeKeep
+		}
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !found:
+		session.notify(notifyKeyMiss, "keymiss", key)
+	case event != "":
+		session.notify(notifyGeneric, event, key)
+	}
+
+	return value, nil
+}
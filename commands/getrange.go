@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// getRangeSpec is shared by GETRANGE and its former name SUBSTR.
+var getRangeSpec = ArgSpec{
+	Arity:    4,
+	FirstKey: 1,
+	LastKey:  1,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey, ArgInteger, ArgInteger},
+}
+
+// stringRange returns the bytes of text from start to end included,
+// negative offsets counting from the end, the way GETRANGE clamps them.
+func stringRange(text string, start, end int) string {
+	if start < 0 && end < 0 && start > end {
+		return ""
+	}
+
+	if start < 0 {
+		start += len(text)
+	}
+
+	if end < 0 {
+		end += len(text)
+	}
+
+	start, end = max(start, 0), min(max(end, 0), len(text)-1)
+
+	if start > end || len(text) == 0 {
+		return ""
+	}
+
+	return text[start : end+1]
+}
+
+func execGetRange(
+	c BaseCommand,
+	session *Session,
+	args rheltypes.Array,
+) (rheltypes.RhelType, error) {
+	spec, err := getRangeSpec.Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	start, _ := spec.Args[1].Integer()
+	end, _ := spec.Args[2].Integer()
+
+	text, _, err := getString(session.DB(), spec.Args[0].String())
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	return rheltypes.NewBulkString(stringRange(text, start, end)), nil
+}
+
+type CmdGetRange struct {
+	BaseCommand
+}
+
+func NewCmdGetRange() CmdGetRange {
+	return CmdGetRange{BaseCommand: BaseCommand("GETRANGE")}
+}
+
+func (c CmdGetRange) Spec() ArgSpec {
+	return getRangeSpec
+}
+
+func (c CmdGetRange) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execGetRange(c.BaseCommand, session, args)
+}
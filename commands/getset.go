@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdGetSet struct {
+	BaseCommand
+}
+
+func NewCmdGetSet() CmdGetSet {
+	return CmdGetSet{BaseCommand: BaseCommand("GETSET")}
+}
+
+func (c CmdGetSet) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+	}
+}
+
+// Exec behaves like SET key value GET.
+func (c CmdGetSet) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	setArgs := CmdSetArgs{
+		Key:   args.At(0).String(),
+		Value: args.At(1),
+		Get:   true,
+	}
+
+	old, _, err := setArgs.set(session)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	if old == nil {
+		return rheltypes.NewNullBulkString(), nil
+	}
+
+	return old, nil
+}
@@ -0,0 +1,101 @@
+package commands
+
+// globMatch reports whether str matches the glob-style pattern the way
+// Redis does: '*' matches any sequence, '?' any single character, '[...]'
+// a set of characters with ranges and '^' negation, and '\' escapes the
+// character that follows it. Like stringmatchlen, an empty str matches
+// only an empty pattern, not even "*".
+func globMatch(pattern, str string) bool {
+	if str == "" {
+		return pattern == ""
+	}
+
+	p, s := 0, 0
+	star, starStr := -1, 0
+
+	for s < len(str) {
+		if p < len(pattern) {
+			if pattern[p] == '*' {
+				star, starStr = p, s
+				p++
+
+				continue
+			}
+
+			if n, matched := matchGlobToken(pattern[p:], str[s]); matched {
+				p += n
+				s++
+
+				continue
+			}
+		}
+
+		// Backtrack to the last star and let it consume one more byte.
+		if star < 0 {
+			return false
+		}
+
+		starStr++
+		p, s = star+1, starStr
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
+
+// matchGlobToken matches c against the token at the start of pattern, it
+// returns the length of the token and whether c matched it.
+func matchGlobToken(pattern string, c byte) (n int, matched bool) {
+	switch pattern[0] {
+	case '?':
+		return 1, true
+	case '[':
+		return matchGlobClass(pattern, c)
+	case '\\':
+		if len(pattern) > 1 {
+			return 2, pattern[1] == c
+		}
+
+		return 1, c == '\\'
+	default:
+		return 1, pattern[0] == c
+	}
+}
+
+// matchGlobClass matches c against the '[...]' class at the start of
+// pattern. An unterminated class extends to the end of the pattern.
+func matchGlobClass(pattern string, c byte) (n int, matched bool) {
+	i := 1
+
+	negate := i < len(pattern) && pattern[i] == '^'
+	if negate {
+		i++
+	}
+
+	for ; i < len(pattern) && pattern[i] != ']'; i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			i++
+			matched = matched || pattern[i] == c
+		case i+2 < len(pattern) && pattern[i+1] == '-':
+			lo, hi := pattern[i], pattern[i+2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+
+			matched = matched || lo <= c && c <= hi
+			i += 2
+		default:
+			matched = matched || pattern[i] == c
+		}
+	}
+
+	if i < len(pattern) {
+		i++
+	}
+
+	return i, matched != negate
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"testing"
+)
+
+// The expected results are the ones of stringmatchlen in Redis.
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		str     string
+		want    bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", false},
+		{"*", "anything", true},
+		{"**", "anything", true},
+		{"h*llo", "hllo", true},
+		{"h*llo", "heeeello", true},
+		{"h*llo", "hello!", false},
+		{"*llo", "hellollo", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"a*", "a", true},
+		{"?", "", false},
+		{"?", "a", true},
+		{"?", "ab", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"[a-c]", "b", true},
+		{"[a-c]", "d", false},
+		{"[c-a]", "b", true},
+		{"[a-c-e]", "-", true},
+		{"[a-c-e]", "e", true},
+		{"[a-]", "^", true},
+		{"[^x]", "y", true},
+		{"[^x]", "x", false},
+		{"[^a-c]", "b", false},
+		{"[^a-c]", "z", true},
+		{`[\]]`, "]", true},
+		{`[\-]`, "-", true},
+		{`[a\-c]`, "b", false},
+		{`\*`, "*", true},
+		{`\*`, "a", false},
+		{`\?`, "?", true},
+		{`\[a]`, "[a]", true},
+		{`a\`, `a\`, true},
+		{`a\`, "a", false},
+		{`a\`, "ab", false},
+		{`\`, `\`, true},
+		{"[abc", "b", true},
+		{"[abc", "[abc", false},
+		{"a[bc", "ab", true},
+		{"[", "[", false},
+		{"[]", "]", false},
+		{"*[", "x", false},
+	}
+
+	for _, test := range tests {
+		if got := globMatch(test.pattern, test.str); got != test.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v",
+				test.pattern, test.str, got, test.want)
+		}
+	}
+}
+
+func TestKeysMatchEmptyKey(t *testing.T) {
+	runReplyTests(t, newTestSession(), []replyTest{
+		{[]string{"SET", "", "empty"}, "+OK\r\n"},
+		{[]string{"KEYS", "*"}, "*1\r\n$0\r\n\r\n"},
+		{[]string{"KEYS", "**"}, "*0\r\n"},
+		{[]string{"SCAN", "0"}, "*2\r\n$1\r\n0\r\n*1\r\n$0\r\n\r\n"},
+		{[]string{"SCAN", "0", "MATCH", "*"},
+			"*2\r\n$1\r\n0\r\n*1\r\n$0\r\n\r\n"},
+		{[]string{"SCAN", "0", "MATCH", "?*"}, "*2\r\n$1\r\n0\r\n*0\r\n"},
+	})
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const (
+	serverName    = "redis"
+	serverVersion = "7.4.0"
+	serverMode    = "standalone"
+	defaultUser   = "default"
+)
+
+type CmdHello struct {
+	BaseCommand
+}
+
+func NewCmdHello() CmdHello {
+	return CmdHello{BaseCommand: BaseCommand("HELLO")}
+}
+
+func (c CmdHello) Spec() ArgSpec {
+	return ArgSpec{Arity: -1, Variadic: true}
+}
+
+func (c CmdHello) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	proto := session.Protocol()
+
+	if version := args.First(); version != nil {
+		num, err := version.Integer()
+		if err != nil {
+			return rheltypes.NewGenericError(fmt.Errorf(
+				"Protocol version is not an integer or out of range",
+			)), nil
+		}
+
+		var ok bool
+
+		if proto, ok = rheltypes.NewProtocol(num); !ok {
+			return rheltypes.ErrNoProto, nil
+		}
+
+		args = args[1:]
+	}
+
+	name := session.Name()
+
+	for i := 0; i < len(args); i++ {
+		switch opt := strings.ToUpper(args[i].String()); {
+		case opt == "AUTH" && i+2 < len(args):
+			if args[i+1].String() != defaultUser {
+				return rheltypes.ErrWrongPass, nil
+			}
+
+			i += 2
+		case opt == "SETNAME" && i+1 < len(args):
+			name = args[i+1].String()
+			i++
+		default:
+			return rheltypes.NewGenericError(
+				fmt.Errorf("Syntax error in HELLO option '%s'", args[i]),
+			), nil
+		}
+	}
+
+	session.SetProtocol(proto)
+	session.SetName(name)
+
+	return c.reply(session), nil
+}
+
+func (c CmdHello) reply(session *Session) (reply rheltypes.Map) {
+	role := "master"
+	if session.Instance().IsReplica() {
+		role = "replica"
+	}
+
+	reply = rheltypes.NewMapFromStrings(
+		"server", serverName,
+		"version", serverVersion,
+	)
+	reply.Add("proto", rheltypes.Integer(session.Protocol()))
+	reply.Add("id", rheltypes.Integer(session.Id()))
+	reply.Add("mode", rheltypes.NewBulkString(serverMode))
+	reply.Add("role", rheltypes.NewBulkString(role))
+	reply.Add("modules", rheltypes.Array{})
+
+	return
+}
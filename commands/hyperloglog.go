@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// hyperLogLogValue reads the HyperLogLog held by a string value.
+func hyperLogLogValue(
+	value rheltypes.RhelType,
+) (*rheltypes.HyperLogLog, error) {
+	text, err := stringValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return rheltypes.ParseHyperLogLog(text)
+}
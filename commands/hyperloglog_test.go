@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"testing"
+)
+
+// pfadd adds prefix:0 to prefix:n-1 to the HyperLogLog at key.
+func pfadd(t *testing.T, session *Session, key, prefix string, n int) {
+	t.Helper()
+
+	args := []string{"PFADD", key}
+	for i := range n {
+		args = append(args, fmt.Sprintf("%s:%d", prefix, i))
+	}
+
+	execute(t, session, args...)
+}
+
+// TestPfCountCache mirrors "PFADD / PFCOUNT cache works" of the Redis test
+// suite: the last byte of the cached cardinality has its high bit set
+// while the cardinality is stale.
+func TestPfCountCache(t *testing.T) {
+	session := newTestSession()
+
+	runReplyTests(t, session, []replyTest{
+		{[]string{"PFADD", "hll", "a", "b", "c"}, ":1\r\n"},
+		{[]string{"GETRANGE", "hll", "15", "15"}, "$1\r\n\x80\r\n"},
+		{[]string{"PFCOUNT", "hll"}, ":3\r\n"},
+		{[]string{"GETRANGE", "hll", "15", "15"}, "$1\r\n\x00\r\n"},
+		{[]string{"GETRANGE", "hll", "8", "8"}, "$1\r\n\x03\r\n"},
+		{[]string{"PFADD", "hll", "a", "b", "c"}, ":0\r\n"},
+		{[]string{"GETRANGE", "hll", "15", "15"}, "$1\r\n\x00\r\n"},
+		{[]string{"PFADD", "hll", "a", "b", "c", "d"}, ":1\r\n"},
+		{[]string{"GETRANGE", "hll", "15", "15"}, "$1\r\n\x80\r\n"},
+		{[]string{"PFCOUNT", "hll"}, ":4\r\n"},
+		{[]string{"GETRANGE", "hll", "15", "15"}, "$1\r\n\x00\r\n"},
+	})
+}
+
+// TestPfMergeUnion merges sparse and dense HyperLogLogs, which has to
+// estimate the same cardinality as PFCOUNT over their keys.
+func TestPfMergeUnion(t *testing.T) {
+	session := newTestSession()
+
+	pfadd(t, session, "sparse1", "a", 50)
+	pfadd(t, session, "sparse2", "b", 200)
+	pfadd(t, session, "dense1", "a", 5000)
+	pfadd(t, session, "dense2", "c", 8000)
+
+	tests := []struct {
+		sources  []string
+		encoding string
+	}{
+		{[]string{"sparse1", "sparse2"}, "\x01"},
+		{[]string{"sparse1", "dense1"}, "\x00"},
+		{[]string{"sparse2", "dense2", "missing"}, "\x00"},
+		{[]string{"dense1", "dense2", "sparse1", "sparse2"}, "\x00"},
+	}
+
+	for i, test := range tests {
+		dest := fmt.Sprintf("merged%d", i)
+		union := execute(t, session, append([]string{"PFCOUNT"},
+			test.sources...)...)
+
+		runReplyTests(t, session, []replyTest{
+			{append([]string{"PFMERGE", dest}, test.sources...), "+OK\r\n"},
+			{[]string{"GETRANGE", dest, "4", "4"},
+				"$1\r\n" + test.encoding + "\r\n"},
+			{[]string{"PFCOUNT", dest}, union},
+		})
+	}
+}
@@ -1,44 +1,120 @@
 package commands
 
 import (
-	"fmt"
-	"strconv"
+	"errors"
 
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
-type CmdIncr struct {
-	BaseCommand
+var errIncrOverflow = rheltypes.NewGenericError(
+	errors.New("increment or decrement would overflow"),
+)
+
+// incrSpec is shared by INCR and DECR, INCRBY and DECRBY also take the
+// amount.
+var incrSpec = ArgSpec{
+	Arity:    2,
+	FirstKey: 1,
+	LastKey:  1,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey},
 }
 
-func NewCmdIncr() CmdIncr {
-	return CmdIncr{BaseCommand: BaseCommand("INCR")}
+var incrBySpec = ArgSpec{
+	Arity:    3,
+	FirstKey: 1,
+	LastKey:  1,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey, ArgString},
 }
 
-func (c CmdIncr) Exec(
-	args rheltypes.Array,
+// parseIncrement reads the amount of INCRBY and DECRBY, which has to be a
+// 64 bits integer in its canonical form like the values they change.
+func parseIncrement(arg rheltypes.RhelType) (int64, error) {
+	n, ok := rheltypes.ParseIntString(arg.String())
+	if !ok {
+		return 0, rheltypes.ErrNotInteger
+	}
+
+	return int64(n), nil
+}
+
+// integerValue returns the integer held by a string value, ErrNotInteger
+// if the string isn't one.
+func integerValue(value rheltypes.RhelType) (rheltypes.IntString, error) {
+	switch v := value.(type) {
+	case rheltypes.IntString:
+		return v, nil
+	case rheltypes.BulkString:
+		if n, ok := rheltypes.ParseIntString(v.String()); ok {
+			return n, nil
+		}
+
+		return 0, rheltypes.ErrNotInteger
+	default:
+		return 0, rheltypes.ErrWrongType
+	}
+}
+
+// incrBy adds delta to the integer at key, 0 when missing, and replies the
+// result. The expiration of the key is kept.
+func incrBy(
+	c BaseCommand,
+	session *Session,
+	key string,
+	delta int64,
 ) (value rheltypes.RhelType, err error) {
-	key := args.First().String()
+	var result rheltypes.IntString
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		var n rheltypes.IntString
 
-	instance := GetDataMapInstance()
+		if found {
+			if n, err = integerValue(current.Value); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+		}
 
-	num, found := instance.Get(key)
+		var ok bool
 
-	numInt := 0
+		if result, ok = n.Add(delta); !ok {
+			err = errIncrOverflow
 
-	if !found {
-		numInt = 1
-	} else if numInt, err = num.Integer(); err != nil {
-		return rheltypes.NewGenericError(
-			fmt.Errorf("value is not an integer or out of range"),
-		), nil
-	} else {
-		numInt++
+			return current, rheltypes.ComputeKeep
+		}
+
+		current.Value = result
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
 	}
 
-	value = rheltypes.Integer(numInt)
+	session.notify(notifyString, "incrby", key)
 
-	instance.Set(key, rheltypes.NewBulkString(strconv.Itoa(numInt)))
+	return rheltypes.Integer(result), nil
+}
 
-	return value, err
+type CmdIncr struct {
+	BaseCommand
+}
+
+func NewCmdIncr() CmdIncr {
+	return CmdIncr{BaseCommand: BaseCommand("INCR")}
+}
+
+func (c CmdIncr) Spec() ArgSpec {
+	return incrSpec
+}
+
+func (c CmdIncr) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return incrBy(c.BaseCommand, session, args.First().String(), 1)
 }
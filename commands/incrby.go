@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdIncrBy struct {
+	BaseCommand
+}
+
+func NewCmdIncrBy() CmdIncrBy {
+	return CmdIncrBy{BaseCommand: BaseCommand("INCRBY")}
+}
+
+func (c CmdIncrBy) Spec() ArgSpec {
+	return incrBySpec
+}
+
+func (c CmdIncrBy) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	delta, err := parseIncrement(args.At(1))
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	return incrBy(c.BaseCommand, session, args.At(0).String(), delta)
+}
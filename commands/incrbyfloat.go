@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const (
+	// longDoublePrec is the mantissa length of the x87 long doubles Redis
+	// computes with, longDoubleMaxExp bounds their binary exponent.
+	longDoublePrec   = 64
+	longDoubleMaxExp = 16384
+
+	// longDoubleDecimals is the number of decimals Redis formats long
+	// doubles with.
+	longDoubleDecimals = 17
+)
+
+var errIncrNotFinite = rheltypes.NewGenericError(
+	errors.New("increment would produce NaN or Infinity"),
+)
+
+// newLongDouble returns a zero float with the precision of a long double.
+func newLongDouble() *big.Float {
+	return new(big.Float).SetPrec(longDoublePrec)
+}
+
+// isFiniteLongDouble reports whether f is within the range of a long
+// double.
+func isFiniteLongDouble(f *big.Float) bool {
+	if f.IsInf() {
+		return false
+	}
+
+	exp := f.MantExp(nil)
+
+	return exp <= longDoubleMaxExp && exp >= -longDoubleMaxExp
+}
+
+// parseLongDouble reads a float the way Redis reads long doubles: without
+// surrounding spaces, never NaN nor out of the range of a long double.
+func parseLongDouble(text string) (*big.Float, error) {
+	if text == "" || strings.TrimSpace(text) != text {
+		return nil, rheltypes.ErrNotFloat
+	}
+
+	f, _, err := newLongDouble().Parse(text, 10)
+	if err != nil || !isFiniteLongDouble(f) {
+		return nil, rheltypes.ErrNotFloat
+	}
+
+	return f, nil
+}
+
+// formatLongDouble renders f the way Redis renders long doubles for
+// humans, like %.17Lf does: in fixed point with longDoubleDecimals
+// decimals, less the trailing zeros and the dot, never with an exponent.
+func formatLongDouble(f *big.Float) string {
+	text := f.Text('f', longDoubleDecimals)
+
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+
+	if text == "-0" {
+		return "0"
+	}
+
+	return text
+}
+
+type CmdIncrByFloat struct {
+	BaseCommand
+}
+
+func NewCmdIncrByFloat() CmdIncrByFloat {
+	return CmdIncrByFloat{BaseCommand: BaseCommand("INCRBYFLOAT")}
+}
+
+func (c CmdIncrByFloat) Spec() ArgSpec {
+	return incrBySpec
+}
+
+// Exec adds the amount to the float at key, 0 when missing, and replies
+// the result as a string. The expiration of the key is kept.
+func (c CmdIncrByFloat) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	key := args.At(0).String()
+
+	delta, err := parseLongDouble(args.At(1).String())
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	var result rheltypes.BulkString
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		f := newLongDouble()
+
+		if found {
+			var text string
+
+			if text, err = stringValue(current.Value); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+
+			if f, err = parseLongDouble(text); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+		}
+
+		if !isFiniteLongDouble(f.Add(f, delta)) {
+			err = errIncrNotFinite
+
+			return current, rheltypes.ComputeKeep
+		}
+
+		result = rheltypes.NewBulkString(formatLongDouble(f))
+		current.Value = result
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	session.notify(notifyString, "incrbyfloat", key)
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package commands
+
+import "testing"
+
+func TestIncrByFloatPrecision(t *testing.T) {
+	tests := []struct {
+		current, delta, want string
+	}{
+		{"0.1", "0.2", "0.3"},
+		{"10.5", "0.1", "10.6"},
+		{"1.5", "1.1", "2.6"},
+		{"5.0e3", "2.0e4", "25000"},
+		{"17179869184", "1.5", "17179869185.5"},
+		{"3", "-3", "0"},
+		{"-0.1", "-0.2", "-0.3"},
+		{"0", "0.0001", "0.0001"},
+		{"0", "0.00001", "0.00001"},
+		{"1e17", "0", "100000000000000000"},
+		{"1e16", "1", "10000000000000001"},
+		{"0", "1e-18", "0"},
+		{"-1e-18", "0", "0"},
+	}
+
+	for _, test := range tests {
+		f, err := parseLongDouble(test.current)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %s", test.current, err)
+		}
+
+		delta, err := parseLongDouble(test.delta)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %s", test.delta, err)
+		}
+
+		got := formatLongDouble(f.Add(f, delta))
+		if got != test.want {
+			t.Errorf(
+				"%s + %s = %s, want %s",
+				test.current, test.delta, got, test.want,
+			)
+		}
+	}
+}
+
+func TestParseLongDoubleRejects(t *testing.T) {
+	invalid := []string{"", " 1", "1 ", "nan", "inf", "1e5000", "x"}
+
+	for _, text := range invalid {
+		if _, err := parseLongDouble(text); err == nil {
+			t.Errorf("parsed %q, want an error", text)
+		}
+	}
+}
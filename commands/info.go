@@ -15,24 +15,59 @@ func NewCmdInfo() CmdInfo {
 	return CmdInfo{BaseCommand: BaseCommand("INFO")}
 }
 
+func (c CmdInfo) Spec() ArgSpec {
+	return ArgSpec{Arity: -1, Variadic: true}
+}
+
+// infoSection is a section of the INFO reply, rendering its lines.
+type infoSection struct {
+	name   string
+	title  string
+	render func(c CmdInfo, session *Session) []string
+}
+
+var infoSections = []infoSection{
+	{"replication", "Replication", CmdInfo.subCmdReplication},
+	{"stats", "Stats", CmdInfo.subCmdStats},
+	{"memory", "Memory", CmdInfo.subCmdMemory},
+}
+
+// Exec replies the sections named by args, every section without any or
+// with all or default. Unknown sections are left out, the reply is empty
+// if none is known.
 func (c CmdInfo) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
-	subCmd := args.First()
-	if subCmd == nil {
-		return nil, c.ErrWrap(fmt.Errorf("expected sub command"))
+	names := make(map[string]bool, len(args))
+
+	for _, arg := range args {
+		names[strings.ToLower(arg.String())] = true
 	}
 
-	switch subCmd.String() {
-	case "replication":
-		return c.subCmdReplication()
-	default:
-		return nil, fmt.Errorf("unrecognized sub command %q", subCmd)
+	all := len(args) == 0 || names["all"] || names["default"] ||
+		names["everything"]
+
+	sections := make([]string, 0, len(infoSections))
+
+	for _, section := range infoSections {
+		if !all && !names[section.name] {
+			continue
+		}
+
+		lines := append(
+			[]string{"# " + section.title},
+			section.render(c, session)...,
+		)
+
+		sections = append(sections, strings.Join(lines, "\n"))
 	}
+
+	return rheltypes.NewBulkString(strings.Join(sections, "\n\n")), nil
 }
 
-func (c CmdInfo) subCmdReplication() (value rheltypes.RhelType, err error) {
-	config := GetConfigMapInstance()
+func (c CmdInfo) subCmdReplication(session *Session) []string {
+	config := session.Instance().Config()
 
 	fields := []string{"role", "master_replid", "master_repl_offset"}
 	str := make([]string, 0, len(fields))
@@ -46,7 +81,29 @@ func (c CmdInfo) subCmdReplication() (value rheltypes.RhelType, err error) {
 		str = append(str, fmt.Sprintf("%s:%s", key, value))
 	}
 
-	value = rheltypes.NewBulkString(strings.Join(str, "\n"))
+	return str
+}
+
+func (c CmdInfo) subCmdStats(session *Session) []string {
+	stats := session.Instance().ExpireStats()
+
+	return []string{
+		fmt.Sprintf("expired_keys:%d", stats.ExpiredKeys),
+		fmt.Sprintf("expired_stale_perc:%.2f", stats.StalePerc),
+		fmt.Sprintf(
+			"expired_time_cap_reached_count:%d",
+			stats.TimeCapReached,
+		),
+		fmt.Sprintf("evicted_keys:%d", session.Instance().EvictedKeys()),
+	}
+}
+
+func (c CmdInfo) subCmdMemory(session *Session) []string {
+	instance := session.Instance()
 
-	return
+	return []string{
+		fmt.Sprintf("used_memory:%d", instance.UsedMemory()),
+		fmt.Sprintf("maxmemory:%d", instance.MaxMemory()),
+		fmt.Sprintf("maxmemory_policy:%s", instance.EvictionPolicy()),
+	}
 }
@@ -0,0 +1,394 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/connection"
+	"github.com/codecrafters-io/redis-starter-go/internal"
+	"github.com/codecrafters-io/redis-starter-go/pubsub"
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const (
+	defaultMapCleanupInterval = 100 * time.Millisecond
+	defaultMasterReplId       = "8371b4fb1155b71f4a04d3e1bc3e18c4a990aeeb"
+	defaultDbFilename         = "dump.rdb"
+)
+
+// Instance holds the whole state of a server: its logical databases,
+// configuration, replicas and pub/sub broker. Commands reach it through
+// their Session.
+type Instance struct {
+	dbs      []*rheltypes.SafeMap
+	dbsLock  sync.RWMutex
+	config   *rheltypes.SafeMap
+	replicas *connection.ConnectionPool
+	offset   *connection.OffsetTracker
+	broker   *pubsub.StreamManager
+	eviction evictor
+	locks    keyLocks
+	// notifications holds the notifyClass enabled by
+	// notify-keyspace-events.
+	notifications atomic.Int64
+}
+
+// NewInstance creates an instance with the given number of databases, at
+// least one.
+func NewInstance(databases int) *Instance {
+	dbs := make([]*rheltypes.SafeMap, max(databases, 1))
+
+	for index := range dbs {
+		dbs[index] = rheltypes.NewSafeMap(defaultMapCleanupInterval)
+	}
+
+	config := rheltypes.NewSafeMap(0)
+	config.SetString("databases", strconv.Itoa(len(dbs)), 0)
+
+	i := &Instance{
+		dbs:      dbs,
+		config:   config,
+		replicas: connection.NewConnectionPool(),
+		offset:   connection.NewOffsetTracker(),
+		broker:   pubsub.NewStreamManager(),
+	}
+
+	i.SetConfig("maxmemory", "0")
+	i.SetConfig("maxmemory-policy", rheltypes.NoEviction.String())
+	i.SetConfig("maxmemory-samples", strconv.Itoa(defaultMaxMemorySamples))
+	i.SetConfig("notify-keyspace-events", "")
+
+	for _, db := range dbs {
+		i.reportKeyEvents(db)
+	}
+
+	return i
+}
+
+// DB returns the keyspace of the database at index, which has to be lower
+// than Databases.
+func (i *Instance) DB(index int) *rheltypes.SafeMap {
+	i.dbsLock.RLock()
+	defer i.dbsLock.RUnlock()
+
+	return i.dbs[index]
+}
+
+func (i *Instance) Databases() int {
+	return len(i.dbs)
+}
+
+// ExpireStats sums the expiry stats of every database, the stale
+// percentage is weighted by their number of volatile keys.
+func (i *Instance) ExpireStats() (total rheltypes.ExpireStats) {
+	var staleKeys float64
+
+	for index := range i.Databases() {
+		stats := i.DB(index).Stats()
+
+		total.ExpiredKeys += stats.ExpiredKeys
+		total.TimeCapReached += stats.TimeCapReached
+		total.VolatileKeys += stats.VolatileKeys
+		staleKeys += stats.StalePerc * float64(stats.VolatileKeys)
+	}
+
+	if total.VolatileKeys > 0 {
+		total.StalePerc = staleKeys / float64(total.VolatileKeys)
+	}
+
+	return total
+}
+
+// SwapDB exchanges the content of two databases, the sessions that have
+// one of them selected see the other one right away.
+func (i *Instance) SwapDB(first, second int) {
+	i.dbsLock.Lock()
+	defer i.dbsLock.Unlock()
+
+	i.dbs[first], i.dbs[second] = i.dbs[second], i.dbs[first]
+	i.eviction.dropPool()
+}
+
+func (i *Instance) Config() *rheltypes.SafeMap {
+	return i.config
+}
+
+// SetConfig changes one of the parameters that can be set at runtime, the
+// value is validated and the new setting applied right away.
+func (i *Instance) SetConfig(name, value string) error {
+	switch name = strings.ToLower(name); name {
+	case "maxmemory":
+		limit, err := parseMemory(value)
+		if err != nil {
+			return err
+		}
+
+		i.eviction.maxMemory.Store(limit)
+		value = strconv.FormatInt(limit, 10)
+	case "maxmemory-policy":
+		policy, found := rheltypes.ParseEvictionPolicy(value)
+		if !found {
+			return errors.New("argument(s) must be one of the following: " +
+				"volatile-lru, allkeys-lru, volatile-lfu, allkeys-lfu, " +
+				"volatile-random, allkeys-random, volatile-ttl, noeviction")
+		}
+
+		i.eviction.policy.Store(int64(policy))
+		i.eviction.dropPool()
+		value = policy.String()
+	case "maxmemory-samples":
+		samples, err := strconv.Atoi(value)
+		if err != nil || samples < 1 || samples > 64 {
+			return errors.New("argument must be between 1 and 64 inclusive")
+		}
+
+		i.eviction.samples.Store(int64(samples))
+	case "notify-keyspace-events":
+		classes, err := parseNotifyClasses(value)
+		if err != nil {
+			return err
+		}
+
+		i.notifications.Store(int64(classes))
+		value = classes.String()
+	default:
+		return errUnknownConfig
+	}
+
+	i.config.SetString(name, value, 0)
+
+	return nil
+}
+
+// Replicas are the connections of the replicas fed by this instance.
+func (i *Instance) Replicas() *connection.ConnectionPool {
+	return i.replicas
+}
+
+// Offset tracks the replication stream received from the master.
+func (i *Instance) Offset() *connection.OffsetTracker {
+	return i.offset
+}
+
+func (i *Instance) Broker() *pubsub.StreamManager {
+	return i.broker
+}
+
+// SetRole records the replication role, replicaOf is the address of the
+// master and empty for a master.
+func (i *Instance) SetRole(replicaOf string) {
+	if replicaOf == "" {
+		i.config.SetString("role", "master", 0)
+		i.config.SetString("master_replid", defaultMasterReplId, 0)
+		i.config.SetString("master_repl_offset", "0", 0)
+
+		return
+	}
+
+	i.config.SetString("role", "slave", 0)
+	i.config.SetString("replicaof", replicaOf, 0)
+}
+
+func (i *Instance) IsReplica() bool {
+	role, found := i.config.Get("role")
+
+	return found && role.String() == "slave"
+}
+
+// LoadDbFile fills the keyspace from the RDB file at dbPath, the file is
+// created empty when it doesn't exist yet.
+func (i *Instance) LoadDbFile(dbPath string) (err error) {
+	if _, err = os.Stat(dbPath); err == nil {
+		return i.readDbFile(dbPath)
+	} else if os.IsNotExist(err) {
+		return createDbFile(dbPath)
+	}
+
+	return fmt.Errorf("error reading %q: %q", dbPath, err)
+}
+
+func (i *Instance) readDbFile(dbPath string) (err error) {
+	file, err := os.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("error reading content of %q: %w", dbPath, err)
+	}
+
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("error closing %q: %w", dbPath, closeErr)
+		}
+	}()
+
+	iter := internal.NewByteIteratorFromFile(file)
+
+	rdbFile, err := internal.ReadRdbFile(iter)
+	if err != nil {
+		return fmt.Errorf("error parsing %q: %w", dbPath, err)
+	}
+
+	return i.load(rdbFile)
+}
+
+// LoadDbContent replaces the keyspace with the RDB file content, e.g. the
+// snapshot received from the master.
+func (i *Instance) LoadDbContent(content []byte) error {
+	rdbFile, err := internal.ReadRdbFile(
+		internal.NewByteIteratorFromBytes(content),
+	)
+	if err != nil {
+		return fmt.Errorf("error parsing rdb content: %w", err)
+	}
+
+	for index := range i.Databases() {
+		i.DB(index).Flush()
+	}
+
+	return i.load(rdbFile)
+}
+
+func (i *Instance) load(rdbFile *internal.RdbFile) error {
+	for index, store := range rdbFile.Databases() {
+		if index >= i.Databases() {
+			return fmt.Errorf(
+				"database %d out of range, %d databases configured",
+				index,
+				i.Databases(),
+			)
+		}
+
+		db := i.DB(index)
+
+		for key, value := range store {
+			db.SetValue(key, keyspaceValue(value), value.Expiry)
+		}
+	}
+
+	return nil
+}
+
+// Snapshot renders the keys of every database into an RDB file. It fails
+// when a value can't be encoded, no key is ever left out.
+func (i *Instance) Snapshot() (*internal.RdbFile, error) {
+	rdbFile := internal.NewRdbfFile()
+
+	for index := range i.Databases() {
+		for key, entry := range i.DB(index).Entries() {
+			value, err := rdbValue(entry.Value)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+
+			value.Expiry = entry.Expiration
+			rdbFile.Set(index, key, value)
+		}
+	}
+
+	return rdbFile, nil
+}
+
+// DbPath is the location of the RDB file, from the dir and dbfilename
+// configuration.
+func (i *Instance) DbPath() string {
+	dbFilename := defaultDbFilename
+
+	if name, found := i.config.Get("dbfilename"); found {
+		dbFilename = name.String()
+	}
+
+	if dir, found := i.config.Get("dir"); found {
+		return path.Join(dir.String(), dbFilename)
+	}
+
+	return dbFilename
+}
+
+// SaveDbFile writes the snapshot of the databases to dbPath, the previous
+// file is only replaced once the new one is complete.
+func (i *Instance) SaveDbFile(dbPath string) (err error) {
+	tmpPath := dbPath + ".tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %w", tmpPath, err)
+	}
+
+	writer := bufio.NewWriter(file)
+
+	snapshot, err := i.Snapshot()
+	if err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+
+		return err
+	}
+
+	err = snapshot.WriteContent(writer)
+
+	closeWriter(file, writer, &err)
+
+	if err != nil {
+		os.Remove(tmpPath)
+
+		return err
+	}
+
+	return os.Rename(tmpPath, dbPath)
+}
+
+func closeWriter(
+	file *os.File,
+	writer *bufio.Writer,
+	err *error,
+) {
+	if errFlush := writer.Flush(); errFlush != nil {
+		errFlush = fmt.Errorf("error flushing writer: %w", errFlush)
+		if *err == nil {
+			*err = errFlush
+		} else {
+			*err = fmt.Errorf("%w; %w", *err, errFlush)
+		}
+	}
+
+	if errClose := file.Close(); errClose != nil {
+		errClose = fmt.Errorf("error closing file: %w", errClose)
+		if *err == nil {
+			*err = errClose
+		} else {
+			*err = fmt.Errorf("%w; %w", *err, errClose)
+		}
+	}
+}
+
+func createDbFile(dbPath string) (err error) {
+	file, err := os.Create(dbPath)
+	if err != nil {
+		return
+	}
+
+	writer := bufio.NewWriter(file)
+
+	err = internal.NewRdbfFile().WriteContent(writer)
+
+	defer closeWriter(file, writer, &err)
+
+	return
+}
+
+// Close releases the instance, blocked subscribers are woken up.
+func (i *Instance) Close() {
+	i.replicas.CloseAlls()
+	i.broker.Close()
+	i.config.Close()
+
+	for _, db := range i.dbs {
+		db.Close()
+	}
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"hash/maphash"
+	"slices"
+	"sync"
+)
+
+// keyLockStripes is the number of locks the keys are spread on, commands
+// on keys sharing a stripe wait for each other.
+const keyLockStripes = 1024
+
+var keyLockSeed = maphash.MakeSeed()
+
+// keyLocks runs every command atomically against the keyspace: a command
+// holds the stripes of the keys declared by its spec while it runs, along
+// with the global lock for reading. Commands reaching beyond their declared
+// keys, e.g. to other databases, hold the global lock alone. Commands
+// without keys run unlocked, they only see the keyspace through atomic
+// operations of the maps.
+type keyLocks struct {
+	global  sync.RWMutex
+	stripes [keyLockStripes]sync.Mutex
+}
+
+// keyLockSet is what a command has to lock, stripes are sorted and unique
+// so that locking them in order can't deadlock.
+type keyLockSet struct {
+	exclusive bool
+	stripes   []int
+}
+
+func keyStripe(db int, key string) int {
+	hash := maphash.String(keyLockSeed, key) + uint64(db)
+
+	return int(hash % keyLockStripes)
+}
+
+// add includes the stripe of key in the set.
+func (s *keyLockSet) add(db int, key string) {
+	s.insert(keyStripe(db, key))
+}
+
+// merge includes every lock of other in the set.
+func (s *keyLockSet) merge(other keyLockSet) {
+	s.exclusive = s.exclusive || other.exclusive
+
+	for _, stripe := range other.stripes {
+		s.insert(stripe)
+	}
+}
+
+func (s *keyLockSet) insert(stripe int) {
+	if pos, found := slices.BinarySearch(s.stripes, stripe); !found {
+		s.stripes = slices.Insert(s.stripes, pos, stripe)
+	}
+}
+
+func (l *keyLocks) lock(set keyLockSet) (unlock func()) {
+	if set.exclusive {
+		l.global.Lock()
+
+		return l.global.Unlock
+	}
+
+	if len(set.stripes) == 0 {
+		return func() {}
+	}
+
+	l.global.RLock()
+
+	for _, stripe := range set.stripes {
+		l.stripes[stripe].Lock()
+	}
+
+	return func() {
+		for _, stripe := range slices.Backward(set.stripes) {
+			l.stripes[stripe].Unlock()
+		}
+
+		l.global.RUnlock()
+	}
+}
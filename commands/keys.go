@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -14,28 +12,30 @@ func NewCmdKeys() CmdKeys {
 	return CmdKeys{BaseCommand: BaseCommand("KEYS")}
 }
 
+func (c CmdKeys) Spec() ArgSpec {
+	return ArgSpec{Arity: 2, Args: []ArgKind{ArgString}}
+}
+
+// Exec replies the keys matching the glob-style pattern, in no particular
+// order.
 func (c CmdKeys) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
-	key := args.At(0)
-	if key == nil {
-		return nil, c.ErrWrap(fmt.Errorf("missing key"))
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
 	}
 
-	instance := GetDataMapInstance()
+	pattern := spec.Args[0].String()
+	allKeys := pattern == "*"
+	keys := rheltypes.Array{}
 
-	switch query := key.String(); query {
-	case "*":
-		buf := make(rheltypes.Array, instance.Size())
-		i := 0
-
-		for k := range instance.Keys() {
-			buf[i] = rheltypes.NewBulkString(k)
-			i++
+	for key := range session.DB().Entries() {
+		if allKeys || globMatch(pattern, key) {
+			keys.Append(rheltypes.NewBulkString(key))
 		}
-
-		return buf, nil
-	default:
-		return nil, c.ErrWrap(fmt.Errorf("unsupported syntax: %q", query))
 	}
+
+	return keys, nil
 }
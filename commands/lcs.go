@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var (
+	errLcsNotString = rheltypes.NewGenericError(errors.New(
+		"The specified keys must contain string values",
+	))
+	errLcsLenAndIdx = rheltypes.NewGenericError(errors.New(
+		"If you want both the length and indexes, please just use IDX.",
+	))
+	errLcsTooLarge = rheltypes.NewGenericError(errors.New(
+		"Insufficient memory, transient memory for LCS exceeds " +
+			"proto-max-bulk-len",
+	))
+)
+
+type CmdLcs struct {
+	BaseCommand
+}
+
+func NewCmdLcs() CmdLcs {
+	return CmdLcs{BaseCommand: BaseCommand("LCS")}
+}
+
+func (c CmdLcs) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  2,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgKey},
+		Options: []OptionSpec{
+			{Token: "LEN"},
+			{Token: "IDX"},
+			{Token: "MINMATCHLEN", Values: []ArgKind{ArgInteger}},
+			{Token: "WITHMATCHLEN"},
+		},
+	}
+}
+
+// Exec replies the longest common subsequence of the strings at both keys,
+// missing keys being empty. LEN replies its length only, IDX the ranges
+// matching in both strings instead, from the last one.
+func (c CmdLcs) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	if spec.Has("LEN") && spec.Has("IDX") {
+		return nil, c.ErrWrap(errLcsLenAndIdx)
+	}
+
+	var texts [2]string
+
+	for i := range texts {
+		texts[i], _, err = getString(session.DB(), spec.Args[i].String())
+		if err != nil {
+			return nil, c.ErrWrap(errLcsNotString)
+		}
+	}
+
+	a, b := texts[0], texts[1]
+
+	if (len(a)+1)*(len(b)+1) > maxStringLength/4 {
+		return nil, c.ErrWrap(errLcsTooLarge)
+	}
+
+	table := newLcsTable(a, b)
+
+	switch {
+	case spec.Has("LEN"):
+		return rheltypes.Integer(table.at(len(a), len(b))), nil
+	case spec.Has("IDX"):
+		minLen := 0
+
+		if option := spec.Option("MINMATCHLEN"); option != nil {
+			minLen, _ = option.Integer()
+		}
+
+		matches := table.matches(minLen, spec.Has("WITHMATCHLEN"))
+
+		return rheltypes.Map{
+			{
+				Key:   rheltypes.NewBulkString("matches"),
+				Value: matches,
+			},
+			{
+				Key:   rheltypes.NewBulkString("len"),
+				Value: rheltypes.Integer(table.at(len(a), len(b))),
+			},
+		}, nil
+	default:
+		return rheltypes.NewBulkString(table.subsequence()), nil
+	}
+}
+
+// lcsTable holds the length of the longest common subsequence of every
+// pair of prefixes of a and b.
+type lcsTable struct {
+	a, b    string
+	lengths []uint32
+}
+
+func newLcsTable(a, b string) *lcsTable {
+	t := &lcsTable{
+		a:       a,
+		b:       b,
+		lengths: make([]uint32, (len(a)+1)*(len(b)+1)),
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				t.set(i, j, t.at(i-1, j-1)+1)
+			default:
+				t.set(i, j, max(t.at(i-1, j), t.at(i, j-1)))
+			}
+		}
+	}
+
+	return t
+}
+
+func (t *lcsTable) at(i, j int) uint32 {
+	return t.lengths[i*(len(t.b)+1)+j]
+}
+
+func (t *lcsTable) set(i, j int, length uint32) {
+	t.lengths[i*(len(t.b)+1)+j] = length
+}
+
+// subsequence walks the table back from the end of both strings.
+func (t *lcsTable) subsequence() string {
+	result := make([]byte, t.at(len(t.a), len(t.b)))
+	idx := len(result)
+
+	for i, j := len(t.a), len(t.b); i > 0 && j > 0; {
+		switch {
+		case t.a[i-1] == t.b[j-1]:
+			idx--
+			result[idx] = t.a[i-1]
+			i--
+			j--
+		case t.at(i-1, j) > t.at(i, j-1):
+			i--
+		default:
+			j--
+		}
+	}
+
+	return string(result)
+}
+
+// matches walks the table back like subsequence does and returns the
+// ranges of contiguous matches at least minLen long, the way Redis does.
+func (t *lcsTable) matches(minLen int, withLen bool) rheltypes.Array {
+	matches := rheltypes.Array{}
+
+	// aStart is len(a) while no range is being tracked.
+	aStart, aEnd, bStart, bEnd := len(t.a), 0, 0, 0
+
+	for i, j := len(t.a), len(t.b); i > 0 && j > 0; {
+		emit := false
+
+		if t.a[i-1] == t.b[j-1] {
+			switch {
+			case aStart == len(t.a):
+				aStart, aEnd, bStart, bEnd = i-1, i-1, j-1, j-1
+			case aStart == i && bStart == j:
+				aStart--
+				bStart--
+			default:
+				emit = true
+			}
+
+			// Emit the range once it reaches the start of either string.
+			if aStart == 0 || bStart == 0 {
+				emit = true
+			}
+
+			i--
+			j--
+		} else {
+			if t.at(i-1, j) > t.at(i, j-1) {
+				i--
+			} else {
+				j--
+			}
+
+			emit = aStart != len(t.a)
+		}
+
+		if !emit {
+			continue
+		}
+
+		if length := aEnd - aStart + 1; minLen == 0 || length >= minLen {
+			match := rheltypes.Array{
+				rheltypes.Array{
+					rheltypes.Integer(aStart),
+					rheltypes.Integer(aEnd),
+				},
+				rheltypes.Array{
+					rheltypes.Integer(bStart),
+					rheltypes.Integer(bEnd),
+				},
+			}
+
+			if withLen {
+				match = append(match, rheltypes.Integer(length))
+			}
+
+			matches = append(matches, match)
+		}
+
+		aStart = len(t.a)
+	}
+
+	return matches
+}
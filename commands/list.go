@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// listValue returns the list held by value, ErrWrongType if value is of
+// another type. The list is changed in place by the commands holding the
+// lock of its key, which store it again afterwards.
+func listValue(value rheltypes.RhelType) (*rheltypes.List, error) {
+	list, ok := value.(*rheltypes.List)
+	if !ok {
+		return nil, rheltypes.ErrWrongType
+	}
+
+	return list, nil
+}
+
+// getList returns the list stored at key, ErrWrongType if key holds another
+// type.
+func getList(
+	db *rheltypes.SafeMap,
+	key string,
+) (list *rheltypes.List, found bool, err error) {
+	current, found := db.Get(key)
+	if !found {
+		return nil, false, nil
+	}
+
+	list, err = listValue(current)
+
+	return list, true, err
+}
+
+// execPush adds the items of args to the head or the tail of the list at
+// key, created if missing, and replies its new length.
+func execPush(
+	c BaseCommand,
+	session *Session,
+	args rheltypes.Array,
+	front bool,
+) (rheltypes.RhelType, error) {
+	parsedArgs, err := NewCmdRLPushArgs(args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	instance := session.DB()
+
+	list := rheltypes.NewList()
+
+	if entry, found := instance.Peek(parsedArgs.Key); found {
+		if list, err = listValue(entry.Value); err != nil {
+			return nil, c.ErrWrap(err)
+		}
+	}
+
+	event := "rpush"
+
+	if front {
+		event = "lpush"
+		list.PushFront(parsedArgs.Items...)
+	} else {
+		list.PushBack(parsedArgs.Items...)
+	}
+
+	instance.Update(parsedArgs.Key, list)
+	session.notify(notifyList, event, parsedArgs.Key)
+	signalList(session, parsedArgs.Key, list.Len())
+
+	return rheltypes.Integer(list.Len()), nil
+}
+
+// popList removes up to count elements from the head of the list at key,
+// deleting the key once the list is empty. popped is nil if key is missing,
+// left is the length of the list afterwards.
+func popList(
+	session *Session,
+	key string,
+	count int,
+) (popped rheltypes.Array, left int, err error) {
+	instance := session.DB()
+
+	entry, found := instance.Peek(key)
+	if !found {
+		return nil, 0, nil
+	}
+
+	list, err := listValue(entry.Value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if popped = list.PopFront(count); len(popped) == 0 {
+		return popped, list.Len(), nil
+	}
+
+	session.notify(notifyList, "lpop", key)
+
+	if list.Len() == 0 {
+		instance.Delete(key)
+		session.notify(notifyGeneric, "del", key)
+
+		return popped, 0, nil
+	}
+
+	instance.Update(key, list)
+
+	return popped, list.Len(), nil
+}
+
+// signalList wakes the first client blocked on the list at key, which holds
+// length elements, unless it is empty. That client signals the list again
+// once it has popped, for the next one to be woken while elements are left.
+func signalList(session *Session, key string, length int) {
+	if length == 0 {
+		return
+	}
+
+	go session.Instance().Broker().Publish(key, rheltypes.Integer(length))
+}
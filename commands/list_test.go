@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestListCommands(t *testing.T) {
+	session := newTestSession()
+
+	runReplyTests(t, session, []replyTest{
+		{[]string{"RPUSH", "l", "a", "b", "c", "d", "e"}, ":5\r\n"},
+		{[]string{"LPUSH", "l", "z", "y"}, ":7\r\n"},
+		{[]string{"LRANGE", "l", "0", "-1"}, "*7\r\n$1\r\ny\r\n$1\r\nz\r\n" +
+			"$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n$1\r\nd\r\n$1\r\ne\r\n"},
+		{[]string{"LRANGE", "l", "-2", "-1"}, "*2\r\n$1\r\nd\r\n$1\r\ne\r\n"},
+		{[]string{"LRANGE", "l", "-100", "0"}, "*1\r\n$1\r\ny\r\n"},
+		{[]string{"LRANGE", "l", "6", "100"}, "*1\r\n$1\r\ne\r\n"},
+		{[]string{"LRANGE", "l", "3", "1"}, "*0\r\n"},
+		{[]string{"LRANGE", "l", "7", "10"}, "*0\r\n"},
+		{[]string{"LRANGE", "missing", "0", "-1"}, "*0\r\n"},
+		{[]string{"LPOP", "l"}, "$1\r\ny\r\n"},
+		{[]string{"LPOP", "l", "2"}, "*2\r\n$1\r\nz\r\n$1\r\na\r\n"},
+		{[]string{"LPOP", "l", "0"}, "*0\r\n"},
+		{[]string{"LPOP", "l", "-1"},
+			"-ERR value is out of range, must be positive\r\n"},
+		{[]string{"LPOP", "l", "10"},
+			"*4\r\n$1\r\nb\r\n$1\r\nc\r\n$1\r\nd\r\n$1\r\ne\r\n"},
+		{[]string{"EXISTS", "l"}, ":0\r\n"},
+		{[]string{"LPOP", "l"}, "$-1\r\n"},
+		{[]string{"LPOP", "l", "2"}, "*-1\r\n"},
+		{[]string{"LLEN", "l"}, ":0\r\n"},
+	})
+}
+
+func TestCopyListIsIndependent(t *testing.T) {
+	session := newTestSession()
+
+	runReplyTests(t, session, []replyTest{
+		{[]string{"RPUSH", "src", "a", "b", "c"}, ":3\r\n"},
+		{[]string{"COPY", "src", "dst"}, ":1\r\n"},
+		{[]string{"LPOP", "src", "2"}, "*2\r\n$1\r\na\r\n$1\r\nb\r\n"},
+		{[]string{"RPUSH", "dst", "d"}, ":4\r\n"},
+		{[]string{"LRANGE", "src", "0", "-1"}, "*1\r\n$1\r\nc\r\n"},
+		{[]string{"LRANGE", "dst", "0", "-1"},
+			"*4\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n$1\r\nd\r\n"},
+	})
+}
+
+func TestBLPopReplies(t *testing.T) {
+	session := newTestSession()
+
+	runReplyTests(t, session, []replyTest{
+		{[]string{"BLPOP", "missing", "0.01"}, "*-1\r\n"},
+		{[]string{"RPUSH", "l", "a"}, ":1\r\n"},
+		{[]string{"BLPOP", "missing", "l", "0.01"},
+			"*2\r\n$1\r\nl\r\n$1\r\na\r\n"},
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"BLPOP", "l", "0"}, "+QUEUED\r\n"},
+		{[]string{"EXEC"}, "*1\r\n*-1\r\n"},
+	})
+
+	execute(t, session, "HELLO", "3")
+
+	runReplyTests(t, session, []replyTest{
+		{[]string{"BLPOP", "missing", "0.01"}, "_\r\n"},
+	})
+}
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -14,18 +12,30 @@ func NewCmdLLen() CmdLLen {
 	return CmdLLen{BaseCommand: BaseCommand("LLEN")}
 }
 
+func (c CmdLLen) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+	}
+}
+
 func (c CmdLLen) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(0).String()
 
-	instance := GetDataMapInstance()
+	list, found, err := getList(session.DB(), key)
 
-	if value, found := instance.Get(key); !found {
+	switch {
+	case err != nil:
+		return nil, c.ErrWrap(err)
+	case !found:
 		return rheltypes.Integer(0), nil
-	} else if list, ok := value.(rheltypes.Array); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
-	} else {
-		return rheltypes.Integer(len(list)), nil
+	default:
+		return rheltypes.Integer(list.Len()), nil
 	}
 }
@@ -14,46 +14,45 @@ func NewCmdLPop() CmdLPop {
 	return CmdLPop{BaseCommand: BaseCommand("LPOP")}
 }
 
+func (c CmdLPop) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgInteger},
+	}
+}
+
 func (c CmdLPop) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(0).String()
 
-	start := 0
-
-	if startArg := args.At(1); startArg != nil {
-		start, _ = startArg.Integer()
-	}
-
-	instance := GetDataMapInstance()
-
-	value, found := instance.Get(key)
+	count := 1
+	countArg := args.At(1)
 
-	var list rheltypes.Array
-
-	var ok bool
-
-	if !found {
-		return rheltypes.NewBulkString("-1"), nil
-	} else if list, ok = value.(rheltypes.Array); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
+	if countArg != nil {
+		if count, _ = countArg.Integer(); count < 0 {
+			return nil, rheltypes.NewGenericError(
+				fmt.Errorf("value is out of range, must be positive"),
+			)
+		}
 	}
 
-	if len(list) == 0 {
-		return rheltypes.NewBulkString("-1"), nil
+	popped, _, err := popList(session, key, count)
+
+	switch {
+	case err != nil:
+		return nil, c.ErrWrap(err)
+	case popped == nil && countArg == nil:
+		return rheltypes.NewNullBulkString(), nil
+	case popped == nil:
+		return rheltypes.NullArray{}, nil
+	case countArg == nil:
+		return popped[0], nil
+	default:
+		return popped, nil
 	}
-
-	var output rheltypes.RhelType
-
-	if start == 0 {
-		output = list.At(0)
-		list = list[1:]
-	} else {
-		output = list.Range(0, start-1)
-		list = list[min(start, len(list)):]
-	}
-
-	instance.Set(key, list)
-
-	return output, nil
 }
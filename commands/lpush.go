@@ -1,9 +1,6 @@
 package commands
 
 import (
-	"fmt"
-	"slices"
-
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -15,41 +12,20 @@ func NewCmdLPush() CmdLPush {
 	return CmdLPush{BaseCommand: BaseCommand("LPUSH")}
 }
 
+func (c CmdLPush) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+		Variadic: true,
+	}
+}
+
 func (c CmdLPush) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
-	parsedArgs, err := NewCmdRLPushArgs(args)
-	if err != nil {
-		return nil, c.ErrWrap(err)
-	}
-
-	instance := GetDataMapInstance()
-
-	value, found := instance.Get(parsedArgs.Key)
-
-	var list rheltypes.Array
-
-	var ok bool
-
-	if !found {
-		list = make(rheltypes.Array, 0)
-	} else if list, ok = value.(rheltypes.Array); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
-	}
-
-	updated := make(rheltypes.Array, len(list)+len(parsedArgs.Items))
-	i := 0
-
-	for _, item := range slices.Backward(parsedArgs.Items) {
-		updated[i] = item
-		i++
-	}
-
-	if len(list) > 0 {
-		copy(updated[len(parsedArgs.Items):], list)
-	}
-
-	instance.Set(parsedArgs.Key, updated)
-
-	return rheltypes.Integer(len(updated)), nil
+	return execPush(c.BaseCommand, session, args, true)
 }
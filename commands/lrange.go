@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -14,6 +12,16 @@ func NewCmdLRange() CmdLRange {
 	return CmdLRange{BaseCommand: BaseCommand("LRANGE")}
 }
 
+func (c CmdLRange) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    4,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgInteger, ArgInteger},
+	}
+}
+
 const (
 	cmdLRangeKeyArg   = 0
 	cmdLRangeStartArg = 1
@@ -21,25 +29,21 @@ const (
 )
 
 func (c CmdLRange) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(cmdLRangeKeyArg).String()
 	start, _ := args.At(cmdLRangeStartArg).Integer()
 	stop, _ := args.At(cmdLRangeStopArg).Integer()
 
-	instance := GetDataMapInstance()
+	list, found, err := getList(session.DB(), key)
 
-	value, found := instance.Get(key)
-
-	var list rheltypes.Array
-
-	var ok bool
-
-	if !found {
+	switch {
+	case err != nil:
+		return nil, c.ErrWrap(err)
+	case !found:
 		return rheltypes.Array{}, nil
-	} else if list, ok = value.(rheltypes.Array); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
+	default:
+		return list.Range(start, stop), nil
 	}
-
-	return list.Range(start, stop), nil
 }
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdMGet struct {
+	BaseCommand
+}
+
+func NewCmdMGet() CmdMGet {
+	return CmdMGet{BaseCommand: BaseCommand("MGET")}
+}
+
+func (c CmdMGet) Spec() ArgSpec {
+	return multiKeySpec
+}
+
+// Exec replies the values of the keys in order, null for the missing ones
+// and the ones that don't hold a string.
+func (c CmdMGet) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	values := make(rheltypes.Array, len(args))
+	db := session.DB()
+
+	for i, key := range args {
+		current, found := db.Get(key.String())
+		if !found || !isStringValue(current) {
+			current = rheltypes.NewNullBulkString()
+		}
+
+		values[i] = current
+	}
+
+	return values, nil
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var errMoveSameDb = rheltypes.NewGenericError(
+	errors.New("source and destination objects are the same"),
+)
+
+type CmdMove struct {
+	BaseCommand
+}
+
+func NewCmdMove() CmdMove {
+	return CmdMove{BaseCommand: BaseCommand("MOVE")}
+}
+
+func (c CmdMove) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgInteger},
+	}
+}
+
+const (
+	cmdMoveKeyArg = 0
+	cmdMoveDbArg  = 1
+)
+
+// Exec moves the key to the destination database along with its expiry,
+// nothing is moved when the destination already holds the key.
+func (c CmdMove) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	key := args.At(cmdMoveKeyArg).String()
+	index, _ := args.At(cmdMoveDbArg).Integer()
+
+	instance := session.Instance()
+
+	if index < 0 || index >= instance.Databases() {
+		return nil, c.ErrWrap(rheltypes.ErrDbIndexOutOfRange)
+	}
+
+	if index == session.SelectedDB() {
+		return nil, c.ErrWrap(errMoveSameDb)
+	}
+
+	source := session.DB()
+
+	entry, found := source.Entry(key)
+	if !found || !instance.DB(index).SetEntryIfAbsent(key, entry) {
+		return rheltypes.Integer(0), nil
+	}
+
+	source.Delete(key)
+
+	session.notify(notifyGeneric, "move_from", key)
+	instance.notify(notifyGeneric, "move_to", index, key)
+
+	return rheltypes.Integer(1), nil
+}
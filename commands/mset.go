@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// msetSpec is shared by MSET and MSETNX, which take key value pairs.
+var msetSpec = ArgSpec{
+	Arity:    -3,
+	FirstKey: 1,
+	LastKey:  -1,
+	KeyStep:  2,
+	Args:     []ArgKind{ArgKey, ArgString},
+	Variadic: true,
+}
+
+// parseKeyValues splits the arguments of MSET and MSETNX into the values
+// to set, an odd number of arguments is an arity error.
+func parseKeyValues(
+	name string,
+	args rheltypes.Array,
+) (pairs []CmdSetArgs, err error) {
+	if len(args)%2 != 0 {
+		return nil, newArityError(name)
+	}
+
+	pairs = make([]CmdSetArgs, 0, len(args)/2)
+
+	for i := 0; i < len(args); i += 2 {
+		pairs = append(pairs, CmdSetArgs{
+			Key:   args[i].String(),
+			Value: args[i+1],
+		})
+	}
+
+	return pairs, nil
+}
+
+type CmdMSet struct {
+	BaseCommand
+}
+
+func NewCmdMSet() CmdMSet {
+	return CmdMSet{BaseCommand: BaseCommand("MSET")}
+}
+
+func (c CmdMSet) Spec() ArgSpec {
+	return msetSpec
+}
+
+// Exec sets every key like SET does, all of them at once since their keys
+// are locked for the whole command.
+func (c CmdMSet) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	pairs, err := parseKeyValues(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	for _, pair := range pairs {
+		pair.set(session)
+	}
+
+	return rheltypes.SimpleString("OK"), nil
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdMSetNx struct {
+	BaseCommand
+}
+
+func NewCmdMSetNx() CmdMSetNx {
+	return CmdMSetNx{BaseCommand: BaseCommand("MSETNX")}
+}
+
+func (c CmdMSetNx) Spec() ArgSpec {
+	return msetSpec
+}
+
+// Exec sets the keys like MSET, unless any of them exists, and replies
+// whether they were set.
+func (c CmdMSetNx) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	pairs, err := parseKeyValues(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	db := session.DB()
+
+	for _, pair := range pairs {
+		if _, found := db.Peek(pair.Key); found {
+			return rheltypes.Integer(0), nil
+		}
+	}
+
+	for _, pair := range pairs {
+		pair.set(session)
+	}
+
+	return rheltypes.Integer(1), nil
+}
@@ -12,6 +12,13 @@ func NewCmdMulti() CmdMulti {
 	return CmdMulti{BaseCommand: BaseCommand("MULTI")}
 }
 
-func (c CmdMulti) Exec(args rheltypes.Array) (rheltypes.RhelType, error) {
+func (c CmdMulti) Spec() ArgSpec {
+	return ArgSpec{Arity: 1}
+}
+
+func (c CmdMulti) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (rheltypes.RhelType, error) {
 	return rheltypes.SimpleString("OK"), nil
 }
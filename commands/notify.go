@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// notifyClass is a set of keyspace event classes, the flags of
+// notify-keyspace-events.
+type notifyClass int64
+
+const (
+	notifyKeyspace  notifyClass = 1 << iota // K
+	notifyKeyevent                          // E
+	notifyGeneric                           // g
+	notifyString                            // $
+	notifyList                              // l
+	notifySet                               // s
+	notifyHash                              // h
+	notifySortedSet                         // z
+	notifyExpired                           // x
+	notifyEvicted                           // e
+	notifyStream                            // t
+	notifyKeyMiss                           // m
+	notifyNew                               // n
+
+	// notifyAll is the alias A, the key miss and new key events have to
+	// be asked for explicitly.
+	notifyAll = notifyGeneric | notifyString | notifyList | notifySet |
+		notifyHash | notifySortedSet | notifyExpired | notifyEvicted |
+		notifyStream
+)
+
+var errInvalidEventClass = errors.New(
+	"Invalid event class character. Use 'Ag$lshzxeKEtmn'.",
+)
+
+// notifyFlags maps each class to its character, in the order Redis renders
+// them.
+var notifyFlags = []struct {
+	class notifyClass
+	flag  byte
+}{
+	{notifyGeneric, 'g'},
+	{notifyString, '$'},
+	{notifyList, 'l'},
+	{notifySet, 's'},
+	{notifyHash, 'h'},
+	{notifySortedSet, 'z'},
+	{notifyExpired, 'x'},
+	{notifyEvicted, 'e'},
+	{notifyStream, 't'},
+	{notifyKeyspace, 'K'},
+	{notifyKeyevent, 'E'},
+	{notifyKeyMiss, 'm'},
+	{notifyNew, 'n'},
+}
+
+func parseNotifyClasses(value string) (classes notifyClass, err error) {
+	for _, flag := range []byte(value) {
+		if flag == 'A' {
+			classes |= notifyAll
+
+			continue
+		}
+
+		i := indexNotifyFlag(flag)
+		if i < 0 {
+			return 0, errInvalidEventClass
+		}
+
+		classes |= notifyFlags[i].class
+	}
+
+	return classes, nil
+}
+
+func indexNotifyFlag(flag byte) int {
+	for i, f := range notifyFlags {
+		if f.flag == flag {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// String renders the classes the way CONFIG GET replies them, with A
+// standing for all of its classes.
+func (c notifyClass) String() string {
+	var b strings.Builder
+
+	if c&notifyAll == notifyAll {
+		b.WriteByte('A')
+		c &^= notifyAll
+	}
+
+	for _, f := range notifyFlags {
+		if c&f.class != 0 {
+			b.WriteByte(f.flag)
+		}
+	}
+
+	return b.String()
+}
+
+// notify publishes the event of class about key of the database db, to the
+// keyspace channel of the key and to the keyevent channel of the event, as
+// far as notify-keyspace-events enables them.
+func (i *Instance) notify(
+	class notifyClass,
+	event string,
+	db int,
+	key string,
+) {
+	classes := notifyClass(i.notifications.Load())
+	if classes&class == 0 {
+		return
+	}
+
+	prefix := "@" + strconv.Itoa(db) + "__:"
+
+	if classes&notifyKeyspace != 0 {
+		i.broker.Publish(
+			"__keyspace"+prefix+key,
+			rheltypes.NewBulkString(event),
+		)
+	}
+
+	if classes&notifyKeyevent != 0 {
+		i.broker.Publish(
+			"__keyevent"+prefix+event,
+			rheltypes.NewBulkString(key),
+		)
+	}
+}
+
+// reportKeyEvents notifies the key events of db under the index it has when
+// they happen, since SWAPDB moves the databases around.
+func (i *Instance) reportKeyEvents(db *rheltypes.SafeMap) {
+	db.OnKeyEvent(func(event rheltypes.KeyEvent, key string) {
+		index := i.indexOf(db)
+		if index < 0 {
+			return
+		}
+
+		switch event {
+		case rheltypes.KeyExpired:
+			i.notify(notifyExpired, "expired", index, key)
+		case rheltypes.KeyAdded:
+			i.notify(notifyNew, "new", index, key)
+		case rheltypes.KeyMissed:
+			i.notify(notifyKeyMiss, "keymiss", index, key)
+		}
+	})
+}
+
+// indexOf returns the index of db, -1 if it isn't one of the databases.
+func (i *Instance) indexOf(db *rheltypes.SafeMap) int {
+	i.dbsLock.RLock()
+	defer i.dbsLock.RUnlock()
+
+	for index, current := range i.dbs {
+		if current == db {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// notify publishes an event about key of the selected database.
+func (s *Session) notify(class notifyClass, event, key string) {
+	s.instance.notify(class, event, s.SelectedDB(), key)
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const policySwitchNote = "Please note that when switching between " +
+	"policies at runtime LRU and LFU data will take some time to adjust."
+
+var (
+	errIdleTimeNotTracked = rheltypes.NewGenericError(errors.New(
+		"An LFU maxmemory policy is selected, idle time not tracked. " +
+			policySwitchNote,
+	))
+	errFreqNotTracked = rheltypes.NewGenericError(errors.New(
+		"An LFU maxmemory policy is not selected, access frequency not " +
+			"tracked. " + policySwitchNote,
+	))
+)
+
+var objectHelp = []string{
+	"OBJECT <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"ENCODING <key>",
+	"    Return the kind of internal representation used in order to " +
+		"store the value",
+	"    associated with a <key>.",
+	"FREQ <key>",
+	"    Return the access frequency index of the <key>. The returned " +
+		"integer is",
+	"    proportional to the logarithm of the recent access frequency of " +
+		"the key.",
+	"IDLETIME <key>",
+	"    Return the idle time of the <key>, that is the approximated " +
+		"number of",
+	"    seconds elapsed since the last access to the key.",
+	"REFCOUNT <key>",
+	"    Return the number of references of the value associated with " +
+		"the specified",
+	"    <key>.",
+	"HELP",
+	"    Print this help.",
+}
+
+type CmdObject struct {
+	BaseCommand
+}
+
+func NewCmdObject() CmdObject {
+	return CmdObject{BaseCommand: BaseCommand("OBJECT")}
+}
+
+func (c CmdObject) Spec() ArgSpec {
+	return ArgSpec{Arity: -2, Variadic: true}
+}
+
+// Exec inspects a key without counting as an access to it, a missing key
+// is replied with a null.
+func (c CmdObject) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	subcmd := args.Cmd()
+
+	if subcmd == "HELP" {
+		return rheltypes.NewArrayFromStrings(objectHelp), nil
+	}
+
+	switch subcmd {
+	case "ENCODING", "FREQ", "IDLETIME", "REFCOUNT":
+	default:
+		return nil, rheltypes.NewErrorf(
+			rheltypes.GenericErrorType,
+			"unknown subcommand '%s'. Try OBJECT HELP.",
+			args.First(),
+		)
+	}
+
+	if len(args) != 2 {
+		return nil, newArityError("object|" + strings.ToLower(subcmd))
+	}
+
+	entry, found := session.DB().Peek(args[1].String())
+	if !found {
+		return rheltypes.NewNullBulkString(), nil
+	}
+
+	lfu := session.Instance().EvictionPolicy().LFU()
+
+	switch subcmd {
+	case "ENCODING":
+		return rheltypes.NewBulkString(rheltypes.Encoding(entry.Value)), nil
+	case "FREQ":
+		if !lfu {
+			return nil, c.ErrWrap(errFreqNotTracked)
+		}
+
+		return rheltypes.Integer(entry.Frequency()), nil
+	case "IDLETIME":
+		if lfu {
+			return nil, c.ErrWrap(errIdleTimeNotTracked)
+		}
+
+		return rheltypes.Integer(int(entry.Idle().Seconds())), nil
+	default:
+		// Values are never shared between keys.
+		return rheltypes.Integer(1), nil
+	}
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPersist struct {
+	BaseCommand
+}
+
+func NewCmdPersist() CmdPersist {
+	return CmdPersist{BaseCommand: BaseCommand("PERSIST")}
+}
+
+func (c CmdPersist) Spec() ArgSpec {
+	return ttlSpec
+}
+
+// Exec removes the expiration of the key, replying 0 when the key is
+// missing or has none.
+func (c CmdPersist) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	hasExpiry := func(current int64) bool { return current != 0 }
+
+	key := args.At(0).String()
+
+	if !session.DB().Expire(key, 0, hasExpiry) {
+		return rheltypes.Integer(0), nil
+	}
+
+	session.notify(notifyGeneric, "persist", key)
+
+	return rheltypes.Integer(1), nil
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPExpire struct {
+	BaseCommand
+}
+
+func NewCmdPExpire() CmdPExpire {
+	return CmdPExpire{BaseCommand: BaseCommand("PEXPIRE")}
+}
+
+func (c CmdPExpire) Spec() ArgSpec {
+	return expireSpec
+}
+
+func (c CmdPExpire) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execExpire(c.BaseCommand, session, args, time.Millisecond, false)
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPExpireAt struct {
+	BaseCommand
+}
+
+func NewCmdPExpireAt() CmdPExpireAt {
+	return CmdPExpireAt{BaseCommand: BaseCommand("PEXPIREAT")}
+}
+
+func (c CmdPExpireAt) Spec() ArgSpec {
+	return expireSpec
+}
+
+func (c CmdPExpireAt) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execExpire(c.BaseCommand, session, args, time.Millisecond, true)
+}
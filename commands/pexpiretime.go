@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPExpireTime struct {
+	BaseCommand
+}
+
+func NewCmdPExpireTime() CmdPExpireTime {
+	return CmdPExpireTime{BaseCommand: BaseCommand("PEXPIRETIME")}
+}
+
+func (c CmdPExpireTime) Spec() ArgSpec {
+	return ttlSpec
+}
+
+func (c CmdPExpireTime) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return keyTtl(session, args, time.Millisecond, true), nil
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPfAdd struct {
+	BaseCommand
+}
+
+func NewCmdPfAdd() CmdPfAdd {
+	return CmdPfAdd{BaseCommand: BaseCommand("PFADD")}
+}
+
+func (c CmdPfAdd) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+		Variadic: true,
+	}
+}
+
+// Exec adds the elements to the HyperLogLog at key, created if missing.
+// It replies 1 if the estimated cardinality may have changed, 0 otherwise.
+func (c CmdPfAdd) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	key := spec.Args[0].String()
+	updated := false
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		hll := rheltypes.NewHyperLogLog()
+
+		if found {
+			if hll, err = hyperLogLogValue(current.Value); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+		}
+
+		updated = !found
+
+		for _, element := range spec.Rest {
+			if hll.Add(element.String()) {
+				updated = true
+			}
+		}
+
+		if !updated {
+			return current, rheltypes.ComputeKeep
+		}
+
+		current.Value = rheltypes.NewBulkString(hll.String())
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	if !updated {
+		return rheltypes.Integer(0), nil
+	}
+
+	session.notify(notifyString, "pfadd", key)
+
+	return rheltypes.Integer(1), nil
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPfCount struct {
+	BaseCommand
+}
+
+func NewCmdPfCount() CmdPfCount {
+	return CmdPfCount{BaseCommand: BaseCommand("PFCOUNT")}
+}
+
+func (c CmdPfCount) Spec() ArgSpec {
+	return multiKeySpec
+}
+
+// Exec replies the estimated cardinality of the HyperLogLog at key, or of
+// the union of the ones at several keys, missing keys being empty.
+func (c CmdPfCount) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	if _, err = c.Spec().Parse(c.Name(), args); err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	if len(args) == 1 {
+		count, err := countHyperLogLog(session, args[0].String())
+		if err != nil {
+			return nil, c.ErrWrap(err)
+		}
+
+		return rheltypes.Integer(count), nil
+	}
+
+	hlls := make([]*rheltypes.HyperLogLog, 0, len(args))
+
+	for _, key := range args {
+		current, found := session.DB().Get(key.String())
+		if !found {
+			continue
+		}
+
+		hll, err := hyperLogLogValue(current)
+		if err != nil {
+			return nil, c.ErrWrap(err)
+		}
+
+		hlls = append(hlls, hll)
+	}
+
+	union := rheltypes.NewHyperLogLog()
+	union.Merge(hlls...)
+
+	return rheltypes.Integer(union.Count()), nil
+}
+
+// countHyperLogLog returns the estimated cardinality of the HyperLogLog at
+// key. It stores the cardinality in the cache of the HyperLogLog when it
+// has to compute it, the way Redis does.
+func countHyperLogLog(session *Session, key string) (count uint64, err error) {
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		if !found {
+			return current, rheltypes.ComputeKeep
+		}
+
+		var hll *rheltypes.HyperLogLog
+
+		if hll, err = hyperLogLogValue(current.Value); err != nil {
+			return current, rheltypes.ComputeKeep
+		}
+
+		stale := hll.Stale()
+		count = hll.Count()
+
+		if !stale {
+			return current, rheltypes.ComputeKeep
+		}
+
+		current.Value = rheltypes.NewBulkString(hll.String())
+
+		return current, rheltypes.ComputeStore
+	})
+
+	return count, err
+}
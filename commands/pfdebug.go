@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var (
+	errPfDebugNoKey = rheltypes.NewGenericError(
+		errors.New("The specified key does not exist"),
+	)
+	errPfDebugNotSparse = rheltypes.NewGenericError(
+		errors.New("HLL encoding is not sparse"),
+	)
+)
+
+type CmdPfDebug struct {
+	BaseCommand
+}
+
+func NewCmdPfDebug() CmdPfDebug {
+	return CmdPfDebug{BaseCommand: BaseCommand("PFDEBUG")}
+}
+
+func (c CmdPfDebug) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    3,
+		FirstKey: 2,
+		LastKey:  2,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgString, ArgKey},
+	}
+}
+
+// Exec inspects the HyperLogLog at key: GETREG replies its registers,
+// DECODE its sparse opcodes, ENCODING whether it is sparse or dense and
+// TODENSE makes its registers dense. GETREG makes them dense too.
+func (c CmdPfDebug) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	subcommand := strings.ToUpper(spec.Args[0].String())
+
+	var (
+		hll       *rheltypes.HyperLogLog
+		converted bool
+	)
+
+	session.DB().Compute(spec.Args[1].String(), func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		if !found {
+			err = errPfDebugNoKey
+
+			return current, rheltypes.ComputeKeep
+		}
+
+		if hll, err = hyperLogLogValue(current.Value); err != nil {
+			return current, rheltypes.ComputeKeep
+		}
+
+		if subcommand == "GETREG" || subcommand == "TODENSE" {
+			converted = hll.ToDense()
+		}
+
+		if !converted {
+			return current, rheltypes.ComputeKeep
+		}
+
+		current.Value = rheltypes.NewBulkString(hll.String())
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	switch subcommand {
+	case "GETREG":
+		registers := hll.Registers()
+		reply := make(rheltypes.Array, len(registers))
+
+		for i, register := range registers {
+			reply[i] = rheltypes.Integer(register)
+		}
+
+		return reply, nil
+	case "DECODE":
+		if !hll.Sparse() {
+			return nil, c.ErrWrap(errPfDebugNotSparse)
+		}
+
+		return rheltypes.SimpleString(hll.Decode()), nil
+	case "ENCODING":
+		if hll.Sparse() {
+			return rheltypes.SimpleString("sparse"), nil
+		}
+
+		return rheltypes.SimpleString("dense"), nil
+	case "TODENSE":
+		if converted {
+			return rheltypes.Integer(1), nil
+		}
+
+		return rheltypes.Integer(0), nil
+	default:
+		return nil, c.ErrWrap(rheltypes.NewGenericError(fmt.Errorf(
+			"Unknown PFDEBUG subcommand '%s'",
+			spec.Args[0].String(),
+		)))
+	}
+}
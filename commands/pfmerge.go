@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPfMerge struct {
+	BaseCommand
+}
+
+func NewCmdPfMerge() CmdPfMerge {
+	return CmdPfMerge{BaseCommand: BaseCommand("PFMERGE")}
+}
+
+func (c CmdPfMerge) Spec() ArgSpec {
+	return multiKeySpec
+}
+
+// Exec merges the HyperLogLogs at the source keys into the one at the
+// destination key, created if missing, which then estimates the
+// cardinality of their union.
+func (c CmdPfMerge) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	if _, err = c.Spec().Parse(c.Name(), args); err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	dest := args[0].String()
+	sources := make([]*rheltypes.HyperLogLog, 0, len(args)-1)
+
+	for _, key := range args[1:] {
+		current, found := session.DB().Get(key.String())
+		if !found {
+			continue
+		}
+
+		hll, err := hyperLogLogValue(current)
+		if err != nil {
+			return nil, c.ErrWrap(err)
+		}
+
+		sources = append(sources, hll)
+	}
+
+	session.DB().Compute(dest, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		hll := rheltypes.NewHyperLogLog()
+
+		if found {
+			if hll, err = hyperLogLogValue(current.Value); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+		}
+
+		hll.Merge(sources...)
+
+		current.Value = rheltypes.NewBulkString(hll.String())
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	session.notify(notifyString, "pfadd", dest)
+
+	return rheltypes.SimpleString("OK"), nil
+}
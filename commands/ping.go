@@ -12,12 +12,21 @@ func NewCmdPing() CmdPing {
 	return CmdPing{BaseCommand: BaseCommand("PING")}
 }
 
-func (c CmdPing) Exec(args rheltypes.Array) (rheltypes.RhelType, error) {
+func (c CmdPing) Spec() ArgSpec {
+	return ArgSpec{Arity: -1, MaxArity: 2, Args: []ArgKind{ArgString}}
+}
+
+func (c CmdPing) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (rheltypes.RhelType, error) {
+	if message := args.First(); message != nil {
+		return message, nil
+	}
+
 	return rheltypes.SimpleString("PONG"), nil
 }
 
 func (c CmdPing) Render() (cmd rheltypes.Array) {
 	return rheltypes.NewArrayFromStrings([]string{string(c.BaseCommand)})
 }
-
-func (c CmdPing) AllowedInSubscription() bool { return true }
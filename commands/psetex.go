@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPSetEx struct {
+	BaseCommand
+}
+
+func NewCmdPSetEx() CmdPSetEx {
+	return CmdPSetEx{BaseCommand: BaseCommand("PSETEX")}
+}
+
+func (c CmdPSetEx) Spec() ArgSpec {
+	return setExSpec
+}
+
+func (c CmdPSetEx) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execSetEx(c.BaseCommand, session, args, time.Millisecond)
+}
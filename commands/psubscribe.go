@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPSubscribe struct {
+	BaseCommand
+}
+
+func NewCmdPSubscribe() CmdPSubscribe {
+	return CmdPSubscribe{BaseCommand: BaseCommand("PSUBSCRIBE")}
+}
+
+func (c CmdPSubscribe) Spec() ArgSpec {
+	return ArgSpec{Arity: -2, Variadic: true}
+}
+
+func (c CmdPSubscribe) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	pattern := args.At(0).String()
+
+	sub := session.Instance().Broker().PSubscribe(
+		pattern,
+		func(channel string) bool { return globMatch(pattern, channel) },
+	)
+
+	arr := rheltypes.NewPushFromStrings([]string{"psubscribe", pattern})
+	value = append(arr, rheltypes.Integer(sub.Id))
+
+	return value, nil
+}
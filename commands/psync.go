@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"fmt"
 
-	"github.com/codecrafters-io/redis-starter-go/internal"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -17,10 +16,20 @@ func NewCmdPsync() CmdPsync {
 	return CmdPsync{BaseCommand: BaseCommand("PSYNC")}
 }
 
+func (c CmdPsync) Spec() ArgSpec {
+	return ArgSpec{Arity: -3, Variadic: true}
+}
+
 func (c CmdPsync) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
-	config := GetConfigMapInstance()
+	// The replica can't be told to resync from a file that can't be made.
+	if _, err = session.Instance().Snapshot(); err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	config := session.Instance().Config()
 
 	id, _ := config.Get("master_replid")
 	offset, _ := config.Get("master_repl_offset")
@@ -36,12 +45,25 @@ func (c CmdPsync) Render(id, offset string) (cmd rheltypes.Array) {
 	)
 }
 
-func (c CmdPsync) RenderFile() (content rheltypes.RhelType) {
+// RenderFile renders the snapshot sent to the replica for its full resync.
+func (c CmdPsync) RenderFile(
+	session *Session,
+) (content rheltypes.RhelType, err error) {
+	snapshot, err := session.Instance().Snapshot()
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
 	var buffer bytes.Buffer
 	buf := bufio.NewWriter(&buffer)
 
-	internal.NewRdbfFile().WriteContent(buf)
-	buf.Flush()
+	if err = snapshot.WriteContent(buf); err == nil {
+		err = buf.Flush()
+	}
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
 
-	return rheltypes.NewBulkStringFromBytes(buffer.Bytes())
+	return rheltypes.NewBulkStringFromBytes(buffer.Bytes()), nil
 }
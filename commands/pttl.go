@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPTtl struct {
+	BaseCommand
+}
+
+func NewCmdPTtl() CmdPTtl {
+	return CmdPTtl{BaseCommand: BaseCommand("PTTL")}
+}
+
+func (c CmdPTtl) Spec() ArgSpec {
+	return ttlSpec
+}
+
+func (c CmdPTtl) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return keyTtl(session, args, time.Millisecond, false), nil
+}
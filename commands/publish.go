@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"github.com/codecrafters-io/redis-starter-go/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -13,13 +12,18 @@ func NewCmdPublish() CmdPublish {
 	return CmdPublish{BaseCommand: BaseCommand("PUBLISH")}
 }
 
+func (c CmdPublish) Spec() ArgSpec {
+	return ArgSpec{Arity: 3, Args: []ArgKind{ArgString, ArgString}}
+}
+
 func (c CmdPublish) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(0).String()
 	msg := args.At(1)
 
-	sm := pubsub.GetStreamManager()
+	sm := session.Instance().Broker()
 
 	value = rheltypes.Integer(sm.NumSubscribers(key))
 
@@ -27,5 +31,3 @@ func (c CmdPublish) Exec(
 
 	return value, nil
 }
-
-func (c CmdPublish) AllowedInSubscription() bool { return true }
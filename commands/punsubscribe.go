@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdPUnsubscribe struct {
+	BaseCommand
+}
+
+func NewCmdPUnsubscribe() CmdPUnsubscribe {
+	return CmdPUnsubscribe{BaseCommand: BaseCommand("PUNSUBSCRIBE")}
+}
+
+func (c CmdPUnsubscribe) Spec() ArgSpec {
+	return ArgSpec{Arity: -2, Variadic: true}
+}
+
+func (c CmdPUnsubscribe) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	pattern := args.At(0).String()
+
+	if subscription := args.At(1); subscription != nil {
+		id, _ := subscription.Integer()
+		session.Instance().Broker().PUnsubscribe(pattern, id)
+	}
+
+	arr := rheltypes.NewPushFromStrings([]string{"punsubscribe", pattern})
+
+	return append(arr, rheltypes.Integer(0)), nil
+}
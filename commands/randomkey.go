@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdRandomKey struct {
+	BaseCommand
+}
+
+func NewCmdRandomKey() CmdRandomKey {
+	return CmdRandomKey{BaseCommand: BaseCommand("RANDOMKEY")}
+}
+
+func (c CmdRandomKey) Spec() ArgSpec {
+	return ArgSpec{Arity: 1}
+}
+
+func (c CmdRandomKey) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	key, found := session.DB().RandomKey()
+	if !found {
+		return rheltypes.NewNullBulkString(), nil
+	}
+
+	return rheltypes.NewBulkString(key), nil
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/redis-starter-go/internal"
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// rdbValue renders a value of the keyspace the way the RDB file holds it.
+// Streams can't be encoded yet, an error is returned rather than leaving
+// them out of the snapshot.
+func rdbValue(value rheltypes.RhelType) (internal.RdbKeyValue, error) {
+	switch v := value.(type) {
+	case *rheltypes.List:
+		list := make([]string, 0, v.Len())
+
+		for _, item := range v.Range(0, -1) {
+			list = append(list, item.String())
+		}
+
+		return internal.RdbKeyValue{
+			Type: internal.ListEncoding,
+			List: list,
+		}, nil
+	case rheltypes.SortedSet:
+		members := make([]internal.RdbSortedSetMember, 0, v.Size())
+
+		for name, score := range v.Members() {
+			members = append(members, internal.RdbSortedSetMember{
+				Member: name,
+				Score:  score,
+			})
+		}
+
+		return internal.RdbKeyValue{
+			Type:      internal.SortedSet2Encoding,
+			SortedSet: members,
+		}, nil
+	default:
+		if !isStringValue(value) {
+			return internal.RdbKeyValue{}, fmt.Errorf(
+				"%s values can't be saved to RDB yet",
+				value.TypeName(),
+			)
+		}
+
+		return internal.RdbKeyValue{Value: value.String()}, nil
+	}
+}
+
+// keyspaceValue turns a value read from an RDB file back into a value of the
+// keyspace.
+func keyspaceValue(value internal.RdbKeyValue) rheltypes.RhelType {
+	switch value.Type {
+	case internal.ListEncoding:
+		list := rheltypes.NewList()
+
+		for _, item := range value.List {
+			list.PushBack(rheltypes.NewBulkString(item))
+		}
+
+		return list
+	case internal.SortedSet2Encoding:
+		set := rheltypes.NewSortedSet()
+
+		for _, member := range value.SortedSet {
+			set.Add(member.Member, member.Score)
+		}
+
+		return *set
+	default:
+		return rheltypes.NewBulkString(value.Value)
+	}
+}
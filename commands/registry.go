@@ -0,0 +1,1352 @@
+package commands
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// CommandFlag describes how a command behaves, the exported ones are
+// reported by COMMAND INFO under their Redis names.
+type CommandFlag uint
+
+const (
+	FlagWrite CommandFlag = 1 << iota
+	FlagReadonly
+	FlagDenyOOM
+	FlagAdmin
+	FlagPubSub
+	FlagNoScript
+	FlagBlocking
+	FlagLoading
+	FlagStale
+	FlagFast
+	// flagSubscribed commands are accepted while a RESP2 connection is
+	// subscribed to a channel.
+	flagSubscribed
+	// flagMasterReply commands answer the master on the replication link,
+	// the replies of every other one are dropped.
+	flagMasterReply
+	// flagExclusive commands reach beyond the keys declared by their spec,
+	// e.g. other databases, and run while no other command does.
+	flagExclusive
+)
+
+var commandFlagNames = []struct {
+	flag CommandFlag
+	name string
+}{
+	{FlagWrite, "write"},
+	{FlagReadonly, "readonly"},
+	{FlagDenyOOM, "denyoom"},
+	{FlagAdmin, "admin"},
+	{FlagPubSub, "pubsub"},
+	{FlagNoScript, "noscript"},
+	{FlagBlocking, "blocking"},
+	{FlagLoading, "loading"},
+	{FlagStale, "stale"},
+	{FlagFast, "fast"},
+}
+
+func (f CommandFlag) Has(flag CommandFlag) bool {
+	return f&flag != 0
+}
+
+func (f CommandFlag) Names() (names []string) {
+	for _, known := range commandFlagNames {
+		if f.Has(known.flag) {
+			names = append(names, known.name)
+		}
+	}
+
+	return names
+}
+
+// CommandDocs is the documentation returned by COMMAND DOCS.
+type CommandDocs struct {
+	Summary    string
+	Since      string
+	Group      string
+	Complexity string
+}
+
+// CommandEntry is a command known to the server along with its metadata.
+// Arity and key positions aren't repeated here, they come from the ArgSpec
+// of the command.
+type CommandEntry struct {
+	Name  string
+	New   func() RhelCommand
+	Flags CommandFlag
+	// Categories are the ACL categories of the command besides the ones
+	// implied by its flags, e.g. "string" for @string.
+	Categories []string
+	Docs       CommandDocs
+}
+
+func (e *CommandEntry) Has(flag CommandFlag) bool {
+	return e != nil && e.Flags.Has(flag)
+}
+
+func (e *CommandEntry) Spec() ArgSpec {
+	return e.New().Spec()
+}
+
+// Propagated reports whether the command is sent to replicas once it ran.
+// Blocking commands would stall the replication link, they aren't.
+func (e *CommandEntry) Propagated() bool {
+	return e.Has(FlagWrite) && !e.Has(FlagBlocking)
+}
+
+// ACLCategories returns the categories of the command, prefixed with @ as
+// in COMMAND INFO.
+func (e *CommandEntry) ACLCategories() (categories []string) {
+	switch {
+	case e.Has(FlagWrite):
+		categories = append(categories, "@write")
+	case e.Has(FlagReadonly):
+		categories = append(categories, "@read")
+	}
+
+	for _, category := range e.Categories {
+		categories = append(categories, "@"+category)
+	}
+
+	if e.Has(FlagFast) {
+		categories = append(categories, "@fast")
+	} else {
+		categories = append(categories, "@slow")
+	}
+
+	if e.Has(FlagBlocking) {
+		categories = append(categories, "@blocking")
+	}
+
+	if e.Has(FlagPubSub) {
+		categories = append(categories, "@pubsub")
+	}
+
+	if e.Has(FlagAdmin) {
+		categories = append(categories, "@admin", "@dangerous")
+	}
+
+	return categories
+}
+
+// Registry holds every command the server knows about, by upper case name.
+type Registry struct {
+	entries map[string]*CommandEntry
+}
+
+func NewRegistry(entries ...CommandEntry) *Registry {
+	r := &Registry{entries: make(map[string]*CommandEntry, len(entries))}
+
+	for _, entry := range entries {
+		r.Register(entry)
+	}
+
+	return r
+}
+
+func (r *Registry) Register(entry CommandEntry) {
+	entry.Name = strings.ToUpper(entry.Name)
+	r.entries[entry.Name] = &entry
+}
+
+func (r *Registry) Lookup(name string) (entry *CommandEntry, found bool) {
+	entry, found = r.entries[strings.ToUpper(name)]
+
+	return
+}
+
+func (r *Registry) Count() int {
+	return len(r.entries)
+}
+
+// All yields the commands sorted by name.
+func (r *Registry) All() iter.Seq[*CommandEntry] {
+	return func(yield func(*CommandEntry) bool) {
+		for _, name := range slices.Sorted(maps.Keys(r.entries)) {
+			if !yield(r.entries[name]) {
+				return
+			}
+		}
+	}
+}
+
+var commandRegistry = NewRegistry(
+	CommandEntry{
+		Name:       "APPEND",
+		New:        func() RhelCommand { return NewCmdAppend() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Appends a string to the value of a key. Creates the " +
+				"key if it doesn't exist.",
+			Since:      "2.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "BITCOUNT",
+		New:        func() RhelCommand { return NewCmdBitCount() },
+		Flags:      FlagReadonly,
+		Categories: []string{"bitmap"},
+		Docs: CommandDocs{
+			Summary: "Counts the number of set bits (population counting) " +
+				"in a string.",
+			Since:      "2.6.0",
+			Group:      "bitmap",
+			Complexity: "O(N)",
+		},
+	},
+	CommandEntry{
+		Name:       "BITFIELD",
+		New:        func() RhelCommand { return NewCmdBitfield() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"bitmap"},
+		Docs: CommandDocs{
+			Summary: "Performs arbitrary bitfield integer operations on " +
+				"strings.",
+			Since:      "3.2.0",
+			Group:      "bitmap",
+			Complexity: "O(1) for each subcommand specified",
+		},
+	},
+	CommandEntry{
+		Name:       "BITFIELD_RO",
+		New:        func() RhelCommand { return NewCmdBitfieldRo() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"bitmap"},
+		Docs: CommandDocs{
+			Summary: "Performs arbitrary read-only bitfield integer " +
+				"operations on strings.",
+			Since:      "6.0.0",
+			Group:      "bitmap",
+			Complexity: "O(1) for each subcommand specified",
+		},
+	},
+	CommandEntry{
+		Name:       "BITOP",
+		New:        func() RhelCommand { return NewCmdBitOp() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"bitmap"},
+		Docs: CommandDocs{
+			Summary: "Performs bitwise operations on multiple strings, and " +
+				"stores the result.",
+			Since:      "2.6.0",
+			Group:      "bitmap",
+			Complexity: "O(N)",
+		},
+	},
+	CommandEntry{
+		Name:       "BITPOS",
+		New:        func() RhelCommand { return NewCmdBitPos() },
+		Flags:      FlagReadonly,
+		Categories: []string{"bitmap"},
+		Docs: CommandDocs{
+			Summary:    "Finds the first set (1) or clear (0) bit in a string.",
+			Since:      "2.8.7",
+			Group:      "bitmap",
+			Complexity: "O(N)",
+		},
+	},
+	CommandEntry{
+		Name:       "BLPOP",
+		New:        func() RhelCommand { return NewCmdBLPop() },
+		Flags:      FlagWrite | FlagBlocking,
+		Categories: []string{"list"},
+		Docs: CommandDocs{
+			Summary: "Removes and returns the first element in a list. " +
+				"Blocks until an element is available otherwise.",
+			Since:      "2.0.0",
+			Group:      "list",
+			Complexity: "O(N) where N is the number of provided keys.",
+		},
+	},
+	CommandEntry{
+		Name:       "COMMAND",
+		New:        func() RhelCommand { return NewCmdCommand() },
+		Flags:      FlagLoading | FlagStale,
+		Categories: []string{"connection"},
+		Docs: CommandDocs{
+			Summary:    "Returns detailed information about all commands.",
+			Since:      "2.8.13",
+			Group:      "server",
+			Complexity: "O(N) where N is the total number of commands.",
+		},
+	},
+	CommandEntry{
+		Name:  "CONFIG",
+		New:   func() RhelCommand { return NewCmdConfig() },
+		Flags: FlagAdmin | FlagNoScript | FlagLoading | FlagStale,
+		Docs: CommandDocs{
+			Summary:    "A container for server configuration commands.",
+			Since:      "2.0.0",
+			Group:      "server",
+			Complexity: "O(N) when N is the number of parameters returned.",
+		},
+	},
+	CommandEntry{
+		Name:       "COPY",
+		New:        func() RhelCommand { return NewCmdCopy() },
+		Flags:      FlagWrite | FlagDenyOOM | flagExclusive,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Copies the value of a key to a new key.",
+			Since:   "6.2.0",
+			Group:   "generic",
+			Complexity: "O(N) worst case for collections, where N is the " +
+				"number of nested items. O(1) for string values.",
+		},
+	},
+	CommandEntry{
+		Name:       "DBSIZE",
+		New:        func() RhelCommand { return NewCmdDbSize() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Returns the number of keys in the database.",
+			Since:      "1.0.0",
+			Group:      "server",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "DECR",
+		New:        func() RhelCommand { return NewCmdDecr() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Decrements the integer value of a key by one. Uses 0 " +
+				"as initial value if the key doesn't exist.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "DECRBY",
+		New:        func() RhelCommand { return NewCmdDecrBy() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Decrements a number from the integer value of a key. " +
+				"Uses 0 as initial value if the key doesn't exist.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "DEL",
+		New:        func() RhelCommand { return NewCmdDel() },
+		Flags:      FlagWrite,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Deletes one or more keys.",
+			Since:   "1.0.0",
+			Group:   "generic",
+			Complexity: "O(N) where N is the number of keys that will be " +
+				"removed.",
+		},
+	},
+	CommandEntry{
+		Name:       "DISCARD",
+		New:        func() RhelCommand { return NewCmdDiscard() },
+		Flags:      FlagNoScript | FlagLoading | FlagStale | FlagFast,
+		Categories: []string{"transaction"},
+		Docs: CommandDocs{
+			Summary:    "Discards a transaction.",
+			Since:      "2.0.0",
+			Group:      "transactions",
+			Complexity: "O(N), when N is the number of queued commands.",
+		},
+	},
+	CommandEntry{
+		Name:       "ECHO",
+		New:        func() RhelCommand { return NewCmdEcho() },
+		Flags:      FlagFast,
+		Categories: []string{"connection"},
+		Docs: CommandDocs{
+			Summary:    "Returns the given string.",
+			Since:      "1.0.0",
+			Group:      "connection",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "EXEC",
+		New:        func() RhelCommand { return NewCmdExec() },
+		Flags:      FlagNoScript | FlagLoading | FlagStale,
+		Categories: []string{"transaction"},
+		Docs: CommandDocs{
+			Summary:    "Executes all commands in a transaction.",
+			Since:      "1.2.0",
+			Group:      "transactions",
+			Complexity: "Depends on commands in the transaction",
+		},
+	},
+	CommandEntry{
+		Name:       "EXISTS",
+		New:        func() RhelCommand { return NewCmdExists() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Determines whether one or more keys exist.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(N) where N is the number of keys to check.",
+		},
+	},
+	CommandEntry{
+		Name:       "EXPIRE",
+		New:        func() RhelCommand { return NewCmdExpire() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Sets the expiration time of a key in seconds.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "EXPIREAT",
+		New:        func() RhelCommand { return NewCmdExpireAt() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Sets the expiration time of a key to a Unix " +
+				"timestamp.",
+			Since:      "1.2.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "EXPIRETIME",
+		New:        func() RhelCommand { return NewCmdExpireTime() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Returns the expiration time of a key as a Unix " +
+				"timestamp.",
+			Since:      "7.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "FLUSHDB",
+		New:        func() RhelCommand { return NewCmdFlushDb() },
+		Flags:      FlagWrite | flagExclusive,
+		Categories: []string{"keyspace", "dangerous"},
+		Docs: CommandDocs{
+			Summary: "Removes all keys from the current database.",
+			Since:   "1.0.0",
+			Group:   "server",
+			Complexity: "O(N) where N is the number of keys in the " +
+				"selected database",
+		},
+	},
+	CommandEntry{
+		Name:       "GET",
+		New:        func() RhelCommand { return NewCmdGet() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary:    "Returns the string value of a key.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "GETBIT",
+		New:        func() RhelCommand { return NewCmdGetBit() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"bitmap"},
+		Docs: CommandDocs{
+			Summary:    "Returns a bit value by offset.",
+			Since:      "2.2.0",
+			Group:      "bitmap",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "GETDEL",
+		New:        func() RhelCommand { return NewCmdGetDel() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Returns the string value of a key after deleting the " +
+				"key.",
+			Since:      "6.2.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "GETEX",
+		New:        func() RhelCommand { return NewCmdGetEx() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Returns the string value of a key after setting its " +
+				"expiration time.",
+			Since:      "6.2.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "GETRANGE",
+		New:        func() RhelCommand { return NewCmdGetRange() },
+		Flags:      FlagReadonly,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary:    "Returns a substring of the string stored at a key.",
+			Since:      "2.4.0",
+			Group:      "string",
+			Complexity: "O(N) where N is the length of the returned string.",
+		},
+	},
+	CommandEntry{
+		Name:       "GETSET",
+		New:        func() RhelCommand { return NewCmdGetSet() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Returns the previous string value of a key after " +
+				"setting it to a new value.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "HELLO",
+		New:        func() RhelCommand { return NewCmdHello() },
+		Flags:      FlagNoScript | FlagLoading | FlagStale | FlagFast,
+		Categories: []string{"connection"},
+		Docs: CommandDocs{
+			Summary:    "Handshakes with the Redis server.",
+			Since:      "6.0.0",
+			Group:      "connection",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "INCR",
+		New:        func() RhelCommand { return NewCmdIncr() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Increments the integer value of a key by one. " +
+				"Uses 0 as initial value if the key doesn't exist.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "INCRBY",
+		New:        func() RhelCommand { return NewCmdIncrBy() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Increments the integer value of a key by a number. " +
+				"Uses 0 as initial value if the key doesn't exist.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "INCRBYFLOAT",
+		New:        func() RhelCommand { return NewCmdIncrByFloat() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Increment the floating point value of a key by a " +
+				"number. Uses 0 as initial value if the key doesn't exist.",
+			Since:      "2.6.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "INFO",
+		New:        func() RhelCommand { return NewCmdInfo() },
+		Flags:      FlagLoading | FlagStale,
+		Categories: []string{"dangerous"},
+		Docs: CommandDocs{
+			Summary:    "Returns information and statistics about the server.",
+			Since:      "1.0.0",
+			Group:      "server",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "KEYS",
+		New:        func() RhelCommand { return NewCmdKeys() },
+		Flags:      FlagReadonly,
+		Categories: []string{"keyspace", "dangerous"},
+		Docs: CommandDocs{
+			Summary:    "Returns all key names that match a pattern.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(N) with N being the number of keys in the database.",
+		},
+	},
+	CommandEntry{
+		Name:       "LCS",
+		New:        func() RhelCommand { return NewCmdLcs() },
+		Flags:      FlagReadonly,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Finds the longest common substring.",
+			Since:   "7.0.0",
+			Group:   "string",
+			Complexity: "O(N*M) where N and M are the lengths of s1 and s2, " +
+				"respectively",
+		},
+	},
+	CommandEntry{
+		Name:       "LLEN",
+		New:        func() RhelCommand { return NewCmdLLen() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"list"},
+		Docs: CommandDocs{
+			Summary:    "Returns the length of a list.",
+			Since:      "1.0.0",
+			Group:      "list",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "LPOP",
+		New:        func() RhelCommand { return NewCmdLPop() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"list"},
+		Docs: CommandDocs{
+			Summary: "Returns the first elements in a list after removing " +
+				"it. Deletes the list if the last element was popped.",
+			Since:      "1.0.0",
+			Group:      "list",
+			Complexity: "O(N) where N is the number of elements returned",
+		},
+	},
+	CommandEntry{
+		Name:       "LPUSH",
+		New:        func() RhelCommand { return NewCmdLPush() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"list"},
+		Docs: CommandDocs{
+			Summary: "Prepends one or more elements to a list. " +
+				"Creates the key if it doesn't exist.",
+			Since: "1.0.0",
+			Group: "list",
+			Complexity: "O(1) for each element added, so O(N) to add N " +
+				"elements.",
+		},
+	},
+	CommandEntry{
+		Name:       "LRANGE",
+		New:        func() RhelCommand { return NewCmdLRange() },
+		Flags:      FlagReadonly,
+		Categories: []string{"list"},
+		Docs: CommandDocs{
+			Summary:    "Returns a range of elements from a list.",
+			Since:      "1.0.0",
+			Group:      "list",
+			Complexity: "O(S+N) where S is the start offset and N the range.",
+		},
+	},
+	CommandEntry{
+		Name:       "MGET",
+		New:        func() RhelCommand { return NewCmdMGet() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Atomically returns the string values of one or more " +
+				"keys.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(N) where N is the number of keys to retrieve.",
+		},
+	},
+	CommandEntry{
+		Name:       "MOVE",
+		New:        func() RhelCommand { return NewCmdMove() },
+		Flags:      FlagWrite | FlagFast | flagExclusive,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Moves a key to another database.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "MSET",
+		New:        func() RhelCommand { return NewCmdMSet() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Atomically creates or modifies the string values of " +
+				"one or more keys.",
+			Since:      "1.0.1",
+			Group:      "string",
+			Complexity: "O(N) where N is the number of keys to set.",
+		},
+	},
+	CommandEntry{
+		Name:       "MSETNX",
+		New:        func() RhelCommand { return NewCmdMSetNx() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Atomically modifies the string values of one or more " +
+				"keys only when all keys don't exist.",
+			Since:      "1.0.1",
+			Group:      "string",
+			Complexity: "O(N) where N is the number of keys to set.",
+		},
+	},
+	CommandEntry{
+		Name:       "MULTI",
+		New:        func() RhelCommand { return NewCmdMulti() },
+		Flags:      FlagNoScript | FlagLoading | FlagStale | FlagFast,
+		Categories: []string{"transaction"},
+		Docs: CommandDocs{
+			Summary:    "Starts a transaction.",
+			Since:      "1.2.0",
+			Group:      "transactions",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "OBJECT",
+		New:        func() RhelCommand { return NewCmdObject() },
+		Flags:      FlagReadonly,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "A container for object introspection commands.",
+			Since:      "2.2.3",
+			Group:      "generic",
+			Complexity: "Depends on subcommand.",
+		},
+	},
+	CommandEntry{
+		Name:       "PERSIST",
+		New:        func() RhelCommand { return NewCmdPersist() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Removes the expiration time of a key.",
+			Since:      "2.2.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "PEXPIRE",
+		New:        func() RhelCommand { return NewCmdPExpire() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Sets the expiration time of a key in milliseconds.",
+			Since:      "2.6.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "PEXPIREAT",
+		New:        func() RhelCommand { return NewCmdPExpireAt() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Sets the expiration time of a key to a Unix " +
+				"milliseconds timestamp.",
+			Since:      "2.6.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "PEXPIRETIME",
+		New:        func() RhelCommand { return NewCmdPExpireTime() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Returns the expiration time of a key as a Unix " +
+				"milliseconds timestamp.",
+			Since:      "7.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "PFADD",
+		New:        func() RhelCommand { return NewCmdPfAdd() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"hyperloglog"},
+		Docs: CommandDocs{
+			Summary: "Adds elements to a HyperLogLog key. Creates the key " +
+				"if it does not exist.",
+			Since:      "2.8.9",
+			Group:      "hyperloglog",
+			Complexity: "O(1) to add every element.",
+		},
+	},
+	CommandEntry{
+		Name:       "PFCOUNT",
+		New:        func() RhelCommand { return NewCmdPfCount() },
+		Flags:      FlagReadonly,
+		Categories: []string{"hyperloglog"},
+		Docs: CommandDocs{
+			Summary: "Returns the approximated cardinality of the set(s) " +
+				"observed by the HyperLogLog key(s).",
+			Since: "2.8.9",
+			Group: "hyperloglog",
+			Complexity: "O(1) with a very small average constant time when " +
+				"called with a single key. O(N) with N being the number " +
+				"of keys, and much bigger constant times, when called " +
+				"with multiple keys.",
+		},
+	},
+	CommandEntry{
+		Name:       "PFDEBUG",
+		New:        func() RhelCommand { return NewCmdPfDebug() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagAdmin,
+		Categories: []string{"hyperloglog"},
+		Docs: CommandDocs{
+			Summary:    "Internal commands for debugging HyperLogLog values.",
+			Since:      "2.8.9",
+			Group:      "hyperloglog",
+			Complexity: "N/A",
+		},
+	},
+	CommandEntry{
+		Name:       "PFMERGE",
+		New:        func() RhelCommand { return NewCmdPfMerge() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"hyperloglog"},
+		Docs: CommandDocs{
+			Summary: "Merges one or more HyperLogLog values into a single " +
+				"key.",
+			Since: "2.8.9",
+			Group: "hyperloglog",
+			Complexity: "O(N) to merge N HyperLogLogs, but with high " +
+				"constant times.",
+		},
+	},
+	CommandEntry{
+		Name:       "PING",
+		New:        func() RhelCommand { return NewCmdPing() },
+		Flags:      FlagFast | flagSubscribed,
+		Categories: []string{"connection"},
+		Docs: CommandDocs{
+			Summary:    "Returns the server's liveliness response.",
+			Since:      "1.0.0",
+			Group:      "connection",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "PSETEX",
+		New:        func() RhelCommand { return NewCmdPSetEx() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Sets both string value and expiration time in " +
+				"milliseconds of a key.",
+			Since:      "2.6.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name: "PSUBSCRIBE",
+		New:  func() RhelCommand { return NewCmdPSubscribe() },
+		Flags: FlagPubSub | FlagNoScript | FlagLoading | FlagStale |
+			flagSubscribed,
+		Docs: CommandDocs{
+			Summary: "Listens for messages published to channels that match " +
+				"one or more patterns.",
+			Since: "2.0.0",
+			Group: "pubsub",
+			Complexity: "O(N) where N is the number of patterns to " +
+				"subscribe to.",
+		},
+	},
+	CommandEntry{
+		Name:  "PSYNC",
+		New:   func() RhelCommand { return NewCmdPsync() },
+		Flags: FlagAdmin | FlagNoScript,
+		Docs: CommandDocs{
+			Summary: "An internal command used in replication.",
+			Since:   "2.8.0",
+			Group:   "server",
+		},
+	},
+	CommandEntry{
+		Name:       "PTTL",
+		New:        func() RhelCommand { return NewCmdPTtl() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Returns the expiration time in milliseconds of a key.",
+			Since:      "2.6.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name: "PUBLISH",
+		New:  func() RhelCommand { return NewCmdPublish() },
+		Flags: FlagPubSub | FlagLoading | FlagStale | FlagFast |
+			flagSubscribed,
+		Docs: CommandDocs{
+			Summary:    "Posts a message to a channel.",
+			Since:      "2.0.0",
+			Group:      "pubsub",
+			Complexity: "O(N+M) where N is the number of subscribers.",
+		},
+	},
+	CommandEntry{
+		Name: "PUNSUBSCRIBE",
+		New:  func() RhelCommand { return NewCmdPUnsubscribe() },
+		Flags: FlagPubSub | FlagNoScript | FlagLoading | FlagStale |
+			flagSubscribed,
+		Docs: CommandDocs{
+			Summary: "Stops listening to messages published to channels " +
+				"that match one or more patterns.",
+			Since: "2.0.0",
+			Group: "pubsub",
+			Complexity: "O(N) where N is the number of patterns to " +
+				"unsubscribe.",
+		},
+	},
+	CommandEntry{
+		Name:       "RANDOMKEY",
+		New:        func() RhelCommand { return NewCmdRandomKey() },
+		Flags:      FlagReadonly,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Returns a random key name from the database.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "RENAME",
+		New:        func() RhelCommand { return NewCmdRename() },
+		Flags:      FlagWrite,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Renames a key and overwrites the destination.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "RENAMENX",
+		New:        func() RhelCommand { return NewCmdRenameNx() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Renames a key only when the target key name doesn't " +
+				"exist.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name: "REPLCONF",
+		New:  func() RhelCommand { return NewCmdReplconf() },
+		Flags: FlagAdmin | FlagNoScript | FlagLoading | FlagStale |
+			flagMasterReply,
+		Docs: CommandDocs{
+			Summary:    "An internal command for configuring the replication.",
+			Since:      "3.0.0",
+			Group:      "server",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "RPUSH",
+		New:        func() RhelCommand { return NewCmdRPush() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"list"},
+		Docs: CommandDocs{
+			Summary: "Appends one or more elements to a list. " +
+				"Creates the key if it doesn't exist.",
+			Since: "1.0.0",
+			Group: "list",
+			Complexity: "O(1) for each element added, so O(N) to add N " +
+				"elements.",
+		},
+	},
+	CommandEntry{
+		Name:  "SAVE",
+		New:   func() RhelCommand { return NewCmdSave() },
+		Flags: FlagAdmin | FlagNoScript | flagExclusive,
+		Docs: CommandDocs{
+			Summary: "Synchronously saves the database(s) to disk.",
+			Since:   "1.0.0",
+			Group:   "server",
+			Complexity: "O(N) where N is the total number of keys in all " +
+				"databases",
+		},
+	},
+	CommandEntry{
+		Name:       "SCAN",
+		New:        func() RhelCommand { return NewCmdScan() },
+		Flags:      FlagReadonly,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Iterates over the key names in the database.",
+			Since:   "2.8.0",
+			Group:   "generic",
+			Complexity: "O(1) for every call. O(N) for a complete " +
+				"iteration, including enough command calls for the " +
+				"cursor to return back to 0. N is the number of " +
+				"elements inside the collection.",
+		},
+	},
+	CommandEntry{
+		Name:       "SELECT",
+		New:        func() RhelCommand { return NewCmdSelect() },
+		Flags:      FlagLoading | FlagStale | FlagFast,
+		Categories: []string{"connection"},
+		Docs: CommandDocs{
+			Summary:    "Changes the selected database.",
+			Since:      "1.0.0",
+			Group:      "connection",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "SET",
+		New:        func() RhelCommand { return NewCmdSet() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Sets the string value of a key, ignoring its type. " +
+				"The key is created if it doesn't exist.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "SETBIT",
+		New:        func() RhelCommand { return NewCmdSetBit() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"bitmap"},
+		Docs: CommandDocs{
+			Summary: "Sets or clears the bit at offset of the string value. " +
+				"Creates the key if it does not exist.",
+			Since:      "2.2.0",
+			Group:      "bitmap",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "SETEX",
+		New:        func() RhelCommand { return NewCmdSetEx() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary:    "Sets the string value and expiration time of a key.",
+			Since:      "2.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "SETNX",
+		New:        func() RhelCommand { return NewCmdSetNx() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Set the string value of a key only when the key " +
+				"doesn't exist.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "SETRANGE",
+		New:        func() RhelCommand { return NewCmdSetRange() },
+		Flags:      FlagWrite | FlagDenyOOM,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary: "Overwrites a part of a string value with another by " +
+				"an offset. Creates the key if it doesn't exist.",
+			Since: "2.2.0",
+			Group: "string",
+			Complexity: "O(1), not counting the time taken to copy the new " +
+				"string in place.",
+		},
+	},
+	CommandEntry{
+		Name:       "STRLEN",
+		New:        func() RhelCommand { return NewCmdStrLen() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary:    "Returns the length of a string value.",
+			Since:      "2.2.0",
+			Group:      "string",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name: "SUBSCRIBE",
+		New:  func() RhelCommand { return NewCmdSubscribe() },
+		Flags: FlagPubSub | FlagNoScript | FlagLoading | FlagStale |
+			flagSubscribed,
+		Docs: CommandDocs{
+			Summary:    "Listens for messages published to channels.",
+			Since:      "2.0.0",
+			Group:      "pubsub",
+			Complexity: "O(N) where N is the number of channels.",
+		},
+	},
+	CommandEntry{
+		Name:       "SUBSTR",
+		New:        func() RhelCommand { return NewCmdSubStr() },
+		Flags:      FlagReadonly,
+		Categories: []string{"string"},
+		Docs: CommandDocs{
+			Summary:    "Returns a substring from a string value.",
+			Since:      "1.0.0",
+			Group:      "string",
+			Complexity: "O(N) where N is the length of the returned string.",
+		},
+	},
+	CommandEntry{
+		Name:       "SWAPDB",
+		New:        func() RhelCommand { return NewCmdSwapDb() },
+		Flags:      FlagWrite | FlagFast | flagExclusive,
+		Categories: []string{"keyspace", "dangerous"},
+		Docs: CommandDocs{
+			Summary: "Swaps two Redis databases.",
+			Since:   "4.0.0",
+			Group:   "server",
+			Complexity: "O(N) where N is the count of clients watching or " +
+				"blocking on keys from both databases.",
+		},
+	},
+	CommandEntry{
+		Name:       "TOUCH",
+		New:        func() RhelCommand { return NewCmdTouch() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary: "Returns the number of existing keys out of those " +
+				"specified after updating the time they were last accessed.",
+			Since: "3.2.1",
+			Group: "generic",
+			Complexity: "O(N) where N is the number of keys that will be " +
+				"touched.",
+		},
+	},
+	CommandEntry{
+		Name:       "TTL",
+		New:        func() RhelCommand { return NewCmdTtl() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Returns the expiration time in seconds of a key.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "TYPE",
+		New:        func() RhelCommand { return NewCmdType() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Determines the type of value stored at a key.",
+			Since:      "1.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "UNLINK",
+		New:        func() RhelCommand { return NewCmdUnlink() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"keyspace"},
+		Docs: CommandDocs{
+			Summary:    "Asynchronously deletes one or more keys.",
+			Since:      "4.0.0",
+			Group:      "generic",
+			Complexity: "O(1) for each key removed regardless of its size.",
+		},
+	},
+	CommandEntry{
+		Name: "UNSUBSCRIBE",
+		New:  func() RhelCommand { return NewCmdUnsubscribe() },
+		Flags: FlagPubSub | FlagNoScript | FlagLoading | FlagStale |
+			flagSubscribed,
+		Docs: CommandDocs{
+			Summary:    "Stops listening to messages posted to channels.",
+			Since:      "2.0.0",
+			Group:      "pubsub",
+			Complexity: "O(N) where N is the number of channels.",
+		},
+	},
+	CommandEntry{
+		Name:       "WAIT",
+		New:        func() RhelCommand { return NewCmdWait() },
+		Flags:      FlagNoScript,
+		Categories: []string{"connection"},
+		Docs: CommandDocs{
+			Summary: "Blocks until the asynchronous replication of all " +
+				"preceding write commands sent by the connection is " +
+				"completed.",
+			Since:      "3.0.0",
+			Group:      "generic",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "XADD",
+		New:        func() RhelCommand { return NewCmdXAdd() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"stream"},
+		Docs: CommandDocs{
+			Summary: "Appends a new message to a stream. " +
+				"Creates the key if it doesn't exist.",
+			Since:      "5.0.0",
+			Group:      "stream",
+			Complexity: "O(1) when adding a new entry.",
+		},
+	},
+	CommandEntry{
+		Name:       "XRANGE",
+		New:        func() RhelCommand { return NewCmdXRange() },
+		Flags:      FlagReadonly,
+		Categories: []string{"stream"},
+		Docs: CommandDocs{
+			Summary: "Returns the messages from a stream within a range " +
+				"of IDs.",
+			Since:      "5.0.0",
+			Group:      "stream",
+			Complexity: "O(N) with N being the number of elements returned.",
+		},
+	},
+	CommandEntry{
+		Name:       "XREAD",
+		New:        func() RhelCommand { return NewCmdXRead() },
+		Flags:      FlagReadonly | FlagBlocking,
+		Categories: []string{"stream"},
+		Docs: CommandDocs{
+			Summary: "Returns messages from multiple streams with IDs " +
+				"greater than the ones requested. Blocks until a message " +
+				"is available otherwise.",
+			Since: "5.0.0",
+			Group: "stream",
+		},
+	},
+	CommandEntry{
+		Name:       "ZADD",
+		New:        func() RhelCommand { return NewCmdZAdd() },
+		Flags:      FlagWrite | FlagDenyOOM | FlagFast,
+		Categories: []string{"sortedset"},
+		Docs: CommandDocs{
+			Summary: "Adds one or more members to a sorted set, or " +
+				"updates their scores. Creates the key if it doesn't exist.",
+			Since: "1.2.0",
+			Group: "sorted-set",
+			Complexity: "O(log(N)) for each item added, where N is the " +
+				"number of elements in the sorted set.",
+		},
+	},
+	CommandEntry{
+		Name:       "ZCARD",
+		New:        func() RhelCommand { return NewCmdZCard() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"sortedset"},
+		Docs: CommandDocs{
+			Summary:    "Returns the number of members in a sorted set.",
+			Since:      "1.2.0",
+			Group:      "sorted-set",
+			Complexity: "O(1)",
+		},
+	},
+	CommandEntry{
+		Name:       "ZRANGE",
+		New:        func() RhelCommand { return NewCmdZRange() },
+		Flags:      FlagReadonly,
+		Categories: []string{"sortedset"},
+		Docs: CommandDocs{
+			Summary: "Returns members in a sorted set within a range of " +
+				"indexes.",
+			Since:      "1.2.0",
+			Group:      "sorted-set",
+			Complexity: "O(log(N)+M) with M the number of elements returned.",
+		},
+	},
+	CommandEntry{
+		Name:       "ZRANK",
+		New:        func() RhelCommand { return NewCmdZRank() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"sortedset"},
+		Docs: CommandDocs{
+			Summary: "Returns the index of a member in a sorted set ordered " +
+				"by ascending scores.",
+			Since:      "2.0.0",
+			Group:      "sorted-set",
+			Complexity: "O(log(N))",
+		},
+	},
+	CommandEntry{
+		Name:       "ZREM",
+		New:        func() RhelCommand { return NewCmdZRem() },
+		Flags:      FlagWrite | FlagFast,
+		Categories: []string{"sortedset"},
+		Docs: CommandDocs{
+			Summary: "Removes one or more members from a sorted set. " +
+				"Deletes the sorted set if all members were removed.",
+			Since: "1.2.0",
+			Group: "sorted-set",
+			Complexity: "O(M*log(N)) with N being the number of elements " +
+				"in the sorted set and M the number of elements removed.",
+		},
+	},
+	CommandEntry{
+		Name:       "ZSCORE",
+		New:        func() RhelCommand { return NewCmdZScore() },
+		Flags:      FlagReadonly | FlagFast,
+		Categories: []string{"sortedset"},
+		Docs: CommandDocs{
+			Summary:    "Returns the score of a member in a sorted set.",
+			Since:      "1.2.0",
+			Group:      "sorted-set",
+			Complexity: "O(1)",
+		},
+	},
+)
+
+func NewRhelCommand(name string) RhelCommand {
+	if entry, exists := commandRegistry.Lookup(name); exists {
+		return entry.New()
+	}
+
+	return BaseCommand(name)
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var renameSpec = ArgSpec{
+	Arity:    3,
+	FirstKey: 1,
+	LastKey:  2,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey, ArgKey},
+}
+
+type CmdRename struct {
+	BaseCommand
+}
+
+func NewCmdRename() CmdRename {
+	return CmdRename{BaseCommand: BaseCommand("RENAME")}
+}
+
+func (c CmdRename) Spec() ArgSpec {
+	return renameSpec
+}
+
+// Exec renames the key keeping its expiration, the destination is
+// overwritten.
+// notifyRename notifies the keys a value was renamed from and to.
+func notifyRename(session *Session, src, dst string) {
+	session.notify(notifyGeneric, "rename_from", src)
+	session.notify(notifyGeneric, "rename_to", dst)
+}
+
+func (c CmdRename) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	src, dst := args.At(0).String(), args.At(1).String()
+
+	found, _ := session.DB().Rename(src, dst, false)
+	if !found {
+		return nil, c.ErrWrap(rheltypes.ErrNoSuchKey)
+	}
+
+	notifyRename(session, src, dst)
+
+	return rheltypes.SimpleString("OK"), nil
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdRenameNx struct {
+	BaseCommand
+}
+
+func NewCmdRenameNx() CmdRenameNx {
+	return CmdRenameNx{BaseCommand: BaseCommand("RENAMENX")}
+}
+
+func (c CmdRenameNx) Spec() ArgSpec {
+	return renameSpec
+}
+
+// Exec renames the key like RENAME, unless the destination exists.
+func (c CmdRenameNx) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	src, dst := args.At(0).String(), args.At(1).String()
+
+	found, renamed := session.DB().Rename(src, dst, true)
+
+	switch {
+	case !found:
+		return nil, c.ErrWrap(rheltypes.ErrNoSuchKey)
+	case !renamed:
+		return rheltypes.Integer(0), nil
+	default:
+		notifyRename(session, src, dst)
+
+		return rheltypes.Integer(1), nil
+	}
+}
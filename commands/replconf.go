@@ -3,7 +3,6 @@ package commands
 import (
 	"strconv"
 
-	"github.com/codecrafters-io/redis-starter-go/connection"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -15,12 +14,17 @@ func NewCmdReplconf() CmdReplconf {
 	return CmdReplconf{BaseCommand: BaseCommand("REPLCONF")}
 }
 
+func (c CmdReplconf) Spec() ArgSpec {
+	return ArgSpec{Arity: -1, Variadic: true}
+}
+
 func (c CmdReplconf) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	switch args.Cmd() {
 	case "GETACK":
-		offset := connection.GetOffsetTracker().Current()
+		offset := session.Instance().Offset().Current()
 
 		return c.Render("ACK", strconv.Itoa(offset)), nil
 	case "ACK":
@@ -35,5 +39,3 @@ func (c CmdReplconf) Render(name, value string) (cmd rheltypes.Array) {
 		[]string{string(c.BaseCommand), name, value},
 	)
 }
-
-func (c CmdReplconf) ReplicaRespond() bool { return true }
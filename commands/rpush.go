@@ -1,9 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
-	"github.com/codecrafters-io/redis-starter-go/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -31,37 +28,20 @@ func NewCmdRPush() CmdRPush {
 	return CmdRPush{BaseCommand: BaseCommand("RPUSH")}
 }
 
+func (c CmdRPush) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+		Variadic: true,
+	}
+}
+
 func (c CmdRPush) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
-	parsedArgs, err := NewCmdRLPushArgs(args)
-	if err != nil {
-		return nil, c.ErrWrap(err)
-	}
-
-	instance := GetDataMapInstance()
-
-	value, found := instance.Get(parsedArgs.Key)
-
-	var list rheltypes.Array
-
-	var ok bool
-
-	if !found {
-		list = make(rheltypes.Array, 0)
-	} else if list, ok = value.(rheltypes.Array); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
-	}
-
-	sm := pubsub.GetStreamManager()
-
-	for _, item := range parsedArgs.Items {
-		list = append(list, item)
-
-		go sm.Publish(parsedArgs.Key, item)
-	}
-
-	instance.Set(parsedArgs.Key, list)
-
-	return rheltypes.Integer(len(list)), nil
+	return execPush(c.BaseCommand, session, args, false)
 }
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdSave struct {
+	BaseCommand
+}
+
+func NewCmdSave() CmdSave {
+	return CmdSave{BaseCommand: BaseCommand("SAVE")}
+}
+
+func (c CmdSave) Spec() ArgSpec {
+	return ArgSpec{Arity: 1}
+}
+
+// Exec writes the snapshot of the databases to the RDB file before
+// replying.
+func (c CmdSave) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	instance := session.Instance()
+
+	if err = instance.SaveDbFile(instance.DbPath()); err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	return rheltypes.SimpleString("OK"), nil
+}
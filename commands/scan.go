@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// defaultScanCount is the number of keys SCAN looks for without COUNT.
+const defaultScanCount = 10
+
+var errInvalidCursor = rheltypes.NewGenericError(errors.New("invalid cursor"))
+
+type CmdScan struct {
+	BaseCommand
+}
+
+func NewCmdScan() CmdScan {
+	return CmdScan{BaseCommand: BaseCommand("SCAN")}
+}
+
+func (c CmdScan) Spec() ArgSpec {
+	return ArgSpec{
+		Arity: -2,
+		Args:  []ArgKind{ArgString},
+		Options: []OptionSpec{
+			{Token: "MATCH", Values: []ArgKind{ArgString}},
+			{Token: "COUNT", Values: []ArgKind{ArgInteger}},
+			{Token: "TYPE", Values: []ArgKind{ArgString}},
+		},
+	}
+}
+
+// Exec replies the cursor to continue from, 0 once the scan is complete,
+// and the keys found matching the MATCH pattern and the TYPE filter. The
+// cursor is the storage bucket to visit next, so it needs no server state.
+func (c CmdScan) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	cursor, err := strconv.ParseUint(spec.Args[0].String(), 10, 64)
+	if err != nil {
+		return nil, c.ErrWrap(errInvalidCursor)
+	}
+
+	count := defaultScanCount
+
+	if opt := spec.Option("COUNT"); opt != nil {
+		if count, _ = opt.Integer(); count < 1 {
+			return nil, c.ErrWrap(rheltypes.ErrSyntax)
+		}
+	}
+
+	pattern := "*"
+	if opt := spec.Option("MATCH"); opt != nil {
+		pattern = opt.String()
+	}
+
+	instance := session.DB()
+	next, found := instance.Scan(int(min(cursor, math.MaxInt32)), count)
+
+	keys := rheltypes.Array{}
+
+	for _, key := range found {
+		if pattern != "*" && !globMatch(pattern, key) {
+			continue
+		}
+
+		if opt := spec.Option("TYPE"); opt != nil {
+			entry, exists := instance.Peek(key)
+			if !exists ||
+				!strings.EqualFold(entry.Value.TypeName(), opt.String()) {
+				continue
+			}
+		}
+
+		keys.Append(rheltypes.NewBulkString(key))
+	}
+
+	return rheltypes.Array{
+		rheltypes.NewBulkString(strconv.Itoa(next)),
+		keys,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdSelect struct {
+	BaseCommand
+}
+
+func NewCmdSelect() CmdSelect {
+	return CmdSelect{BaseCommand: BaseCommand("SELECT")}
+}
+
+func (c CmdSelect) Spec() ArgSpec {
+	return ArgSpec{Arity: 2, Args: []ArgKind{ArgInteger}}
+}
+
+func (c CmdSelect) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	index, _ := args.At(0).Integer()
+
+	if err = session.SelectDB(index); err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	return rheltypes.SimpleString("OK"), nil
+}
+
+func (c CmdSelect) Render(index int) (cmd rheltypes.Array) {
+	return rheltypes.NewArrayFromStrings(
+		[]string{string(c.BaseCommand), strconv.Itoa(index)},
+	)
+}
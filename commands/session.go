@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var lastSessionId atomic.Int64
+
+// Session holds the state of a single client connection.
+type Session struct {
+	instance    *Instance
+	id          int
+	name        string
+	protocol    rheltypes.Protocol
+	db          int
+	transaction *Transaction
+	master      bool
+	lock        sync.RWMutex
+}
+
+func NewSession(instance *Instance) *Session {
+	return &Session{
+		instance: instance,
+		id:       int(lastSessionId.Add(1)),
+		protocol: rheltypes.Resp2,
+	}
+}
+
+// NewMasterSession creates the session of the replication link on a
+// replica, the only one allowed to write to it.
+func NewMasterSession(instance *Instance) (s *Session) {
+	s = NewSession(instance)
+	s.master = true
+
+	return
+}
+
+func (s *Session) Instance() *Instance {
+	return s.instance
+}
+
+func (s *Session) Id() int {
+	return s.id
+}
+
+func (s *Session) Name() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.name
+}
+
+func (s *Session) SetName(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.name = name
+}
+
+func (s *Session) Protocol() rheltypes.Protocol {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.protocol
+}
+
+func (s *Session) SetProtocol(proto rheltypes.Protocol) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.protocol = proto
+}
+
+// DB returns the keyspace of the selected database.
+func (s *Session) DB() *rheltypes.SafeMap {
+	return s.instance.DB(s.SelectedDB())
+}
+
+func (s *Session) SelectedDB() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.db
+}
+
+func (s *Session) SelectDB(index int) error {
+	if index < 0 || index >= s.instance.Databases() {
+		return rheltypes.ErrDbIndexOutOfRange
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.db = index
+
+	return nil
+}
+
+func (s *Session) Transaction() *Transaction {
+	return s.transaction
+}
+
+// verifyMemory evicts keys when the keyspace is over maxmemory, commands
+// that may grow it are refused while not enough memory could be freed. The
+// replication link is never refused, its master evicts for it.
+func (s *Session) verifyMemory(entry *CommandEntry) error {
+	if s.master {
+		return nil
+	}
+
+	if err := s.instance.freeMemory(); err != nil && entry.Has(FlagDenyOOM) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Session) verifyWrite(entry *CommandEntry) error {
+	if !entry.Has(FlagWrite) || s.master {
+		return nil
+	}
+
+	if s.instance.IsReplica() {
+		return rheltypes.ErrReadOnly
+	}
+
+	return nil
+}
@@ -3,32 +3,21 @@ package commands
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
+// CmdSetArgs are the arguments of SET, Expiration is a unix time in
+// milliseconds and 0 when the value doesn't expire.
 type CmdSetArgs struct {
-	Key   string
-	Value rheltypes.RhelType
-	Px    int
-}
-
-func (a *CmdSetArgs) Set(name string, value rheltypes.RhelType) (err error) {
-	switch name {
-	case "PX":
-		if a.Px, err = value.Integer(); err != nil {
-			return fmt.Errorf(
-				"failed to convert value %q to integer: %w",
-				value.String(),
-				err,
-			)
-		}
-
-	default:
-		return fmt.Errorf("unknown set option %q", name)
-	}
-
-	return nil
+	Key        string
+	Value      rheltypes.RhelType
+	Expiration int64
+	KeepTtl    bool
+	Nx         bool
+	Xx         bool
+	Get        bool
 }
 
 type CmdSet struct {
@@ -39,45 +28,174 @@ func NewCmdSet() CmdSet {
 	return CmdSet{BaseCommand: BaseCommand("SET")}
 }
 
+// expireOptions are the ways SET and GETEX accept an expiration, at most
+// one of them can be given.
+var expireOptions = []struct {
+	token    string
+	unit     time.Duration
+	absolute bool
+}{
+	{token: "EX", unit: time.Second},
+	{token: "PX", unit: time.Millisecond},
+	{token: "EXAT", unit: time.Second, absolute: true},
+	{token: "PXAT", unit: time.Millisecond, absolute: true},
+}
+
+func (c CmdSet) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+		Options: []OptionSpec{
+			{Token: "EX", Values: []ArgKind{ArgInteger}},
+			{Token: "PX", Values: []ArgKind{ArgInteger}},
+			{Token: "EXAT", Values: []ArgKind{ArgInteger}},
+			{Token: "PXAT", Values: []ArgKind{ArgInteger}},
+			{Token: "NX"},
+			{Token: "XX"},
+			{Token: "KEEPTTL"},
+			{Token: "GET"},
+		},
+	}
+}
+
+func newInvalidExpireError(cmd string) error {
+	return rheltypes.NewGenericError(fmt.Errorf(
+		"invalid expire time in '%s' command",
+		strings.ToLower(cmd),
+	))
+}
+
+// parseExpireOption returns the expiration given by one of expireOptions,
+// or 0 when there is none. Only positive times are valid.
+func parseExpireOption(
+	name string,
+	spec ParsedArgs,
+) (expiration int64, found bool, err error) {
+	for _, opt := range expireOptions {
+		value := spec.Option(opt.token)
+		if value == nil {
+			continue
+		}
+
+		if found {
+			return 0, found, rheltypes.ErrSyntax
+		}
+
+		found = true
+
+		when, _ := value.Integer()
+		if when <= 0 {
+			return 0, found, newInvalidExpireError(name)
+		}
+
+		expiration, err = absoluteExpiration(name, when, opt.unit, opt.absolute)
+		if err != nil {
+			return 0, found, err
+		}
+	}
+
+	return expiration, found, nil
+}
+
 func parseSetArgs(args rheltypes.Array) (parsed CmdSetArgs, err error) {
-	setKey := args.At(0)
-	if setKey == nil {
-		return parsed, fmt.Errorf("missing key")
+	c := NewCmdSet()
+
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return parsed, err
 	}
 
-	parsed.Key = setKey.String()
-	setValue := args.At(1)
+	parsed.Key = spec.Args[0].String()
+	parsed.Value = spec.Args[1]
+	parsed.KeepTtl = spec.Has("KEEPTTL")
+	parsed.Nx = spec.Has("NX")
+	parsed.Xx = spec.Has("XX")
+	parsed.Get = spec.Has("GET")
+
+	if parsed.Nx && parsed.Xx {
+		return parsed, rheltypes.ErrSyntax
+	}
 
-	if setValue == nil {
-		return parsed, fmt.Errorf("missing value")
+	expiration, hasExpiration, err := parseExpireOption(c.Name(), spec)
+	if err != nil {
+		return parsed, err
 	}
 
-	parsed.Value = setValue
+	if hasExpiration && parsed.KeepTtl {
+		return parsed, rheltypes.ErrSyntax
+	}
+
+	parsed.Expiration = expiration
+
+	return parsed, nil
+}
 
-	lastField := ""
+func isStringValue(value rheltypes.RhelType) bool {
+	switch value.(type) {
+	case rheltypes.BulkString, rheltypes.IntString:
+		return true
+	default:
+		return false
+	}
+}
 
-	for _, arg := range args[2:] {
-		switch name := strings.ToUpper(arg.String()); name {
-		case "PX":
-			lastField = name
-		default:
-			if lastField == "" {
-				continue
-			}
+// set stores the value unless the NX or XX condition fails, it returns the
+// value it replaced, if any. With GET, a value that isn't a string is left
+// untouched and reported as an error. A stored value is notified as set,
+// along with its expiration.
+func (a CmdSetArgs) set(
+	session *Session,
+) (old rheltypes.RhelType, stored bool, err error) {
+	session.DB().Compute(a.Key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		if found {
+			old = current.Value
+		}
+
+		if a.Get && found && !isStringValue(current.Value) {
+			err = rheltypes.ErrWrongType
 
-			err = parsed.Set(lastField, arg)
-			if err != nil {
-				return parsed, err
-			}
+			return current, rheltypes.ComputeKeep
+		}
 
-			lastField = ""
+		if a.Nx && found || a.Xx && !found {
+			return current, rheltypes.ComputeKeep
+		}
+
+		next := rheltypes.RhelMapValue{
+			Value:      a.Value,
+			Expiration: a.Expiration,
+		}
+
+		if a.KeepTtl {
+			next.Expiration = current.Expiration
+		}
+
+		stored = true
+
+		return next, rheltypes.ComputeStore
+	})
+
+	if stored {
+		session.notify(notifyString, "set", a.Key)
+
+		if a.Expiration != 0 {
+			session.notify(notifyGeneric, expireEvent(a.Expiration), a.Key)
 		}
 	}
 
-	return parsed, err
+	return old, stored, err
 }
 
+// Exec replies OK, or null when the NX or XX condition fails. With GET it
+// replies the previous value instead, null if there was none.
 func (c CmdSet) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	parsedArgs, err := parseSetArgs(args)
@@ -85,19 +203,16 @@ func (c CmdSet) Exec(
 		return nil, c.ErrWrap(err)
 	}
 
-	instance := GetDataMapInstance()
+	old, stored, err := parsedArgs.set(session)
 
-	if parsedArgs.Px > 0 {
-		instance.SetToExpire(
-			parsedArgs.Key,
-			parsedArgs.Value,
-			int64(parsedArgs.Px),
-		)
-	} else {
-		instance.Set(parsedArgs.Key, parsedArgs.Value)
+	switch {
+	case err != nil:
+		return nil, c.ErrWrap(err)
+	case parsedArgs.Get && old != nil:
+		return old, nil
+	case parsedArgs.Get, !stored:
+		return rheltypes.NewNullBulkString(), nil
+	default:
+		return rheltypes.SimpleString("OK"), nil
 	}
-
-	return rheltypes.SimpleString("OK"), nil
 }
-
-func (c CmdSet) Resend() bool { return true }
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdSetBit struct {
+	BaseCommand
+}
+
+func NewCmdSetBit() CmdSetBit {
+	return CmdSetBit{BaseCommand: BaseCommand("SETBIT")}
+}
+
+func (c CmdSetBit) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    4,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString, ArgString},
+	}
+}
+
+// Exec sets the bit of the string at key at offset and replies its former
+// value. The string grows with zero bytes to hold the offset, the string
+// being copied since values are shared.
+func (c CmdSetBit) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	key := spec.Args[0].String()
+
+	offset, err := parseBitOffset(spec.Args[1])
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	bit, err := parseBit(spec.Args[2], errBitValue)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	var old byte
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		text := ""
+
+		if found {
+			if text, err = stringValue(current.Value); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+		}
+
+		old = bitAt(text, offset)
+
+		buf := make([]byte, max(len(text), offset>>3+1))
+		copy(buf, text)
+
+		mask := byte(1) << (7 - offset&7)
+
+		if bit == 1 {
+			buf[offset>>3] |= mask
+		} else {
+			buf[offset>>3] &^= mask
+		}
+
+		current.Value = rheltypes.NewBulkString(string(buf))
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	session.notify(notifyString, "setbit", key)
+
+	return rheltypes.Integer(old), nil
+}
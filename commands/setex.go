@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var setExSpec = ArgSpec{
+	Arity:    4,
+	FirstKey: 1,
+	LastKey:  1,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey, ArgInteger, ArgString},
+}
+
+// execSetEx sets the value to expire after the time given in unit, which
+// has to be positive.
+func execSetEx(
+	c BaseCommand,
+	session *Session,
+	args rheltypes.Array,
+	unit time.Duration,
+) (rheltypes.RhelType, error) {
+	when, _ := args.At(1).Integer()
+	if when <= 0 {
+		return nil, c.ErrWrap(newInvalidExpireError(c.Name()))
+	}
+
+	expiration, err := absoluteExpiration(c.Name(), when, unit, false)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	setArgs := CmdSetArgs{
+		Key:        args.At(0).String(),
+		Value:      args.At(2),
+		Expiration: expiration,
+	}
+
+	setArgs.set(session)
+
+	return rheltypes.SimpleString("OK"), nil
+}
+
+type CmdSetEx struct {
+	BaseCommand
+}
+
+func NewCmdSetEx() CmdSetEx {
+	return CmdSetEx{BaseCommand: BaseCommand("SETEX")}
+}
+
+func (c CmdSetEx) Spec() ArgSpec {
+	return setExSpec
+}
+
+func (c CmdSetEx) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execSetEx(c.BaseCommand, session, args, time.Second)
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdSetNx struct {
+	BaseCommand
+}
+
+func NewCmdSetNx() CmdSetNx {
+	return CmdSetNx{BaseCommand: BaseCommand("SETNX")}
+}
+
+func (c CmdSetNx) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+	}
+}
+
+func (c CmdSetNx) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	setArgs := CmdSetArgs{
+		Key:   args.At(0).String(),
+		Value: args.At(1),
+		Nx:    true,
+	}
+
+	if _, stored, _ := setArgs.set(session); !stored {
+		return rheltypes.Integer(0), nil
+	}
+
+	return rheltypes.Integer(1), nil
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var errOffsetOutOfRange = rheltypes.NewGenericError(
+	errors.New("offset is out of range"),
+)
+
+type CmdSetRange struct {
+	BaseCommand
+}
+
+func NewCmdSetRange() CmdSetRange {
+	return CmdSetRange{BaseCommand: BaseCommand("SETRANGE")}
+}
+
+func (c CmdSetRange) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    4,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgInteger, ArgString},
+	}
+}
+
+// Exec overwrites the string at key from offset on, padding it with zero
+// bytes up to offset, and replies its new length. An empty value leaves
+// the key untouched, it isn't even created.
+func (c CmdSetRange) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	key := spec.Args[0].String()
+	offset, _ := spec.Args[1].Integer()
+	patch := spec.Args[2].String()
+
+	if offset < 0 {
+		return nil, c.ErrWrap(errOffsetOutOfRange)
+	}
+
+	length := 0
+
+	session.DB().Compute(key, func(
+		current rheltypes.RhelMapValue,
+		found bool,
+	) (rheltypes.RhelMapValue, rheltypes.ComputeAction) {
+		text := ""
+
+		if found {
+			if text, err = stringValue(current.Value); err != nil {
+				return current, rheltypes.ComputeKeep
+			}
+		}
+
+		length = len(text)
+
+		if len(patch) == 0 {
+			return current, rheltypes.ComputeKeep
+		}
+
+		if offset > maxStringLength-len(patch) {
+			err = errStringTooLong
+
+			return current, rheltypes.ComputeKeep
+		}
+
+		current.Value = rheltypes.NewBulkString(overwrite(text, offset, patch))
+		length = max(length, offset+len(patch))
+
+		return current, rheltypes.ComputeStore
+	})
+
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	if len(patch) > 0 {
+		session.notify(notifyString, "setrange", key)
+	}
+
+	return rheltypes.Integer(length), nil
+}
+
+// overwrite returns text with patch written at offset, text is padded with
+// zero bytes if it is shorter than offset.
+func overwrite(text string, offset int, patch string) string {
+	var b strings.Builder
+
+	b.Grow(max(len(text), offset+len(patch)))
+	b.WriteString(text[:min(offset, len(text))])
+
+	for range offset - len(text) {
+		b.WriteByte(0)
+	}
+
+	b.WriteString(patch)
+
+	if end := offset + len(patch); end < len(text) {
+		b.WriteString(text[end:])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+// maxStringLength is the size a string value can grow to, the default
+// proto-max-bulk-len of Redis.
+const maxStringLength = 512 << 20
+
+var errStringTooLong = rheltypes.NewGenericError(errors.New(
+	"string exceeds maximum allowed size (proto-max-bulk-len)",
+))
+
+// stringValue returns the content of a string value, ErrWrongType if value
+// is of another type. The content is shared and must not be changed.
+func stringValue(value rheltypes.RhelType) (string, error) {
+	if !isStringValue(value) {
+		return "", rheltypes.ErrWrongType
+	}
+
+	return value.String(), nil
+}
+
+// getString returns the string stored at key, ErrWrongType if key holds
+// another type.
+func getString(
+	db *rheltypes.SafeMap,
+	key string,
+) (value string, found bool, err error) {
+	current, found := db.Get(key)
+	if !found {
+		return "", false, nil
+	}
+
+	value, err = stringValue(current)
+
+	return value, true, err
+}
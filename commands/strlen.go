@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdStrLen struct {
+	BaseCommand
+}
+
+func NewCmdStrLen() CmdStrLen {
+	return CmdStrLen{BaseCommand: BaseCommand("STRLEN")}
+}
+
+func (c CmdStrLen) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+	}
+}
+
+// Exec replies the length of the string at key in bytes, 0 when missing.
+func (c CmdStrLen) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	text, _, err := getString(session.DB(), args.At(0).String())
+	if err != nil {
+		return nil, c.ErrWrap(err)
+	}
+
+	return rheltypes.Integer(len(text)), nil
+}
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"github.com/codecrafters-io/redis-starter-go/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -15,12 +14,17 @@ func NewCmdSubscribe() CmdSubscribe {
 	return CmdSubscribe{BaseCommand: BaseCommand("SUBSCRIBE")}
 }
 
+func (c CmdSubscribe) Spec() ArgSpec {
+	return ArgSpec{Arity: -2, Variadic: true}
+}
+
 func (c CmdSubscribe) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(0).String()
 
-	sub := pubsub.GetStreamManager().Subscribe(key, false)
+	sub := session.Instance().Broker().Subscribe(key, false)
 
 	// // timeout, _ := args.At(1).Float()
 	// last, err := pubsub.ReadLast(key, int(timeout*milisecondInSecond))
@@ -35,10 +39,8 @@ func (c CmdSubscribe) Exec(
 	// 		last,
 	// 	}, nil
 	// }
-	arr := rheltypes.NewArrayFromStrings([]string{"subscribe", key})
+	arr := rheltypes.NewPushFromStrings([]string{"subscribe", key})
 	value = append(arr, rheltypes.Integer(sub.Id))
 
 	return value, nil
 }
-
-func (c CmdSubscribe) AllowedInSubscription() bool { return true }
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdSubStr struct {
+	BaseCommand
+}
+
+func NewCmdSubStr() CmdSubStr {
+	return CmdSubStr{BaseCommand: BaseCommand("SUBSTR")}
+}
+
+func (c CmdSubStr) Spec() ArgSpec {
+	return getRangeSpec
+}
+
+func (c CmdSubStr) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return execGetRange(c.BaseCommand, session, args)
+}
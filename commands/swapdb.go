@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+var (
+	errSwapDbFirstIndex = rheltypes.NewGenericError(
+		errors.New("invalid first DB index"),
+	)
+	errSwapDbSecondIndex = rheltypes.NewGenericError(
+		errors.New("invalid second DB index"),
+	)
+)
+
+type CmdSwapDb struct {
+	BaseCommand
+}
+
+func NewCmdSwapDb() CmdSwapDb {
+	return CmdSwapDb{BaseCommand: BaseCommand("SWAPDB")}
+}
+
+func (c CmdSwapDb) Spec() ArgSpec {
+	return ArgSpec{Arity: 3, Args: []ArgKind{ArgString, ArgString}}
+}
+
+func (c CmdSwapDb) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	instance := session.Instance()
+
+	first, err := strconv.Atoi(args.At(0).String())
+	if err != nil {
+		return nil, c.ErrWrap(errSwapDbFirstIndex)
+	}
+
+	second, err := strconv.Atoi(args.At(1).String())
+	if err != nil {
+		return nil, c.ErrWrap(errSwapDbSecondIndex)
+	}
+
+	for _, index := range []int{first, second} {
+		if index < 0 || index >= instance.Databases() {
+			return nil, c.ErrWrap(rheltypes.ErrDbIndexOutOfRange)
+		}
+	}
+
+	instance.SwapDB(first, second)
+
+	return rheltypes.SimpleString("OK"), nil
+}
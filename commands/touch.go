@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+type CmdTouch struct {
+	BaseCommand
+}
+
+func NewCmdTouch() CmdTouch {
+	return CmdTouch{BaseCommand: BaseCommand("TOUCH")}
+}
+
+func (c CmdTouch) Spec() ArgSpec {
+	return multiKeySpec
+}
+
+// Exec replies the number of keys that exist, their last access time and
+// access frequency are updated.
+func (c CmdTouch) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return rheltypes.Integer(countKeys(session.DB().Entry, args)), nil
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const (
+	ttlKeyMissing  = -2
+	ttlKeyNoExpiry = -1
+)
+
+var ttlSpec = ArgSpec{
+	Arity:    2,
+	FirstKey: 1,
+	LastKey:  1,
+	KeyStep:  1,
+	Args:     []ArgKind{ArgKey},
+}
+
+// keyTtl replies with the time to live of the key in unit, or with its
+// expiration as a unix time when absolute. Missing keys reply -2 and keys
+// without expiration -1.
+func keyTtl(
+	session *Session,
+	args rheltypes.Array,
+	unit time.Duration,
+	absolute bool,
+) rheltypes.Integer {
+	entry, found := session.DB().Peek(args.At(0).String())
+
+	switch {
+	case !found:
+		return ttlKeyMissing
+	case entry.Expiration == 0:
+		return ttlKeyNoExpiry
+	}
+
+	scale := int64(unit / time.Millisecond)
+
+	if absolute {
+		return rheltypes.Integer(entry.Expiration / scale)
+	}
+
+	ttl := max(entry.Expiration-time.Now().UnixMilli(), 0)
+
+	// Rounded to the closest unit like Redis does.
+	return rheltypes.Integer((ttl + scale/2) / scale)
+}
+
+type CmdTtl struct {
+	BaseCommand
+}
+
+func NewCmdTtl() CmdTtl {
+	return CmdTtl{BaseCommand: BaseCommand("TTL")}
+}
+
+func (c CmdTtl) Spec() ArgSpec {
+	return ttlSpec
+}
+
+func (c CmdTtl) Exec(
+	session *Session,
+	args rheltypes.Array,
+) (value rheltypes.RhelType, err error) {
+	return keyTtl(session, args, time.Second, false), nil
+}
@@ -14,7 +14,18 @@ func NewCmdType() CmdType {
 	return CmdType{BaseCommand: BaseCommand("TYPE")}
 }
 
+func (c CmdType) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+	}
+}
+
 func (c CmdType) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (valueType rheltypes.RhelType, err error) {
 	key := args.At(0)
@@ -22,12 +33,12 @@ func (c CmdType) Exec(
 		return nil, c.ErrWrap(fmt.Errorf("missing key"))
 	}
 
-	instance := GetDataMapInstance()
+	instance := session.DB()
 
 	valueType = rheltypes.SimpleString("none")
 
-	if value, found := instance.Get(key.String()); found {
-		valueType = rheltypes.SimpleString(value.TypeName())
+	if entry, found := instance.Peek(key.String()); found {
+		valueType = rheltypes.SimpleString(entry.Value.TypeName())
 	}
 
 	return
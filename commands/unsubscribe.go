@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"github.com/codecrafters-io/redis-starter-go/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -13,13 +12,20 @@ func NewCmdUnsubscribe() CmdUnsubscribe {
 	return CmdUnsubscribe{BaseCommand: BaseCommand("UNSUBSCRIBE")}
 }
 
+func (c CmdUnsubscribe) Spec() ArgSpec {
+	return ArgSpec{Arity: -2, Variadic: true}
+}
+
 func (c CmdUnsubscribe) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(0).String()
-	id, _ := args.At(1).Integer()
 
-	pubsub.GetStreamManager().Unsubscribe(key, id)
+	if subscription := args.At(1); subscription != nil {
+		id, _ := subscription.Integer()
+		session.Instance().Broker().Unsubscribe(key, id)
+	}
 
 	// // timeout, _ := args.At(1).Float()
 	// last, err := pubsub.ReadLast(key, int(timeout*milisecondInSecond))
@@ -34,9 +40,7 @@ func (c CmdUnsubscribe) Exec(
 	// 		last,
 	// 	}, nil
 	// }
-	arr := rheltypes.NewArrayFromStrings([]string{"unsubscribe", key})
+	arr := rheltypes.NewPushFromStrings([]string{"unsubscribe", key})
 
 	return append(arr, rheltypes.Integer(0)), nil
 }
-
-func (c CmdUnsubscribe) AllowedInSubscription() bool { return true }
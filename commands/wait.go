@@ -3,7 +3,6 @@ package commands
 import (
 	"time"
 
-	"github.com/codecrafters-io/redis-starter-go/connection"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -17,7 +16,12 @@ func NewCmdWait() CmdWait {
 	return CmdWait{BaseCommand: BaseCommand("WAIT")}
 }
 
+func (c CmdWait) Spec() ArgSpec {
+	return ArgSpec{Arity: 3, Args: []ArgKind{ArgInteger, ArgInteger}}
+}
+
 func (c CmdWait) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	required, _ := args.At(0).Integer()
@@ -27,7 +31,7 @@ func (c CmdWait) Exec(
 		return rheltypes.Integer(0), nil
 	}
 
-	conn := connection.GetConnectionPool()
+	conn := session.Instance().Replicas()
 
 	if conn.NumResend() == 0 {
 		return rheltypes.Integer(conn.NumAcknowledged()), nil
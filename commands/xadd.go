@@ -1,10 +1,8 @@
 package commands
 
 import (
-	"fmt"
 	"slices"
 
-	"github.com/codecrafters-io/redis-starter-go/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -20,6 +18,10 @@ func NewXAddArgs(args rheltypes.Array) (parsed CmdXAddArgs, err error) {
 	parsed.Key = args.At(0).String()
 	parsed.Id = args.At(1).String()
 	items := args[2:]
+
+	if len(items)%defaultXAddSliceSize != 0 {
+		return parsed, newArityError("XADD")
+	}
 	parsed.Items = make(map[string]string, len(items)/defaultXAddSliceSize)
 
 	for pair := range slices.Chunk(items, defaultXAddSliceSize) {
@@ -37,7 +39,19 @@ func NewCmdXAdd() CmdXAdd {
 	return CmdXAdd{BaseCommand: BaseCommand("XADD")}
 }
 
+func (c CmdXAdd) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -5,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+		Variadic: true,
+	}
+}
+
 func (c CmdXAdd) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	parsedArgs, err := NewXAddArgs(args)
@@ -45,9 +59,9 @@ func (c CmdXAdd) Exec(
 		return nil, c.ErrWrap(err)
 	}
 
-	instance := GetDataMapInstance()
+	instance := session.DB()
 
-	value, found := instance.Get(parsedArgs.Key)
+	entry, found := instance.Peek(parsedArgs.Key)
 
 	var stream rheltypes.Stream
 
@@ -55,8 +69,8 @@ func (c CmdXAdd) Exec(
 
 	if !found {
 		stream = rheltypes.NewStream()
-	} else if stream, ok = value.(rheltypes.Stream); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
+	} else if stream, ok = entry.Value.(rheltypes.Stream); !ok {
+		return nil, c.ErrWrap(rheltypes.ErrWrongType)
 	}
 
 	var addedId string
@@ -65,10 +79,11 @@ func (c CmdXAdd) Exec(
 		return rheltypes.NewGenericError(err), nil
 	}
 
-	instance.Set(parsedArgs.Key, stream)
+	instance.Update(parsedArgs.Key, stream)
+	session.notify(notifyStream, "xadd", parsedArgs.Key)
 
-	if len(stream) > 0 {
-		sm := pubsub.GetStreamManager()
+	if stream.Len() > 0 {
+		sm := session.Instance().Broker()
 
 		go sm.Publish(parsedArgs.Key, stream.At(-1))
 	}
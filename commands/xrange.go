@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -14,6 +12,19 @@ func NewCmdXRange() CmdXRange {
 	return CmdXRange{BaseCommand: BaseCommand("XRANGE")}
 }
 
+func (c CmdXRange) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -4,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString, ArgString},
+		Options: []OptionSpec{
+			{Token: "COUNT", Values: []ArgKind{ArgInteger}},
+		},
+	}
+}
+
 const (
 	posXRangeKey   = 0
 	posXRangeLower = 1
@@ -21,10 +32,11 @@ const (
 )
 
 func (c CmdXRange) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(posXRangeKey).String()
-	got, found := GetDataMapInstance().Get(key)
+	got, found := session.DB().Get(key)
 
 	if !found {
 		return make(rheltypes.Array, 0), nil
@@ -35,14 +47,26 @@ func (c CmdXRange) Exec(
 	var ok bool
 
 	if stream, ok = got.(rheltypes.Stream); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
+		return nil, c.ErrWrap(rheltypes.ErrWrongType)
 	}
 
-	value = stream.Range(
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return nil, err
+	}
+
+	items := stream.Range(
 		args.At(posXRangeLower).String(),
 		args.At(posXRangeUpper).String(),
 		true,
-	).ToArray()
+	)
+
+	if count := spec.Option("COUNT"); count != nil {
+		n, _ := count.Integer()
+		items = items[:min(max(n, 0), len(items))]
+	}
+
+	value = items.ToArray()
 
 	return
 }
@@ -2,9 +2,7 @@ package commands
 
 import (
 	"fmt"
-	"strings"
 
-	"github.com/codecrafters-io/redis-starter-go/pubsub"
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -16,6 +14,17 @@ func NewCmdXRead() CmdXRead {
 	return CmdXRead{BaseCommand: BaseCommand("XREAD")}
 }
 
+func (c CmdXRead) Spec() ArgSpec {
+	return ArgSpec{
+		Arity: -4,
+		Options: []OptionSpec{
+			{Token: "COUNT", Values: []ArgKind{ArgInteger}},
+			{Token: "BLOCK", Values: []ArgKind{ArgInteger}},
+			{Token: "STREAMS", Rest: true},
+		},
+	}
+}
+
 const (
 	numXReadStreamSections = 2
 	numXReadValueSections  = 2
@@ -32,37 +41,45 @@ type CmdXReadStream struct {
 
 type CmdXReadArgs struct {
 	block   int
+	count   int
 	streams []CmdXReadStream
 }
 
-func NewCmdXReadArgs(args rheltypes.Array) (parsed CmdXReadArgs) {
-	parsed.block = -1
-
-	streamsIndex := -1
+func NewCmdXReadArgs(args rheltypes.Array) (parsed CmdXReadArgs, err error) {
+	c := NewCmdXRead()
 
-	var readInteger *int
+	spec, err := c.Spec().Parse(c.Name(), args)
+	if err != nil {
+		return parsed, err
+	}
 
-	for i, a := range args {
-		switch strings.ToUpper(a.String()) {
-		case "BLOCK":
-			readInteger = &parsed.block
+	parsed.block = -1
+	parsed.count = -1
 
-			continue
-		case "STREAMS":
-			streamsIndex = i + 1
+	if block := spec.Option("BLOCK"); block != nil {
+		if parsed.block, _ = block.Integer(); parsed.block < 0 {
+			return parsed, rheltypes.NewGenericError(
+				fmt.Errorf("timeout is negative"),
+			)
 		}
+	}
 
-		if readInteger != nil {
-			*readInteger, _ = a.Integer()
-			readInteger = nil
-		}
+	if count := spec.Option("COUNT"); count != nil {
+		parsed.count, _ = count.Integer()
+	}
 
-		if streamsIndex != -1 {
-			break
-		}
+	if !spec.Has("STREAMS") {
+		return parsed, rheltypes.ErrSyntax
 	}
 
-	args = args[streamsIndex:]
+	args = spec.Options["STREAMS"]
+
+	if len(args) == 0 || len(args)%numXReadStreamSections != 0 {
+		return parsed, rheltypes.NewGenericError(fmt.Errorf(
+			"Unbalanced 'xread' list of streams: " +
+				"for each stream key an ID or '$' must be specified.",
+		))
+	}
 
 	half := len(args) / numXReadStreamSections
 
@@ -75,15 +92,17 @@ func NewCmdXReadArgs(args rheltypes.Array) (parsed CmdXReadArgs) {
 		}
 	}
 
-	return parsed
+	return parsed, nil
 }
 
 func (c CmdXRead) ReadAll(
+	session *Session,
 	streams []CmdXReadStream,
+	count int,
 ) (values rheltypes.Array, err error) {
-	values = make(rheltypes.Array, len(streams))
+	values = make(rheltypes.Array, 0, len(streams))
 
-	for s, streamSpec := range streams {
+	for _, streamSpec := range streams {
 		if streamSpec.id == "$" {
 			continue
 		}
@@ -92,7 +111,7 @@ func (c CmdXRead) ReadAll(
 
 		streamArray[0] = rheltypes.NewBulkString(streamSpec.key)
 
-		got, found := GetDataMapInstance().Get(streamSpec.key)
+		got, found := session.DB().Get(streamSpec.key)
 
 		if !found {
 			return nil, fmt.Errorf("stream %q not found", streamSpec.key)
@@ -101,35 +120,46 @@ func (c CmdXRead) ReadAll(
 		stream, ok := got.(rheltypes.Stream)
 
 		if !ok {
-			return nil, fmt.Errorf(
-				"expected stream from %q, got %T %v",
-				streamSpec.key,
-				got,
-				got,
-			)
+			return nil, rheltypes.ErrWrongType
 		}
 
-		streamArray[1] = stream.Range(
-			streamSpec.id,
-			"+",
-			false,
-		).ToArray()
+		items := stream.Range(streamSpec.id, "+", false)
 
-		values[s] = streamArray
+		if count > 0 {
+			items = items[:min(count, len(items))]
+		}
+
+		streamArray[1] = items.ToArray()
+
+		values = append(values, streamArray)
 	}
 
 	return values, err
 }
 
 func (c CmdXRead) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
-	parsedArgs := NewCmdXReadArgs(args)
+	parsedArgs, err := NewCmdXReadArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Within a transaction, which holds the locks of its keys, XREAD
+	// doesn't block.
+	if session.Transaction() != nil {
+		parsedArgs.block = -1
+	}
 
 	valueArray := make(rheltypes.Array, 0, len(parsedArgs.streams))
 
 	if parsedArgs.block == -1 {
-		if valueArray, err = c.ReadAll(parsedArgs.streams); err != nil {
+		if valueArray, err = c.ReadAll(
+			session,
+			parsedArgs.streams,
+			parsedArgs.count,
+		); err != nil {
 			return nil, c.ErrWrap(fmt.Errorf("failed to read all: %w", err))
 		}
 	}
@@ -137,7 +167,7 @@ func (c CmdXRead) Exec(
 	if parsedArgs.block > -1 {
 		key := parsedArgs.streams[0].key
 
-		lastItem, err := pubsub.ReadLast(key, parsedArgs.block)
+		lastItem, err := session.Instance().Broker().ReadLast(key, parsedArgs.block)
 		if err != nil {
 			return nil, c.ErrWrap(fmt.Errorf("failed to read last: %w", err))
 		}
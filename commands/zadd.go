@@ -1,32 +1,15 @@
 package commands
 
 import (
-	"fmt"
+	"math"
+	"slices"
 
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
-// type CmdRPushArgs struct {
-// 	Key   string
-// 	Items []rheltypes.RhelType
-// }
-
-// func NewCmdRLPushArgs(args rheltypes.Array) (parsed CmdRPushArgs, err error)
-// {
-// 	parsed.Key = args.At(0).String()
-// 	parsed.Items = make([]rheltypes.RhelType, len(args)-1)
-
-// 	if len(args) > 1 {
-// 		copy(parsed.Items, args[1:])
-// 	}
-
-// 	return
-// }
-
 const (
 	cmdZAddNameArg  = 0
-	cmdZAddScoreArg = 1
-	cmdZAddKeyArg   = 2
+	cmdZAddPairSize = 2
 )
 
 type CmdZAdd struct {
@@ -37,31 +20,61 @@ func NewCmdZAdd() CmdZAdd {
 	return CmdZAdd{BaseCommand: BaseCommand("ZADD")}
 }
 
+func (c CmdZAdd) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -4,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgFloat, ArgString},
+		Variadic: true,
+	}
+}
+
 func (c CmdZAdd) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	name := args.At(cmdZAddNameArg).String()
-	key := args.At(cmdZAddKeyArg).String()
-	score, _ := args.At(cmdZAddScoreArg).Float()
-	instance := GetDataMapInstance()
+	pairs := args[cmdZAddNameArg+1:]
+
+	if len(pairs)%cmdZAddPairSize != 0 {
+		return nil, rheltypes.ErrSyntax
+	}
+
+	scores := make([]float64, 0, len(pairs)/cmdZAddPairSize)
+
+	for pair := range slices.Chunk(pairs, cmdZAddPairSize) {
+		score, err := rheltypes.ParseDouble(pair[0].String())
+		if err != nil || math.IsNaN(score) {
+			return nil, rheltypes.ErrNotFloat
+		}
+
+		scores = append(scores, score)
+	}
+
+	instance := session.DB()
 
 	var set rheltypes.SortedSet
 
 	var ok bool
 
-	if value, found := instance.Get(name); !found {
+	if entry, found := instance.Peek(name); !found {
 		set = *rheltypes.NewSortedSet()
-	} else if set, ok = value.(rheltypes.SortedSet); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
+	} else if set, ok = entry.Value.(rheltypes.SortedSet); !ok {
+		return nil, c.ErrWrap(rheltypes.ErrWrongType)
 	}
 
-	value = rheltypes.Integer(0)
+	added := 0
 
-	if !set.Add(key, score) {
-		value = rheltypes.Integer(1)
+	for i, score := range scores {
+		if !set.Add(pairs[i*cmdZAddPairSize+1].String(), score) {
+			added++
+		}
 	}
 
-	instance.Set(name, set)
+	instance.Update(name, set)
+	session.notify(notifySortedSet, "zadd", name)
 
-	return
+	return rheltypes.Integer(added), nil
 }
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -14,15 +12,26 @@ func NewCmdZCard() CmdZCard {
 	return CmdZCard{BaseCommand: BaseCommand("ZCARD")}
 }
 
+func (c CmdZCard) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    2,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+	}
+}
+
 const (
 	posZCardNameArg = 0
 )
 
 func (c CmdZCard) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(posZCardNameArg).String()
-	item, found := GetDataMapInstance().Get(key)
+	item, found := session.DB().Get(key)
 
 	if !found {
 		return rheltypes.Integer(0), nil
@@ -33,7 +42,7 @@ func (c CmdZCard) Exec(
 	var ok bool
 
 	if set, ok = item.(rheltypes.SortedSet); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
+		return nil, c.ErrWrap(rheltypes.ErrWrongType)
 	}
 
 	return rheltypes.Integer(set.Size()), nil
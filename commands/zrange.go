@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -14,6 +12,16 @@ func NewCmdZRange() CmdZRange {
 	return CmdZRange{BaseCommand: BaseCommand("ZRANGE")}
 }
 
+func (c CmdZRange) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -4,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgInteger, ArgInteger},
+	}
+}
+
 const (
 	posZRangeNameArg  = 0
 	posZRangeStartArg = 1
@@ -21,10 +29,11 @@ const (
 )
 
 func (c CmdZRange) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	key := args.At(posZRangeNameArg).String()
-	item, found := GetDataMapInstance().Get(key)
+	item, found := session.DB().Get(key)
 
 	if !found {
 		return make(rheltypes.Array, 0), nil
@@ -35,7 +44,7 @@ func (c CmdZRange) Exec(
 	var ok bool
 
 	if set, ok = item.(rheltypes.SortedSet); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected stream, got %T", value))
+		return nil, c.ErrWrap(rheltypes.ErrWrongType)
 	}
 
 	start, _ := args.At(posZRangeStartArg).Integer()
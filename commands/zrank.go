@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"log"
 
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
@@ -15,17 +14,28 @@ func NewCmdZRank() CmdZRank {
 	return CmdZRank{BaseCommand: BaseCommand("ZRANK")}
 }
 
+func (c CmdZRank) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+	}
+}
+
 const (
 	posZRankNameArg = 0
 	posZRankKeyArg  = 1
 )
 
 func (c CmdZRank) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	name := args.At(posZRankNameArg).String()
 	key := args.At(posZRankKeyArg).String()
-	item, found := GetDataMapInstance().Get(name)
+	item, found := session.DB().Get(name)
 
 	if !found {
 		log.Println("item nit found", name)
@@ -38,7 +48,7 @@ func (c CmdZRank) Exec(
 	var ok bool
 
 	if set, ok = item.(rheltypes.SortedSet); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected sorted set, got %T", value))
+		return nil, c.ErrWrap(rheltypes.ErrWrongType)
 	}
 
 	index, found := set.Index(key)
@@ -1,8 +1,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
 )
 
@@ -19,32 +17,58 @@ func NewCmdZRem() CmdZRem {
 	return CmdZRem{BaseCommand: BaseCommand("ZREM")}
 }
 
+func (c CmdZRem) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    -3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey},
+		Variadic: true,
+	}
+}
+
 func (c CmdZRem) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	name := args.At(cmdZRemNameArg).String()
-	key := args.At(cmdZRemKeyArg).String()
-	instance := GetDataMapInstance()
+	instance := session.DB()
 
-	item, found := GetDataMapInstance().Get(name)
+	item, found := instance.Peek(name)
 
 	if !found {
-		return make(rheltypes.Array, 0), nil
+		return rheltypes.Integer(0), nil
 	}
 
 	var set rheltypes.SortedSet
 
 	var ok bool
 
-	if set, ok = item.(rheltypes.SortedSet); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected sorted set, got %T", value))
+	if set, ok = item.Value.(rheltypes.SortedSet); !ok {
+		return nil, c.ErrWrap(rheltypes.ErrWrongType)
+	}
+
+	removed := 0
+
+	for _, key := range args[cmdZRemKeyArg:] {
+		if set.Delete(key.String()) {
+			removed++
+		}
+	}
+
+	if removed == 0 {
+		return rheltypes.Integer(0), nil
 	}
 
-	if set.Delete(key) {
-		instance.Set(name, set)
+	session.notify(notifySortedSet, "zrem", name)
 
-		return rheltypes.Integer(1), nil
+	if set.Size() == 0 {
+		instance.Delete(name)
+		session.notify(notifyGeneric, "del", name)
+	} else {
+		instance.Update(name, set)
 	}
 
-	return rheltypes.Integer(0), nil
+	return rheltypes.Integer(removed), nil
 }
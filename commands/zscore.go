@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"fmt"
 	"log"
 
 	"github.com/codecrafters-io/redis-starter-go/rheltypes"
@@ -15,17 +14,28 @@ func NewCmdZScore() CmdZScore {
 	return CmdZScore{BaseCommand: BaseCommand("ZSCORE")}
 }
 
+func (c CmdZScore) Spec() ArgSpec {
+	return ArgSpec{
+		Arity:    3,
+		FirstKey: 1,
+		LastKey:  1,
+		KeyStep:  1,
+		Args:     []ArgKind{ArgKey, ArgString},
+	}
+}
+
 const (
 	posZScoreNameArg = 0
 	posZSCoreKeyArg  = 1
 )
 
 func (c CmdZScore) Exec(
+	session *Session,
 	args rheltypes.Array,
 ) (value rheltypes.RhelType, err error) {
 	name := args.At(posZScoreNameArg).String()
 	key := args.At(posZSCoreKeyArg).String()
-	item, found := GetDataMapInstance().Get(name)
+	item, found := session.DB().Get(name)
 
 	if !found {
 		log.Println("item nit found", name)
@@ -38,7 +48,7 @@ func (c CmdZScore) Exec(
 	var ok bool
 
 	if set, ok = item.(rheltypes.SortedSet); !ok {
-		return nil, c.ErrWrap(fmt.Errorf("expected sorted set, got %T", value))
+		return nil, c.ErrWrap(rheltypes.ErrWrongType)
 	}
 
 	member, found := set.Get(key)
@@ -49,5 +59,5 @@ func (c CmdZScore) Exec(
 		return rheltypes.NewNullBulkString(), nil
 	}
 
-	return member.AsBulkString(), nil
+	return rheltypes.Double(member.Score()), nil
 }
@@ -1,8 +1,10 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"sync"
 )
 
@@ -14,7 +16,8 @@ type ConnectionPool struct {
 	connections []net.Conn
 	mutex       sync.Mutex
 	ack         map[string]int
-	resend      int
+	resends     int
+	db          int
 }
 
 func NewConnectionPool() *ConnectionPool {
@@ -24,17 +27,16 @@ func NewConnectionPool() *ConnectionPool {
 	}
 }
 
-var (
-	pool     *ConnectionPool
-	poolOnce sync.Once
-)
-
 func (p *ConnectionPool) Add(conn net.Conn) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
 	p.connections = append(p.connections, conn)
 	p.ack[conn.RemoteAddr().String()] = 0
+
+	// The replica starts from a full resync, the next propagated command
+	// has to select its database whatever the others have selected.
+	p.db = -1
 }
 
 func (p *ConnectionPool) CloseAlls() {
@@ -45,7 +47,8 @@ func (p *ConnectionPool) CloseAlls() {
 		conn.Close()
 	}
 
-	p = NewConnectionPool()
+	p.connections = p.connections[:0]
+	p.ack = make(map[string]int, defaultConnectionCapacity)
 }
 
 func (p *ConnectionPool) ResetAck() {
@@ -58,30 +61,66 @@ func (p *ConnectionPool) Ack(addr string, ack int) {
 	p.ack[addr] = ack
 }
 
-func (p *ConnectionPool) Resend(cmd []byte, ack bool) error {
+// Resend propagates cmd to every replica, replicas that can't be written to
+// anymore are dropped from the pool and reported in the returned error.
+func (p *ConnectionPool) Resend(cmd []byte, ack bool) (err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.resend(cmd, ack)
+}
+
+// ResendInDb propagates cmd executed in the database db, selectCmd is sent
+// first when the replicas have another database selected.
+func (p *ConnectionPool) ResendInDb(db int, selectCmd, cmd []byte) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
+	if db != p.db {
+		cmd = slices.Concat(selectCmd, cmd)
+		p.db = db
+	}
+
+	return p.resend(cmd, false)
+}
+
+func (p *ConnectionPool) resend(cmd []byte, ack bool) (err error) {
+	alive := p.connections[:0]
+
 	for _, conn := range p.connections {
-		if _, err := conn.Write(cmd); err != nil {
-			return fmt.Errorf("error resending cmd %q: %w", cmd, err)
+		if _, writeErr := conn.Write(cmd); writeErr != nil {
+			err = errors.Join(err, fmt.Errorf(
+				"error resending cmd %q to %s: %w",
+				cmd,
+				conn.RemoteAddr(),
+				writeErr,
+			))
+
+			delete(p.ack, conn.RemoteAddr().String())
+			conn.Close()
+
+			continue
 		}
+
+		alive = append(alive, conn)
 	}
 
-	p.resend++
+	p.connections = alive
+
+	p.resends++
 
 	if ack {
 		p.ResetAck()
 	}
 
-	return nil
+	return err
 }
 
 func (p *ConnectionPool) NumResend() int {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	return p.resend
+	return p.resends
 }
 
 func (p *ConnectionPool) NumAcknowledged() int {
@@ -90,11 +129,3 @@ func (p *ConnectionPool) NumAcknowledged() int {
 
 	return len(p.ack)
 }
-
-func GetConnectionPool() *ConnectionPool {
-	poolOnce.Do(func() {
-		pool = NewConnectionPool()
-	})
-
-	return pool
-}
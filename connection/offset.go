@@ -12,6 +12,9 @@ func NewOffsetTracker() *OffsetTracker {
 }
 
 func (o *OffsetTracker) Add(b int) (current int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
 	current = o.bytes
 	o.bytes += b
 
@@ -19,18 +22,8 @@ func (o *OffsetTracker) Add(b int) (current int) {
 }
 
 func (o *OffsetTracker) Current() int {
-	return o.bytes
-}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
 
-var (
-	tracker     *OffsetTracker
-	trackerOnce sync.Once
-)
-
-func GetOffsetTracker() *OffsetTracker {
-	trackerOnce.Do(func() {
-		tracker = NewOffsetTracker()
-	})
-
-	return tracker
+	return o.bytes
 }
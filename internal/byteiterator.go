@@ -4,9 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -31,11 +35,11 @@ func NewByteIteratorFromFile(data *os.File) *ByteIterator {
 func (r *ByteIterator) readBytes(n int) ([]byte, error) {
 	buf := make([]byte, n)
 
-	b, err := r.buf.Read(buf)
-	if err != nil {
-		return nil, err
-	} else if b < n {
+	b, err := io.ReadFull(r.buf, buf)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
 		return nil, fmt.Errorf("expected to read %d bytes, got %d", n, b)
+	} else if err != nil {
+		return nil, err
 	}
 
 	r.Offset += b
@@ -50,6 +54,15 @@ func (r *ByteIterator) readByte() (b byte, err error) {
 	return
 }
 
+func (r *ByteIterator) peekByte() (b byte, err error) {
+	peeked, err := r.buf.Peek(1)
+	if err != nil {
+		return b, err
+	}
+
+	return peeked[0], nil
+}
+
 type RdbValueType int
 
 const (
@@ -58,7 +71,7 @@ const (
 	SetEncoding
 	SortedSetEncoding
 	HashEncoding
-	_
+	SortedSet2Encoding
 	_
 	_
 	_
@@ -97,6 +110,7 @@ type RdbSizeEncoding int
 const (
 	StringSizeEncoded RdbSizeEncoding = iota
 	IntegerSizeEncoded
+	LzfSizeEncoded
 )
 
 type RdbValueSize struct {
@@ -115,7 +129,8 @@ func (r *ByteIterator) readIntegerSize(b byte) (size RdbValueSize, err error) {
 	case indicatorSizeInt32Bit:
 		size.size = sizeInt32Bit
 	case indicatorSizeL2F:
-		err = fmt.Errorf("L2F size encoding not supported")
+		// The compressed and uncompressed sizes follow.
+		size.encoding = LzfSizeEncoded
 	default:
 		err = fmt.Errorf(
 			"unknown size encoding: %08b %X",
@@ -147,7 +162,7 @@ func (r *ByteIterator) readSize() (size RdbValueSize, err error) {
 				err,
 			)
 		} else {
-			size.size = int(binary.BigEndian.Uint16([]byte{b, sizeByte}))
+			size.size = int(binary.BigEndian.Uint16([]byte{sizeByte, b}))
 		}
 	case indicatorSize4Bytes:
 		if b, err := r.readBytes(sizeInt32Bit); err != nil {
@@ -176,22 +191,26 @@ func (v RdbStringValue) String() string {
 
 func (v RdbStringValue) isRbdValue() {}
 
+// newRdbIntegerValue decodes a string saved as an integer, which Redis
+// writes in little endian and signed.
 func newRdbIntegerValue(buf []byte) (value RdbStringValue, err error) {
+	var n int64
+
 	switch bufSize := len(buf); bufSize {
 	case sizeInt8Bit:
-		value = RdbStringValue(strconv.Itoa(int(buf[0])))
+		n = int64(int8(buf[0]))
 	case sizeInt16Bit:
-		value = RdbStringValue(strconv.Itoa(int(binary.BigEndian.Uint16(buf))))
+		n = int64(int16(binary.LittleEndian.Uint16(buf)))
 	case sizeInt32Bit:
-		value = RdbStringValue(strconv.Itoa(int(binary.BigEndian.Uint32(buf))))
+		n = int64(int32(binary.LittleEndian.Uint32(buf)))
 	default:
-		err = fmt.Errorf(
+		return value, fmt.Errorf(
 			"failed to create rdb integer string: byte size %d is incorrect",
 			bufSize,
 		)
 	}
 
-	return
+	return RdbStringValue(strconv.FormatInt(n, 10)), nil
 }
 
 func (r *ByteIterator) readStringValue() (value RdbStringValue, err error) {
@@ -200,6 +219,10 @@ func (r *ByteIterator) readStringValue() (value RdbStringValue, err error) {
 		return value, fmt.Errorf("failed to read value size: %w", err)
 	}
 
+	if valueSize.encoding == LzfSizeEncoded {
+		return r.readLzfStringValue()
+	}
+
 	buf, err := r.readBytes(valueSize.size)
 	if err != nil {
 		return value, fmt.Errorf("failed to read value bytes: %w", err)
@@ -215,6 +238,104 @@ func (r *ByteIterator) readStringValue() (value RdbStringValue, err error) {
 	return
 }
 
+func (r *ByteIterator) readLzfStringValue() (value RdbStringValue, err error) {
+	compressed, err := r.readSize()
+	if err != nil {
+		return value, fmt.Errorf("failed to read compressed size: %w", err)
+	}
+
+	size, err := r.readSize()
+	if err != nil {
+		return value, fmt.Errorf("failed to read uncompressed size: %w", err)
+	}
+
+	buf, err := r.readBytes(compressed.size)
+	if err != nil {
+		return value, fmt.Errorf("failed to read compressed bytes: %w", err)
+	}
+
+	buf, err = lzfDecompress(buf, size.size)
+	if err != nil {
+		return value, fmt.Errorf("failed to decompress value: %w", err)
+	}
+
+	return RdbStringValue(buf), nil
+}
+
+// RdbListValue holds the elements of a list, from its head.
+type RdbListValue []string
+
+func (v RdbListValue) String() string {
+	return strings.Join(v, ", ")
+}
+
+func (v RdbListValue) isRbdValue() {}
+
+// RdbSortedSetMember is a member of a sorted set along with its score.
+type RdbSortedSetMember struct {
+	Member string
+	Score  float64
+}
+
+// RdbSortedSetValue holds the members of a sorted set, in any order.
+type RdbSortedSetValue []RdbSortedSetMember
+
+func (v RdbSortedSetValue) String() string {
+	return fmt.Sprint([]RdbSortedSetMember(v))
+}
+
+func (v RdbSortedSetValue) isRbdValue() {}
+
+// readListValue reads the size of a list followed by its elements.
+func (r *ByteIterator) readListValue() (value RdbListValue, err error) {
+	size, err := r.readSize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read list size: %w", err)
+	}
+
+	for range size.size {
+		item, err := r.readStringValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read list element: %w", err)
+		}
+
+		value = append(value, string(item))
+	}
+
+	return value, nil
+}
+
+// readSortedSetValue reads the size of a sorted set followed by its
+// members, each one with its score as a little endian binary double.
+func (r *ByteIterator) readSortedSetValue() (
+	value RdbSortedSetValue,
+	err error,
+) {
+	size, err := r.readSize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sorted set size: %w", err)
+	}
+
+	for range size.size {
+		member, err := r.readStringValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sorted set member: %w", err)
+		}
+
+		score, err := r.readBytes(sizeInt64Bit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sorted set score: %w", err)
+		}
+
+		value = append(value, RdbSortedSetMember{
+			Member: string(member),
+			Score:  math.Float64frombits(binary.LittleEndian.Uint64(score)),
+		})
+	}
+
+	return value, nil
+}
+
 type RdbExpirationTime int64
 
 func (v RdbExpirationTime) String() string {
@@ -258,7 +379,9 @@ func newRdbExpirationTimeMili(
 }
 
 func (r *ByteIterator) readKeyValue() (key RdbStringValue, value RdbValue, err error) {
-	encodingByte, err := r.readByte()
+	// The byte closing a section is left for the caller, it tells whether
+	// another database follows.
+	encodingByte, err := r.peekByte()
 	if err != nil {
 		return key, value, fmt.Errorf("failed to read value encoding: %w", err)
 	}
@@ -268,6 +391,10 @@ func (r *ByteIterator) readKeyValue() (key RdbStringValue, value RdbValue, err e
 		return key, value, ErrEndOfSection
 	}
 
+	if _, err = r.readByte(); err != nil {
+		return key, value, err
+	}
+
 	switch encoding {
 	case StringEncoding, MetadataEncoding:
 		if key, err = r.readStringValue(); err != nil {
@@ -275,6 +402,18 @@ func (r *ByteIterator) readKeyValue() (key RdbStringValue, value RdbValue, err e
 		}
 
 		value, err = r.readStringValue()
+	case ListEncoding:
+		if key, err = r.readStringValue(); err != nil {
+			return key, value, fmt.Errorf("failed to read key: %w", err)
+		}
+
+		value, err = r.readListValue()
+	case SortedSet2Encoding:
+		if key, err = r.readStringValue(); err != nil {
+			return key, value, fmt.Errorf("failed to read key: %w", err)
+		}
+
+		value, err = r.readSortedSetValue()
 	case ExpirationMiliSectionEncoding:
 		value, err = newRdbExpirationTimeMili(r)
 	case ExpirationSectionEncoding:
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// lzfMaxRatio bounds how much LZF data expands: a back reference of 3
+	// bytes produces at most 264 of them.
+	lzfMaxRatio = 88
+
+	// lzfMaxSize is the longest string Redis holds, its proto-max-bulk-len.
+	lzfMaxSize = 512 << 20
+)
+
+var errCorruptLzf = errors.New("corrupt lzf data")
+
+// lzfDecompress expands the LZF data Redis saves long strings as, into the
+// size bytes it was compressed from. A size the data can't expand to is
+// rejected before anything is allocated.
+func lzfDecompress(in []byte, size int) ([]byte, error) {
+	if size < 0 || size > lzfMaxSize || size > len(in)*lzfMaxRatio {
+		return nil, fmt.Errorf(
+			"%w: %d bytes can't expand to %d",
+			errCorruptLzf,
+			len(in),
+			size,
+		)
+	}
+
+	out := make([]byte, 0, size)
+
+	for ip := 0; ip < len(in); {
+		ctrl := int(in[ip])
+		ip++
+
+		// A control byte below 32 starts a run of ctrl+1 literal bytes.
+		if ctrl < 1<<5 {
+			end := ip + ctrl + 1
+			if end > len(in) || len(out)+ctrl+1 > size {
+				return nil, errCorruptLzf
+			}
+
+			out = append(out, in[ip:end]...)
+			ip = end
+
+			continue
+		}
+
+		// Otherwise it is a back reference to bytes already expanded.
+		length := ctrl >> 5
+		if length == 7 {
+			if ip >= len(in) {
+				return nil, errCorruptLzf
+			}
+
+			length += int(in[ip])
+			ip++
+		}
+
+		if ip >= len(in) {
+			return nil, errCorruptLzf
+		}
+
+		ref := len(out) - (ctrl&0x1F)<<8 - int(in[ip]) - 1
+		ip++
+
+		if ref < 0 {
+			return nil, errCorruptLzf
+		}
+
+		if len(out)+length+2 > size {
+			return nil, errCorruptLzf
+		}
+
+		// References may overlap the bytes they produce.
+		for i := range length + 2 {
+			out = append(out, out[ref+i])
+		}
+	}
+
+	if len(out) != size {
+		return nil, fmt.Errorf(
+			"%w: expands to %d bytes instead of %d",
+			errCorruptLzf,
+			len(out),
+			size,
+		)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestLzfDecompress(t *testing.T) {
+	// far is 300 literal bytes in runs of 30, then a reference to the 3
+	// first of them, the offset taking the high bits of the control byte.
+	var far, farWant []byte
+
+	for i := range 10 {
+		run := bytes.Repeat([]byte{byte('a' + i)}, 30)
+		far = append(append(far, 29), run...)
+		farWant = append(farWant, run...)
+	}
+
+	far = append(far, 0x21, 0x2b)
+	farWant = append(farWant, "aaa"...)
+
+	tests := []struct {
+		name string
+		in   []byte
+		want []byte
+	}{
+		{"literal", []byte("\x04hello"), []byte("hello")},
+		{
+			"back reference",
+			[]byte("\x02abc\x20\x02\x00X"),
+			[]byte("abcabcX"),
+		},
+		{
+			"long back reference",
+			[]byte("\x05hello \xe0\x08\x05"),
+			[]byte("hello hello hello hello"),
+		},
+		{
+			"overlapping back reference",
+			[]byte("\x00a\xe0\x00\x00"),
+			[]byte("aaaaaaaaaa"),
+		},
+		{"far back reference", far, farWant},
+	}
+
+	for _, test := range tests {
+		got, err := lzfDecompress(test.in, len(test.want))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+
+			continue
+		}
+
+		if !bytes.Equal(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLzfDecompressCorrupt(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		size int
+	}{
+		{"reference at the start", []byte("\x20\x00"), 3},
+		{"reference before the start", []byte("\x00a\x20\x01"), 4},
+		{"far reference before the start", []byte("\x00a\x21\x00"), 4},
+		{"truncated literal", []byte("\x05ab"), 6},
+		{"truncated reference", []byte("\x00a\x20"), 4},
+		{"truncated long reference", []byte("\x00a\xe0"), 10},
+		{"truncated long reference offset", []byte("\x00a\xe0\x00"), 10},
+		{"size too short for a literal", []byte("\x04hello"), 4},
+		{"size too short for a reference", []byte("\x00a\xe0\x00\x00"), 9},
+		{"size too long", []byte("\x04hello"), 6},
+		{"size beyond any expansion", []byte("\x04hello"), 6*lzfMaxRatio + 1},
+		{"negative size", []byte("\x04hello"), -1},
+		{"size of empty data", nil, 1},
+	}
+
+	for _, test := range tests {
+		got, err := lzfDecompress(test.in, test.size)
+		if !errors.Is(err, errCorruptLzf) {
+			t.Errorf("%s: got %q and error %v, want errCorruptLzf",
+				test.name, got, err)
+		}
+	}
+
+	if got, err := lzfDecompress(nil, 0); err != nil || len(got) != 0 {
+		t.Errorf("empty data: got %q and error %v", got, err)
+	}
+}
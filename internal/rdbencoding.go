@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+const (
+	maxSize6Bit  = 1<<6 - 1
+	maxSize14Bit = 1<<14 - 1
+)
+
+// appendSize encodes size the way readSize decodes it, using the shortest
+// of the 6 bit, 14 bit and 4 bytes forms.
+func appendSize(buf []byte, size int) []byte {
+	switch {
+	case size <= maxSize6Bit:
+		return append(buf, byte(size))
+	case size <= maxSize14Bit:
+		return append(buf, byte(size>>8)|indicatorSize14Bit<<6, byte(size))
+	default:
+		buf = append(buf, indicatorSize4Bytes<<6)
+
+		return binary.BigEndian.AppendUint32(buf, uint32(size))
+	}
+}
+
+func appendString(buf []byte, str string) []byte {
+	buf = appendSize(buf, len(str))
+
+	return append(buf, str...)
+}
+
+func appendList(buf []byte, list []string) []byte {
+	buf = appendSize(buf, len(list))
+
+	for _, item := range list {
+		buf = appendString(buf, item)
+	}
+
+	return buf
+}
+
+func appendSortedSet(buf []byte, members []RdbSortedSetMember) []byte {
+	buf = appendSize(buf, len(members))
+
+	for _, member := range members {
+		buf = appendString(buf, member.Member)
+		buf = binary.LittleEndian.AppendUint64(
+			buf,
+			math.Float64bits(member.Score),
+		)
+	}
+
+	return buf
+}
+
+func appendExpiry(buf []byte, expiry int64) []byte {
+	buf = append(buf, ExpirationMiliSectionEncoding)
+
+	return binary.LittleEndian.AppendUint64(buf, uint64(expiry))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"iter"
 	"maps"
+	"slices"
 )
 
 type RdbHeader struct {
@@ -88,9 +89,14 @@ func readRdbMetadata(iter *ByteIterator) (meta RdbMetadata, err error) {
 
 var ErrEndOfSection = errors.New("end of database")
 
+// RdbKeyValue is the value of a key, Type tells which of Value, List or
+// SortedSet holds its content.
 type RdbKeyValue struct {
-	Expiry int64
-	Value  string
+	Expiry    int64
+	Type      RdbValueType
+	Value     string
+	List      []string
+	SortedSet []RdbSortedSetMember
 }
 
 func readRdbKeyValue(
@@ -106,10 +112,17 @@ func readRdbKeyValue(
 		case RdbExpirationTime:
 			value.Expiry = int64(v)
 		case RdbStringValue:
-			value.Value = rawValue.String()
-			key = rawKey.String()
+			value.Type, value.Value = StringEncoding, v.String()
 
-			return key, value, err
+			return rawKey.String(), value, err
+		case RdbListValue:
+			value.Type, value.List = ListEncoding, v
+
+			return rawKey.String(), value, err
+		case RdbSortedSetValue:
+			value.Type, value.SortedSet = SortedSet2Encoding, v
+
+			return rawKey.String(), value, err
 		}
 	}
 }
@@ -143,29 +156,23 @@ func readRdbFileValues(
 }
 
 type RdbFile struct {
-	header      RdbHeader
-	metadata    RdbMetadata
-	selector    int
-	hashSize    int
-	expKeysSize int
-	keyStore    RdbFileKeyStore
-	checksum    [defaultRdbFileChecksumLength]byte
+	header    RdbHeader
+	metadata  RdbMetadata
+	databases map[int]RdbFileKeyStore
+	checksum  [defaultRdbFileChecksumLength]byte
 }
 
 func NewRdbfFile() *RdbFile {
 	return &RdbFile{
-		header:      newRdbHeader(),
-		metadata:    newRdbMetadata(),
-		selector:    0,
-		hashSize:    0,
-		expKeysSize: 0,
-		keyStore:    newRdbKeyStore(),
-		checksum:    [defaultRdbFileChecksumLength]byte{},
+		header:    newRdbHeader(),
+		metadata:  newRdbMetadata(),
+		databases: make(map[int]RdbFileKeyStore),
+		checksum:  [defaultRdbFileChecksumLength]byte{},
 	}
 }
 
 func ReadRdbFile(iter *ByteIterator) (rdb *RdbFile, err error) {
-	rdb = &RdbFile{}
+	rdb = &RdbFile{databases: make(map[int]RdbFileKeyStore)}
 
 	if rdb.header, err = readRdbHeader(iter); err != nil {
 		return nil, fmt.Errorf("error reading rdb header: %w", err)
@@ -175,16 +182,28 @@ func ReadRdbFile(iter *ByteIterator) (rdb *RdbFile, err error) {
 		return nil, fmt.Errorf("error reading rdb metadata: %w", err)
 	}
 
-	if err = rdb.setSelector(iter); err != nil {
-		return nil, fmt.Errorf("error reading rdb selector: %w", err)
-	}
+	for {
+		markByte, err := iter.readByte()
+		if err != nil {
+			return nil, fmt.Errorf("error reading rdb section: %w", err)
+		}
 
-	if err = rdb.setSizes(iter); err != nil {
-		return nil, fmt.Errorf("error reading rdb size info: %w", err)
-	}
+		if markByte == EndOfFileEncoding {
+			break
+		}
 
-	if rdb.keyStore, err = readRdbFileValues(iter, rdb.hashSize); err != nil {
-		return nil, fmt.Errorf("error reading rdb key values: %w", err)
+		if markByte != DatabaseSectionEncoding {
+			return nil, fmt.Errorf(
+				"expected %X mark, got %08b %X",
+				DatabaseSectionEncoding,
+				markByte,
+				markByte,
+			)
+		}
+
+		if err = rdb.readDatabase(iter); err != nil {
+			return nil, err
+		}
 	}
 
 	if err = rdb.setChecksum(iter); err != nil {
@@ -194,8 +213,26 @@ func ReadRdbFile(iter *ByteIterator) (rdb *RdbFile, err error) {
 	return
 }
 
-func (f RdbFile) Iter() iter.Seq2[string, RdbKeyValue] {
-	return maps.All(f.keyStore)
+// Databases yields the key values of every database by increasing index.
+func (f RdbFile) Databases() iter.Seq2[int, RdbFileKeyStore] {
+	return func(yield func(int, RdbFileKeyStore) bool) {
+		for _, index := range slices.Sorted(maps.Keys(f.databases)) {
+			if !yield(index, f.databases[index]) {
+				return
+			}
+		}
+	}
+}
+
+// Set adds key to the database at index, it is written by WriteContent.
+func (f *RdbFile) Set(index int, key string, value RdbKeyValue) {
+	store, found := f.databases[index]
+	if !found {
+		store = newRdbKeyStore()
+		f.databases[index] = store
+	}
+
+	store[key] = value
 }
 
 func (f *RdbFile) WriteContent(writer *bufio.Writer) (err error) {
@@ -203,6 +240,12 @@ func (f *RdbFile) WriteContent(writer *bufio.Writer) (err error) {
 		return fmt.Errorf("error writing header: %w", headerErr)
 	}
 
+	for index, store := range f.Databases() {
+		if _, dbErr := writer.Write(encodeDatabase(index, store)); dbErr != nil {
+			return fmt.Errorf("error writing database %d: %w", index, dbErr)
+		}
+	}
+
 	if eofErr := writer.WriteByte(EndOfFileEncoding); eofErr != nil {
 		return fmt.Errorf("error writing enf of file: %w", eofErr)
 	}
@@ -214,25 +257,88 @@ func (f *RdbFile) WriteContent(writer *bufio.Writer) (err error) {
 	return
 }
 
-func (f *RdbFile) setSelector(iter *ByteIterator) (err error) {
-	size, err := iter.readSize()
+// encodeDatabase renders the section of a database: its selector, the
+// sizes and the key values, each one preceded by its expiry if any.
+func encodeDatabase(index int, store RdbFileKeyStore) (content []byte) {
+	expKeysSize := 0
+
+	for _, value := range store {
+		if value.Expiry > 0 {
+			expKeysSize++
+		}
+	}
+
+	content = append(content, DatabaseSectionEncoding)
+	content = appendSize(content, index)
+	content = append(content, SizesSectionEncoding)
+	content = appendSize(content, len(store))
+	content = appendSize(content, expKeysSize)
+
+	for _, key := range slices.Sorted(maps.Keys(store)) {
+		value := store[key]
+
+		if value.Expiry > 0 {
+			content = appendExpiry(content, value.Expiry)
+		}
+
+		content = append(content, byte(value.Type))
+		content = appendString(content, key)
+
+		switch value.Type {
+		case ListEncoding:
+			content = appendList(content, value.List)
+		case SortedSet2Encoding:
+			content = appendSortedSet(content, value.SortedSet)
+		default:
+			content = appendString(content, value.Value)
+		}
+	}
+
+	return
+}
+
+// readDatabase reads a section following its database selector mark, up to
+// the mark of the next section.
+func (f *RdbFile) readDatabase(iter *ByteIterator) (err error) {
+	index, err := readRdbSelector(iter)
 	if err != nil {
-		return err
+		return fmt.Errorf("error reading rdb selector: %w", err)
 	}
 
-	f.selector = int(size.size)
+	hashSize, err := readRdbSizes(iter)
+	if err != nil {
+		return fmt.Errorf("error reading rdb size info: %w", err)
+	}
+
+	store, err := readRdbFileValues(iter, hashSize)
+	if err != nil {
+		return fmt.Errorf("error reading rdb key values: %w", err)
+	}
+
+	f.databases[index] = store
 
 	return
 }
 
-func (f *RdbFile) setSizes(iter *ByteIterator) (err error) {
+func readRdbSelector(iter *ByteIterator) (index int, err error) {
+	size, err := iter.readSize()
+	if err != nil {
+		return 0, err
+	}
+
+	return size.size, nil
+}
+
+// readRdbSizes reads the sizes section and returns the number of keys, the
+// number of keys with an expiry is only a hint and is skipped.
+func readRdbSizes(iter *ByteIterator) (hashSize int, err error) {
 	markByte, err := iter.readByte()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if markByte != SizesSectionEncoding {
-		return fmt.Errorf(
+		return 0, fmt.Errorf(
 			"expected %X mark, got %08b %X",
 			SizesSectionEncoding,
 			markByte,
@@ -240,24 +346,26 @@ func (f *RdbFile) setSizes(iter *ByteIterator) (err error) {
 		)
 	}
 
+	var expKeysSize int
+
 	items := []struct {
 		ptr  *int
 		name string
 	}{
-		{ptr: &f.hashSize, name: "hash"},
-		{ptr: &f.expKeysSize, name: "exp keys"},
+		{ptr: &hashSize, name: "hash"},
+		{ptr: &expKeysSize, name: "exp keys"},
 	}
 
 	for _, field := range items {
 		size, err := iter.readSize()
 		if err != nil {
-			return fmt.Errorf("failed to read %s size: %w", field.name, err)
+			return 0, fmt.Errorf("failed to read %s size: %w", field.name, err)
 		}
 
-		*field.ptr = int(size.size)
+		*field.ptr = size.size
 	}
 
-	return err
+	return hashSize, err
 }
 
 func (f *RdbFile) setChecksum(iter *ByteIterator) (err error) {
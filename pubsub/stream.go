@@ -9,7 +9,12 @@ import (
 	"time"
 )
 
-const defaultStreamCapacity = 64
+const (
+	defaultStreamCapacity = 64
+	// defaultSubscriptionBuffer is the number of messages a subscriber can
+	// lag behind before it is dropped.
+	defaultSubscriptionBuffer = 1024
+)
 
 // Message represents a published message.
 //
@@ -22,6 +27,13 @@ type (
 	doneChannel chan struct{}
 )
 
+// PatternMessage is delivered to the subscribers of a pattern, along with
+// the channel the message was published to.
+type PatternMessage struct {
+	Channel string
+	Payload Message
+}
+
 // Subscription represents a single Subscription to a stream.
 type Subscription struct {
 	Id       int
@@ -29,25 +41,57 @@ type Subscription struct {
 	Done     doneChannel
 	once     sync.Once
 	unsub    chan int
+	mu       sync.Mutex // held while Messages is sent to or closed
+	closed   bool
 }
 
 func newSubscription(id int, unsub chan int) *Subscription {
 	return &Subscription{
 		Id:       id,
-		Messages: make(chan Message, 1),
+		Messages: make(chan Message, defaultSubscriptionBuffer),
 		Done:     make(doneChannel),
 		unsub:    unsub,
 	}
 }
 
+// newClosedSubscription is handed out once the manager is closed.
+func newClosedSubscription() (sub *Subscription) {
+	sub = newSubscription(0, make(chan int, 1))
+	sub.Close()
+
+	return sub
+}
+
 func (sub *Subscription) Close() {
 	sub.once.Do(func() {
+		sub.mu.Lock()
+		sub.closed = true
 		close(sub.Messages)
+		sub.mu.Unlock()
+
 		sub.unsub <- sub.Id
 		close(sub.Done)
 	})
 }
 
+// deliver hands msg to the subscriber without waiting, unless it is closed.
+// full reports a subscriber not keeping up with its messages.
+func (sub *Subscription) deliver(msg Message) (delivered, full bool) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.closed {
+		return false, false
+	}
+
+	select {
+	case sub.Messages <- msg:
+		return true, false
+	default:
+		return false, true
+	}
+}
+
 // // subscribeReq represents a subscription request.
 // type subscribeReq struct {
 // 	id string
@@ -58,6 +102,7 @@ func (sub *Subscription) Close() {
 type stream struct {
 	subscribers map[int]*Subscription
 	lastId      int
+	closing     bool
 	lock        sync.Mutex
 	msg         chan Message
 	sub         chan *Subscription
@@ -71,7 +116,7 @@ func newStream(quit chan struct{}, sendFirst bool) *stream {
 	return &stream{
 		subscribers: make(map[int]*Subscription, defaultStreamCapacity),
 		sub:         make(chan *Subscription, 1),
-		msg:         make(chan Message, 1),
+		msg:         make(chan Message, defaultStreamCapacity),
 		unsub:       make(chan int, 1),
 		done:        make(chan struct{}),
 		quit:        quit,
@@ -87,10 +132,16 @@ func (s *stream) delete(id int) {
 	// }
 }
 
+// subscribe returns nil once the stream is closing, a new one has to be
+// created then.
 func (s *stream) subscribe() *Subscription {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	if s.closing {
+		return nil
+	}
+
 	sub := newSubscription(s.lastId, s.unsub)
 	s.lastId++
 	s.subscribers[sub.Id] = sub
@@ -98,12 +149,19 @@ func (s *stream) subscribe() *Subscription {
 	return sub
 }
 
+func (s *stream) subscriber(id int) *Subscription {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.subscribers[id]
+}
+
 func (s *stream) iterSubscriptions() iter.Seq[*Subscription] {
 	if s.sendFirst {
 		return func(yield func(*Subscription) bool) {
 			// keys := slices.Collect()
-			for key := range slices.Sorted(maps.Keys(s.subscribers)) {
-				if !yield(s.subscribers[key]) {
+			for _, id := range slices.Sorted(maps.Keys(s.subscribers)) {
+				if !yield(s.subscribers[id]) {
 					return
 				}
 			}
@@ -117,53 +175,47 @@ func (s *stream) publishMsg(msg Message) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-loop:
 	for sub := range s.iterSubscriptions() {
-		select {
-		case sub.Messages <- msg:
-			if s.sendFirst {
-				break loop
-			}
-		case <-sub.Done:
-		default:
+		delivered, full := sub.deliver(msg)
+		if full {
 			sub.Close()
 		}
+
+		if delivered && s.sendFirst {
+			break
+		}
 	}
 }
 
+// clean closes every subscription, their ids are collected by the event
+// loop, which can't be waited on here.
 func (s *stream) clean() {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	var closed sync.WaitGroup
-
 	for _, sub := range s.subscribers {
-		closed.Add(1)
-
-		go func(s *Subscription) {
-			defer closed.Done()
-			s.Close() // Wait for stream completion
-		}(sub)
+		go sub.Close()
 	}
-
-	closed.Wait()
 }
 
+// close signals the end of the event loop. Its channels are left open, a
+// late Publish would panic otherwise.
 func (s *stream) close() {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	close(s.msg)
-	close(s.sub)
-	close(s.unsub)
 
 	close(s.done)
 }
 
+// shouldClose reports whether the last subscriber is gone, the stream
+// doesn't accept new ones from then on.
 func (s *stream) shouldClose() bool {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	return len(s.subscribers) == 0
+	s.closing = s.closing || len(s.subscribers) == 0
+
+	return s.closing
 }
 
 // run is the main event loop for a stream.
@@ -188,19 +240,35 @@ func (s *stream) run() {
 	}
 }
 
-// StreamManager is the main broker managing all streams.
+// StreamManager is the main broker managing all streams, the ones of
+// channels and the ones of patterns, which receive the messages of every
+// channel they match.
 type StreamManager struct {
-	streams map[string]*stream
-	quit    chan struct{}
-	mu      sync.RWMutex
+	streams  map[string]*stream
+	patterns map[string]*patternStream
+	quit     chan struct{}
+	mu       sync.RWMutex
 }
 
-// newStreamManager creates a new PubSub instance.
-func newStreamManager() *StreamManager {
-	return &StreamManager{
-		streams: make(map[string]*stream),
-		quit:    make(chan struct{}),
+type patternStream struct {
+	*stream
+	match func(channel string) bool
+}
+
+const defaultStreamCleanupInterval = 1 * time.Second
+
+// NewStreamManager creates a new PubSub instance, it runs until Close is
+// called.
+func NewStreamManager() (m *StreamManager) {
+	m = &StreamManager{
+		streams:  make(map[string]*stream),
+		patterns: make(map[string]*patternStream),
+		quit:     make(chan struct{}),
 	}
+
+	go m.run()
+
+	return m
 }
 
 // Subscribe creates a subscription to a stream.
@@ -211,17 +279,66 @@ func (m *StreamManager) Subscribe(
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	st, exists := m.streams[streamName]
-	if !exists {
-		st = newStream(m.quit, sendFirst)
-		m.streams[streamName] = st
+	if m.streams == nil {
+		return newClosedSubscription()
+	}
+
+	if st, exists := m.streams[streamName]; exists {
+		if sub := st.subscribe(); sub != nil {
+			return sub
+		}
+	}
+
+	st := newStream(m.quit, sendFirst)
+	m.streams[streamName] = st
 
-		go st.run()
+	go st.run()
+
+	return st.subscribe()
+}
+
+// PSubscribe creates a subscription to every channel matched by pattern,
+// its messages are PatternMessage values.
+func (m *StreamManager) PSubscribe(
+	pattern string,
+	match func(channel string) bool,
+) *Subscription {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.patterns == nil {
+		return newClosedSubscription()
 	}
 
+	if st, exists := m.patterns[pattern]; exists {
+		if sub := st.subscribe(); sub != nil {
+			return sub
+		}
+	}
+
+	st := &patternStream{stream: newStream(m.quit, false), match: match}
+	m.patterns[pattern] = st
+
+	go st.run()
+
 	return st.subscribe()
 }
 
+func (m *StreamManager) GetPatternSubscription(
+	pattern string,
+	subscriberId int,
+) *Subscription {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st, exists := m.patterns[pattern]
+	if !exists {
+		return nil
+	}
+
+	return st.subscriber(subscriberId)
+}
+
 func (m *StreamManager) GetSubscription(
 	streamName string,
 	subscriberId int,
@@ -235,46 +352,84 @@ func (m *StreamManager) GetSubscription(
 		return nil
 	}
 
-	return st.subscribers[subscriberId]
+	return st.subscriber(subscriberId)
 }
 
-// Publish sends a message to all subscribers of a stream.
+// Publish sends a message to all subscribers of a stream and of the
+// patterns matching it. Messages published by the same goroutine are
+// received in order.
 func (m *StreamManager) Publish(streamName string, msg any) {
 	m.mu.RLock()
 	st, exists := m.streams[streamName]
+	patterns := m.matchingPatterns(streamName)
 	m.mu.RUnlock()
 
-	if !exists {
-		return
+	if exists {
+		st.send(Message(msg))
+	}
+
+	for _, pattern := range patterns {
+		pattern.send(PatternMessage{Channel: streamName, Payload: msg})
+	}
+}
+
+// matchingPatterns returns the pattern streams matching channel, the lock
+// has to be held.
+func (m *StreamManager) matchingPatterns(channel string) []*patternStream {
+	var matching []*patternStream
+
+	for _, pattern := range m.patterns {
+		if pattern.match(channel) {
+			matching = append(matching, pattern)
+		}
 	}
 
+	return matching
+}
+
+// send hands msg to the event loop, unless the stream has ended meanwhile.
+func (s *stream) send(msg Message) {
 	select {
-	case st.msg <- Message(msg):
-	default:
+	case s.msg <- msg:
+	case <-s.done:
 	}
 }
 
-// Close closes all streams.
+// Close closes all streams, subscribing afterwards yields closed
+// subscriptions.
 func (m *StreamManager) Close() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.streams == nil {
+		return
+	}
+
 	close(m.quit)
 
 	var closed sync.WaitGroup
 
-	for _, s := range m.streams {
+	wait := func(s *stream) {
 		closed.Add(1)
 
-		go func(s *stream) {
+		go func() {
 			defer closed.Done()
 			<-s.done // Wait for stream completion
-		}(s)
+		}()
+	}
+
+	for _, s := range m.streams {
+		wait(s)
+	}
+
+	for _, s := range m.patterns {
+		wait(s.stream)
 	}
 
 	closed.Wait()
 
 	m.streams = nil
+	m.patterns = nil
 }
 
 func (m *StreamManager) Unsubscribe(
@@ -289,58 +444,86 @@ func (m *StreamManager) Unsubscribe(
 		return
 	}
 
-	st.subscribers[subscriptionId].Close()
+	if subscriber := st.subscribers[subscriptionId]; subscriber != nil {
+		subscriber.Close()
+	}
 }
 
-func (m *StreamManager) NumSubscribers(streamName string) int {
+func (m *StreamManager) PUnsubscribe(
+	pattern string,
+	subscriptionId int,
+) {
+	if subscriber := m.GetPatternSubscription(
+		pattern,
+		subscriptionId,
+	); subscriber != nil {
+		subscriber.Close()
+	}
+}
+
+// NumSubscribers counts the subscribers receiving the messages published
+// to streamName, including the ones of matching patterns.
+func (m *StreamManager) NumSubscribers(streamName string) (count int) {
 	m.mu.RLock()
-	st, exists := m.streams[streamName]
+	defer m.mu.RUnlock()
 
-	m.mu.RUnlock()
+	if st, exists := m.streams[streamName]; exists {
+		count += st.numSubscribers()
+	}
 
-	if !exists {
-		return 0
+	for _, pattern := range m.matchingPatterns(streamName) {
+		count += pattern.numSubscribers()
 	}
 
-	return len(st.subscribers)
+	return count
+}
+
+func (s *stream) numSubscribers() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.subscribers)
 }
 
+func (s *stream) ended() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// run drops the streams whose subscribers are all gone.
 func (m *StreamManager) run() {
+	ticker := time.NewTicker(defaultStreamCleanupInterval)
+	defer ticker.Stop()
+
 	for {
-		deletionList := make([]string, 0, len(m.streams))
+		select {
+		case <-m.quit:
+			return
+		case <-ticker.C:
+		}
+
+		m.mu.Lock()
 
 		for name, stream := range m.streams {
-			select {
-			case <-stream.done:
-				deletionList = append(deletionList, name)
-			default:
+			if stream.ended() {
+				delete(m.streams, name)
 			}
 		}
 
-		m.mu.Lock()
-
-		for _, id := range deletionList {
-			delete(m.streams, id)
+		for pattern, stream := range m.patterns {
+			if stream.ended() {
+				delete(m.patterns, pattern)
+			}
 		}
 
 		m.mu.Unlock()
 	}
 }
 
-var (
-	streamManager     *StreamManager
-	streamManagerOnce sync.Once
-)
-
-func GetStreamManager() *StreamManager {
-	streamManagerOnce.Do(func() {
-		streamManager = newStreamManager()
-		go streamManager.run()
-	})
-
-	return streamManager
-}
-
 func CreateContextFromTimeout(
 	timeoutMS int,
 ) (context.Context, context.CancelFunc) {
@@ -359,8 +542,10 @@ func CreateContextFromTimeout(
 	}
 }
 
-func ReadLast(key string, timeout int) (any, error) {
-	sub := GetStreamManager().Subscribe(key, true)
+// ReadLast waits up to timeout milliseconds for the next message published
+// to key, a nil message is returned on timeout or when the manager closes.
+func (m *StreamManager) ReadLast(key string, timeout int) (any, error) {
+	sub := m.Subscribe(key, true)
 	defer sub.Close()
 
 	ctx, cancel := CreateContextFromTimeout(timeout)
@@ -0,0 +1,81 @@
+package pubsub_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/pubsub"
+)
+
+func TestPublishSendFirstAfterUnsubscribe(t *testing.T) {
+	m := pubsub.NewStreamManager()
+	defer m.Close()
+
+	subs := make([]*pubsub.Subscription, 3)
+	for i := range subs {
+		subs[i] = m.Subscribe("list", true)
+	}
+
+	subs[0].Close()
+
+	for m.GetSubscription("list", subs[0].Id) != nil {
+		time.Sleep(time.Millisecond)
+	}
+
+	m.Publish("list", "wake")
+
+	select {
+	case msg := <-subs[1].Messages:
+		if msg != "wake" {
+			t.Fatalf("got message %v, want wake", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the first subscriber left got no message")
+	}
+
+	select {
+	case msg := <-subs[2].Messages:
+		t.Fatalf("got message %v on the second subscriber", msg)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestPublishWhileClosing(t *testing.T) {
+	m := pubsub.NewStreamManager()
+	defer m.Close()
+
+	keep := m.Subscribe("news", false)
+
+	for range 100 {
+		sub := m.Subscribe("news", false)
+
+		var wg sync.WaitGroup
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for range 10 {
+				m.Publish("news", "hello")
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			sub.Close()
+		}()
+
+		wg.Wait()
+	}
+
+	// Every message reaches the subscriber left.
+	for range 1000 {
+		select {
+		case <-keep.Messages:
+		case <-time.After(time.Second):
+			t.Fatal("messages are missing")
+		}
+	}
+}
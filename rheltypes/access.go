@@ -0,0 +1,64 @@
+package rheltypes
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// The LFU counter follows the one of Redis: it grows logarithmically with
+// the number of accesses, from lfuInitValue for a new key up to
+// lfuMaxValue, and is decremented once per lfuDecayTime without access.
+const (
+	lfuInitValue = 5
+	lfuMaxValue  = 255
+	lfuLogFactor = 10
+	lfuDecayTime = time.Minute
+)
+
+// accessInfo tracks when a key was last accessed and how often. It is
+// shared by the copies of an entry and updated atomically, so that reads
+// holding only the read lock can record their access.
+type accessInfo struct {
+	clock   atomic.Int64
+	counter atomic.Uint32
+}
+
+func newAccessInfo() *accessInfo {
+	info := &accessInfo{}
+	info.clock.Store(currentTime())
+	info.counter.Store(lfuInitValue)
+
+	return info
+}
+
+// touch records an access, concurrent ones may be counted only once which
+// is fine for an approximation.
+func (a *accessInfo) touch() {
+	now := currentTime()
+	counter := a.frequency(now)
+
+	if counter < lfuMaxValue {
+		base := float64(max(int(counter)-lfuInitValue, 0))
+		if rand.Float64() < 1/(base*lfuLogFactor+1) {
+			counter++
+		}
+	}
+
+	a.counter.Store(counter)
+	a.clock.Store(now)
+}
+
+// idle returns the milliseconds elapsed since the last access.
+func (a *accessInfo) idle(now int64) int64 {
+	return max(now-a.clock.Load(), 0)
+}
+
+// frequency returns the LFU counter, decremented for every lfuDecayTime
+// elapsed since the last access.
+func (a *accessInfo) frequency(now int64) uint32 {
+	periods := a.idle(now) / lfuDecayTime.Milliseconds()
+	counter := int64(a.counter.Load())
+
+	return uint32(max(counter-periods, 0))
+}
@@ -20,14 +20,28 @@ func NewArrayFromStrings(values []string) (a Array) {
 
 func NewArrayFromTokens(token Token, iter *TokenIterator) (a Array, err error) {
 	length, err := token.AsSize()
+	if err == nil && (length < -1 || length > maxMultibulkLength) {
+		err = errLengthOutOfRange
+	}
+
 	if err != nil {
-		return nil, fmt.Errorf("failed to init bul string: %w", err)
+		return nil, fmt.Errorf(
+			"%w %q: %w",
+			errInvalidMultibulkLength,
+			token.ToString(),
+			err,
+		)
+	}
+
+	// A null array, *-1, is decoded as a nil Array.
+	if length < 0 {
+		return nil, nil
 	}
 
-	a = make(Array, 0, length)
+	a = make(Array, 0, min(length, maxPrealloc))
 
 	for range length {
-		value, err := RhelEncode(iter)
+		value, err := rhelEncodeNested(iter)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create array: %w", err)
 		}
@@ -0,0 +1,77 @@
+package rheltypes
+
+import (
+	"fmt"
+	"math/big"
+)
+
+type BigNumber struct {
+	value *big.Int
+}
+
+func NewBigNumber(value *big.Int) BigNumber {
+	return BigNumber{value: new(big.Int).Set(value)}
+}
+
+func NewBigNumberFromTokens(token Token) (n BigNumber, err error) {
+	value, ok := new(big.Int).SetString(token.Data, 10)
+	if !ok {
+		return n, fmt.Errorf("failed to convert to big number %q", token)
+	}
+
+	return BigNumber{value: value}, nil
+}
+
+func (n BigNumber) Size() int {
+	return len(BigNumberPrefix) + len(n.String()) + len(rhelFieldDelim)
+}
+
+func (n BigNumber) Serialize() []byte {
+	buf := make([]byte, 0, n.Size())
+
+	return fmt.Appendf(buf, "%s%s\r\n", BigNumberPrefix, n.String())
+}
+
+func (n BigNumber) String() string {
+	if n.value == nil {
+		return "0"
+	}
+
+	return n.value.String()
+}
+
+func (n BigNumber) First() RhelType {
+	return n
+}
+
+func (n BigNumber) Integer() (int, error) {
+	if n.value == nil {
+		return 0, nil
+	}
+
+	if !n.value.IsInt64() {
+		return 0, fmt.Errorf("big number %s overflows integer", n)
+	}
+
+	return int(n.value.Int64()), nil
+}
+
+func (n BigNumber) TypeName() string {
+	return "bignumber"
+}
+
+func (n BigNumber) Float() (float64, error) {
+	if n.value == nil {
+		return 0, nil
+	}
+
+	f, _ := new(big.Float).SetInt(n.value).Float64()
+
+	return f, nil
+}
+
+func (n BigNumber) resp2() RhelType {
+	return NewBulkString(n.String())
+}
+
+func (n BigNumber) isRhelType() {}
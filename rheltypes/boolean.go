@@ -0,0 +1,70 @@
+package rheltypes
+
+import (
+	"fmt"
+)
+
+type Boolean bool
+
+func NewBooleanFromTokens(token Token) (b Boolean, err error) {
+	switch token.Data {
+	case "t":
+		return true, nil
+	case "f":
+		return false, nil
+	default:
+		return b, fmt.Errorf("failed to convert to boolean %q", token)
+	}
+}
+
+func (b Boolean) Size() int {
+	return len(BooleanPrefix) + 1 + len(rhelFieldDelim)
+}
+
+func (b Boolean) Serialize() []byte {
+	buf := make([]byte, 0, b.Size())
+
+	return fmt.Appendf(buf, "%s%s\r\n", BooleanPrefix, b.flag())
+}
+
+func (b Boolean) String() string {
+	return b.flag()
+}
+
+func (b Boolean) First() RhelType {
+	return b
+}
+
+func (b Boolean) Integer() (int, error) {
+	if b {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+func (b Boolean) TypeName() string {
+	return "boolean"
+}
+
+func (b Boolean) Float() (float64, error) {
+	i, _ := b.Integer()
+
+	return float64(i), nil
+}
+
+func (b Boolean) flag() string {
+	if b {
+		return "t"
+	}
+
+	return "f"
+}
+
+func (b Boolean) resp2() RhelType {
+	i, _ := b.Integer()
+
+	return Integer(i)
+}
+
+func (b Boolean) isRhelType() {}
@@ -0,0 +1,40 @@
+package rheltypes
+
+import (
+	"slices"
+)
+
+// CloneValue returns a copy of a keyspace value that can be changed
+// without affecting value, e.g. for COPY. Strings are never changed in
+// place and are shared.
+func CloneValue(value RhelType) RhelType {
+	switch v := value.(type) {
+	case *List:
+		return v.Clone()
+	case Stream:
+		// Items are never changed once added, only the slice is.
+		v.items = slices.Clone(v.items)
+
+		return v
+	case SortedSet:
+		return v.Clone()
+	default:
+		return value
+	}
+}
+
+func (s SortedSet) Clone() SortedSet {
+	clone := SortedSet{
+		names:   make(map[string]*SortedSetMember, len(s.names)),
+		members: make([]*SortedSetMember, len(s.members)),
+		bytes:   s.bytes,
+	}
+
+	for i, member := range s.members {
+		copied := *member
+		clone.members[i] = &copied
+		clone.names[copied.name] = &copied
+	}
+
+	return clone
+}
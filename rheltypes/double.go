@@ -0,0 +1,79 @@
+package rheltypes
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Double float64
+
+func ParseDouble(str string) (float64, error) {
+	switch strings.ToLower(str) {
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan":
+		return math.NaN(), nil
+	default:
+		return strconv.ParseFloat(str, 64)
+	}
+}
+
+func FormatDouble(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+func NewDoubleFromTokens(token Token) (d Double, err error) {
+	f, err := ParseDouble(token.Data)
+	if err != nil {
+		return d, fmt.Errorf("failed to convert to double %q: %w", token, err)
+	}
+
+	return Double(f), nil
+}
+
+func (d Double) Size() int {
+	return len(DoublePrefix) + len(d.String()) + len(rhelFieldDelim)
+}
+
+func (d Double) Serialize() []byte {
+	buf := make([]byte, 0, d.Size())
+
+	return fmt.Appendf(buf, "%s%s\r\n", DoublePrefix, d.String())
+}
+
+func (d Double) String() string {
+	return FormatDouble(float64(d))
+}
+
+func (d Double) First() RhelType {
+	return d
+}
+
+func (d Double) Integer() (int, error) {
+	return int(d), nil
+}
+
+func (d Double) TypeName() string {
+	return "double"
+}
+
+func (d Double) Float() (float64, error) { return float64(d), nil }
+
+func (d Double) resp2() RhelType {
+	return NewBulkString(d.String())
+}
+
+func (d Double) isRhelType() {}
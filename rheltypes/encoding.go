@@ -0,0 +1,62 @@
+package rheltypes
+
+import (
+	"strconv"
+)
+
+// Limits under which Redis keeps values in their compact encodings, with
+// the default configuration.
+const (
+	embstrMaxLength          = 44
+	listListpackMaxBytes     = 8 * 1024
+	sortedListpackMaxEntries = 128
+	sortedListpackMaxValue   = 64
+)
+
+// Encoding returns the name of the encoding Redis would use to hold value,
+// as reported by OBJECT ENCODING.
+func Encoding(value RhelType) string {
+	switch v := value.(type) {
+	case IntString:
+		return "int"
+	case BulkString:
+		return stringEncoding(v.Text)
+	case *List:
+		if v.bytes <= listListpackMaxBytes {
+			return "listpack"
+		}
+
+		return "quicklist"
+	case SortedSet:
+		if len(v.members) > sortedListpackMaxEntries {
+			return "skiplist"
+		}
+
+		for _, member := range v.members {
+			if len(member.name) > sortedListpackMaxValue {
+				return "skiplist"
+			}
+		}
+
+		return "listpack"
+	case Stream:
+		return "stream"
+	default:
+		return "raw"
+	}
+}
+
+// stringEncoding tells apart the strings holding a 64 bits integer in its
+// canonical form, the short strings and the other ones.
+func stringEncoding(text []byte) string {
+	n, err := strconv.ParseInt(string(text), 10, 64)
+	if err == nil && strconv.FormatInt(n, 10) == string(text) {
+		return "int"
+	}
+
+	if len(text) <= embstrMaxLength {
+		return "embstr"
+	}
+
+	return "raw"
+}
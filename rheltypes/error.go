@@ -1,13 +1,116 @@
 package rheltypes
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
+// ErrorType is the code prefixing an error reply, clients match on it the
+// same way they do against Redis.
 type ErrorType string
 
 const (
-	GenericErrorType = "ERR"
+	GenericErrorType     ErrorType = "ERR"
+	WrongTypeErrorType   ErrorType = "WRONGTYPE"
+	NoScriptErrorType    ErrorType = "NOSCRIPT"
+	ExecAbortErrorType   ErrorType = "EXECABORT"
+	NoAuthErrorType      ErrorType = "NOAUTH"
+	NoPermErrorType      ErrorType = "NOPERM"
+	WrongPassErrorType   ErrorType = "WRONGPASS"
+	ReadOnlyErrorType    ErrorType = "READONLY"
+	LoadingErrorType     ErrorType = "LOADING"
+	BusyErrorType        ErrorType = "BUSY"
+	BusyKeyErrorType     ErrorType = "BUSYKEY"
+	NotBusyErrorType     ErrorType = "NOTBUSY"
+	MovedErrorType       ErrorType = "MOVED"
+	AskErrorType         ErrorType = "ASK"
+	TryAgainErrorType    ErrorType = "TRYAGAIN"
+	CrossSlotErrorType   ErrorType = "CROSSSLOT"
+	ClusterDownErrorType ErrorType = "CLUSTERDOWN"
+	OomErrorType         ErrorType = "OOM"
+	NoProtoErrorType     ErrorType = "NOPROTO"
+	NoReplicasErrorType  ErrorType = "NOREPLICAS"
+	MasterDownErrorType  ErrorType = "MASTERDOWN"
+	MisconfErrorType     ErrorType = "MISCONF"
+	NoGroupErrorType     ErrorType = "NOGROUP"
+	BusyGroupErrorType   ErrorType = "BUSYGROUP"
+	InvalidObjErrorType  ErrorType = "INVALIDOBJ"
+)
+
+// Predefined replies, messages follow the ones sent by Redis.
+var (
+	ErrSyntax     = NewGenericError(errors.New("syntax error"))
+	ErrNotInteger = NewGenericError(
+		errors.New("value is not an integer or out of range"),
+	)
+	ErrNotFloat  = NewGenericError(errors.New("value is not a valid float"))
+	ErrNoSuchKey = NewGenericError(errors.New("no such key"))
+
+	ErrDbIndexOutOfRange = NewGenericError(
+		errors.New("DB index is out of range"),
+	)
+
+	ErrWrongType = NewError(
+		WrongTypeErrorType,
+		errors.New("Operation against a key holding the wrong kind of value"),
+	)
+	ErrNoScript = NewError(
+		NoScriptErrorType,
+		errors.New("No matching script. Please use EVAL."),
+	)
+	ErrExecAbort = NewError(
+		ExecAbortErrorType,
+		errors.New("Transaction discarded because of previous errors."),
+	)
+	ErrNoAuth = NewError(
+		NoAuthErrorType,
+		errors.New("Authentication required."),
+	)
+	ErrWrongPass = NewError(
+		WrongPassErrorType,
+		errors.New(
+			"invalid username-password pair or user is disabled.",
+		),
+	)
+	ErrReadOnly = NewError(
+		ReadOnlyErrorType,
+		errors.New("You can't write against a read only replica."),
+	)
+	ErrLoading = NewError(
+		LoadingErrorType,
+		errors.New("Redis is loading the dataset in memory"),
+	)
+	ErrBusy = NewError(
+		BusyErrorType,
+		errors.New(
+			"Redis is busy running a script. "+
+				"You can only call SCRIPT KILL or SHUTDOWN NOSAVE.",
+		),
+	)
+	ErrBusyKey = NewError(
+		BusyKeyErrorType,
+		errors.New("Target key name already exists."),
+	)
+	ErrOom = NewError(
+		OomErrorType,
+		errors.New("command not allowed when used memory > 'maxmemory'."),
+	)
+	ErrNoProto = NewError(
+		NoProtoErrorType,
+		errors.New("unsupported protocol version"),
+	)
+	ErrNoReplicas = NewError(
+		NoReplicasErrorType,
+		errors.New("Not enough good replicas to write."),
+	)
+	ErrMasterDown = NewError(
+		MasterDownErrorType,
+		errors.New(
+			"Link with MASTER is down and "+
+				"replica-serve-stale-data is set to 'no'.",
+		),
+	)
 )
 
 type Error struct {
@@ -15,13 +118,60 @@ type Error struct {
 	msg     string
 }
 
+func NewError(errType ErrorType, msg error) Error {
+	return Error{errType: errType, msg: msg.Error()}
+}
+
 func NewGenericError(msg error) Error {
-	return Error{errType: GenericErrorType, msg: msg.Error()}
+	return NewError(GenericErrorType, msg)
 }
 
-// func NewSimpleStringFromTokens(token Token) (SimpleString, error) {
-// 	return SimpleString(token.Data), nil
-// }
+func NewErrorf(errType ErrorType, format string, args ...any) Error {
+	return Error{errType: errType, msg: fmt.Sprintf(format, args...)}
+}
+
+func NewNoPermError(cmd string) Error {
+	return NewErrorf(
+		NoPermErrorType,
+		"this user has no permissions to run the '%s' command",
+		strings.ToLower(cmd),
+	)
+}
+
+// NewMovedError redirects a client to the node serving slot.
+func NewMovedError(slot int, addr string) Error {
+	return NewErrorf(MovedErrorType, "%d %s", slot, addr)
+}
+
+// NewAskError redirects a client to addr for the next query only.
+func NewAskError(slot int, addr string) Error {
+	return NewErrorf(AskErrorType, "%d %s", slot, addr)
+}
+
+// ErrorTypeOf returns the code of the reply wrapped in err, replies that are
+// not wrapped in err are reported with the generic code.
+func ErrorTypeOf(err error) ErrorType {
+	var reply Error
+	if errors.As(err, &reply) {
+		return reply.errType
+	}
+
+	return GenericErrorType
+}
+
+func NewErrorFromTokens(token Token) (Error, error) {
+	errType, msg, _ := strings.Cut(token.Data, " ")
+
+	return Error{errType: ErrorType(errType), msg: msg}, nil
+}
+
+func (e Error) Type() ErrorType {
+	return e.errType
+}
+
+func (e Error) Message() string {
+	return e.msg
+}
 
 func (e Error) Size() int {
 	return len(
@@ -54,6 +204,11 @@ func (e Error) String() string {
 	return string(e.Serialize())
 }
 
+// Error allows replies to be returned and wrapped as Go errors.
+func (e Error) Error() string {
+	return fmt.Sprintf("%s %s", e.errType, e.msg)
+}
+
 func (e Error) First() RhelType {
 	return e
 }
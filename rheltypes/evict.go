@@ -0,0 +1,159 @@
+package rheltypes
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+)
+
+// EvictionPolicy selects the keys removed once maxmemory is reached, they
+// follow the maxmemory-policy values of Redis.
+type EvictionPolicy int
+
+const (
+	NoEviction EvictionPolicy = iota
+	AllKeysLru
+	AllKeysLfu
+	AllKeysRandom
+	VolatileLru
+	VolatileLfu
+	VolatileRandom
+	VolatileTtl
+)
+
+var evictionPolicyNames = []string{
+	NoEviction:     "noeviction",
+	AllKeysLru:     "allkeys-lru",
+	AllKeysLfu:     "allkeys-lfu",
+	AllKeysRandom:  "allkeys-random",
+	VolatileLru:    "volatile-lru",
+	VolatileLfu:    "volatile-lfu",
+	VolatileRandom: "volatile-random",
+	VolatileTtl:    "volatile-ttl",
+}
+
+// ParseEvictionPolicy returns the policy called name, ignoring case.
+func ParseEvictionPolicy(name string) (EvictionPolicy, bool) {
+	for policy, policyName := range evictionPolicyNames {
+		if strings.EqualFold(name, policyName) {
+			return EvictionPolicy(policy), true
+		}
+	}
+
+	return NoEviction, false
+}
+
+func (p EvictionPolicy) String() string {
+	return evictionPolicyNames[p]
+}
+
+// Volatile reports whether the policy only evicts keys with an expiration.
+func (p EvictionPolicy) Volatile() bool {
+	return p >= VolatileLru
+}
+
+// LFU reports whether the policy evicts the least frequently used keys.
+func (p EvictionPolicy) LFU() bool {
+	return p == AllKeysLfu || p == VolatileLfu
+}
+
+// EvictionCandidate is a key sampled for eviction, the ones with the
+// highest Score are the best to evict.
+type EvictionCandidate struct {
+	Key   string
+	Score int64
+}
+
+// SampleEviction returns up to n random keys among the ones policy can
+// evict, scored like Redis does: by idle time for LRU, by lowest access
+// frequency for LFU and by nearest expiration for TTL. Volatile keys are
+// sampled one per shard, starting from a random one.
+func (sm *SafeMap) SampleEviction(
+	policy EvictionPolicy,
+	n int,
+) []EvictionCandidate {
+	now := currentTime()
+	candidates := make([]EvictionCandidate, 0, n)
+
+	add := func(key string, entry RhelMapValue) {
+		var score int64
+
+		switch policy {
+		case AllKeysLru, VolatileLru:
+			score = entry.access.idle(now)
+		case AllKeysLfu, VolatileLfu:
+			score = lfuMaxValue - int64(entry.access.frequency(now))
+		case VolatileTtl:
+			score = math.MaxInt64 - entry.Expiration
+		}
+
+		candidates = append(candidates, EvictionCandidate{key, score})
+	}
+
+	if policy.Volatile() {
+		start := rand.IntN(numShards)
+
+		for i := 0; i < numShards && len(candidates) < n; i++ {
+			s := &sm.shards[(start+i)%numShards]
+
+			s.mu.RLock()
+
+			if s.volatile.len() > 0 {
+				key := s.volatile.random()
+				entry, _ := s.get(key)
+				add(key, entry)
+			}
+
+			s.mu.RUnlock()
+		}
+
+		return candidates
+	}
+
+	if sm.Size() == 0 {
+		return candidates
+	}
+
+	start := rand.IntN(numBuckets)
+
+	for i := 0; i < numBuckets && len(candidates) < n; i++ {
+		index := (start + i) % numBuckets
+		s := &sm.shards[shardOf(index)]
+
+		s.mu.RLock()
+
+		for key, entry := range s.bucket(index) {
+			if len(candidates) == n {
+				break
+			}
+
+			add(key, entry)
+		}
+
+		s.mu.RUnlock()
+	}
+
+	return candidates
+}
+
+// Evict removes key to free memory, it reports whether it was removed. A
+// key that has expired meanwhile is removed as expired instead, one that
+// has lost its expiration is kept if volatile is set.
+func (sm *SafeMap) Evict(key string, volatile bool) bool {
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
+
+	entry, found := s.get(key)
+	if !found || sm.expire(s, key) || volatile && entry.Expiration == 0 {
+		return false
+	}
+
+	sm.remove(s, key)
+
+	return true
+}
+
+// UsedMemory estimates the bytes held by the keys and their values.
+func (sm *SafeMap) UsedMemory() int64 {
+	return sm.used.Load()
+}
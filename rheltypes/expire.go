@@ -0,0 +1,126 @@
+package rheltypes
+
+import (
+	"sync"
+	"time"
+)
+
+// The active expiry follows the one of Redis: random volatile keys are
+// sampled by batches, another batch is sampled as long as more than
+// activeExpireAcceptableStale percent of the last one had expired, within
+// activeExpireTimePerc percent of the cleanup interval.
+const (
+	activeExpireKeysPerLoop     = 20
+	activeExpireAcceptableStale = 10
+	activeExpireTimePerc        = 25
+	activeExpireStaleWeight     = 0.05
+)
+
+// ExpireStats reports the keys removed by the expiry of a SafeMap.
+type ExpireStats struct {
+	// ExpiredKeys counts the keys removed once expired, either by the
+	// active expiry or when accessed.
+	ExpiredKeys int
+	// StalePerc estimates the percentage of volatile keys that have expired
+	// but are still held.
+	StalePerc float64
+	// TimeCapReached counts the cycles stopped by their time budget while
+	// expired keys were still found.
+	TimeCapReached int
+	// VolatileKeys is the number of keys having an expiration.
+	VolatileKeys int
+}
+
+// expireCycleStats are updated by the active expiry and read by Stats, next
+// is the shard the next sample starts from and only used by the cycle.
+type expireCycleStats struct {
+	mu             sync.Mutex
+	stalePerc      float64
+	timeCapReached int
+	next           int
+}
+
+func (sm *SafeMap) Stats() ExpireStats {
+	stats := ExpireStats{ExpiredKeys: int(sm.expired.Load())}
+
+	for i := range sm.shards {
+		s := &sm.shards[i]
+
+		s.mu.RLock()
+		stats.VolatileKeys += s.volatile.len()
+		s.mu.RUnlock()
+	}
+
+	sm.stats.mu.Lock()
+	defer sm.stats.mu.Unlock()
+
+	stats.StalePerc = sm.stats.stalePerc
+	stats.TimeCapReached = sm.stats.timeCapReached
+
+	return stats
+}
+
+func (sm *SafeMap) activeExpireCycle() {
+	budget := sm.interval * activeExpireTimePerc / 100
+	start := time.Now()
+
+	var sampled, expired int
+
+	for {
+		batchSampled, batchExpired := sm.expireSample(activeExpireKeysPerLoop)
+
+		sampled += batchSampled
+		expired += batchExpired
+
+		if batchExpired*100 <= batchSampled*activeExpireAcceptableStale {
+			break
+		}
+
+		if time.Since(start) > budget {
+			sm.stats.mu.Lock()
+			sm.stats.timeCapReached++
+			sm.stats.mu.Unlock()
+
+			break
+		}
+	}
+
+	var currentPerc float64
+
+	if sampled > 0 {
+		currentPerc = float64(expired) * 100 / float64(sampled)
+	}
+
+	sm.stats.mu.Lock()
+	defer sm.stats.mu.Unlock()
+
+	sm.stats.stalePerc = currentPerc*activeExpireStaleWeight +
+		sm.stats.stalePerc*(1-activeExpireStaleWeight)
+}
+
+// expireSample checks up to n random volatile keys, taken one per shard in
+// turn. Each shard is only locked while its key is checked so that clients
+// are served in between.
+func (sm *SafeMap) expireSample(n int) (sampled, expired int) {
+	for visited := 0; sampled < n && visited < numShards; {
+		s := &sm.shards[sm.stats.next]
+		sm.stats.next = (sm.stats.next + 1) % numShards
+
+		s.mu.Lock()
+
+		if s.volatile.len() == 0 {
+			visited++
+		} else {
+			visited = 0
+			sampled++
+
+			if sm.expire(s, s.volatile.random()) {
+				expired++
+			}
+		}
+
+		s.mu.Unlock()
+	}
+
+	return sampled, expired
+}
@@ -0,0 +1,632 @@
+package rheltypes
+
+import (
+	"encoding/binary"
+	"errors"
+	"iter"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Parameters of the HyperLogLogs of Redis: 2^14 registers of 6 bits, each
+// one holding the longest run of zeros seen in the 50 remaining bits of
+// the hashes addressing it, plus one.
+const (
+	hllP           = 14
+	hllQ           = 64 - hllP
+	hllRegisters   = 1 << hllP
+	hllBits        = 6
+	hllRegisterMax = 1<<hllBits - 1
+	hllAlphaInf    = 0.721347520444481703680
+	hllHashSeed    = 0xadc83b19
+)
+
+// Layout of the HYLL strings: a header then the registers, either dense or
+// sparse. The header holds the magic, the encoding, 3 unused bytes and the
+// cached cardinality.
+const (
+	hllMagic      = "HYLL"
+	hllDense      = 0
+	hllSparse     = 1
+	hllHeaderSize = 16
+	hllDenseSize  = hllHeaderSize + (hllRegisters*hllBits+7)/8
+
+	// hllStaleCard is set in the last byte of the cached cardinality
+	// while it is stale.
+	hllStaleCard = 1 << 7
+)
+
+// Opcodes of the sparse encoding: ZERO is a run of 1 to 64 zero registers,
+// XZERO a run of up to 16384 and VAL a run of 1 to 4 registers holding
+// 1 to 32.
+const (
+	hllSparseXZeroBit    = 0x40
+	hllSparseValBit      = 0x80
+	hllSparseZeroMaxLen  = 64
+	hllSparseXZeroMaxLen = 16384
+	hllSparseValMaxValue = 32
+	hllSparseValMaxLen   = 4
+
+	// hllSparseMaxBytes is hll-sparse-max-bytes, the size of the strings
+	// past which the registers become dense.
+	hllSparseMaxBytes = 3000
+)
+
+var (
+	ErrNotHyperLogLog = NewError(
+		WrongTypeErrorType,
+		errors.New("Key is not a valid HyperLogLog string value."),
+	)
+	ErrCorruptHyperLogLog = NewError(
+		InvalidObjErrorType,
+		errors.New("Corrupted HLL object detected"),
+	)
+)
+
+// HyperLogLog estimates the number of distinct elements added to it, the
+// way Redis does. The keyspace holds it as a string in the HYLL format of
+// Redis, so that GET, SET and RDB files carry it unchanged:
+// ParseHyperLogLog reads such a string and String renders it back.
+//
+// The registers are sparse while most of them are zero, opcodes encoding
+// runs of registers holding the same value. They are changed the way Redis
+// changes them, so that both render the same bytes for the same commands.
+// They become dense, 6 bits each, once a register exceeds what the sparse
+// encoding holds or the string would grow past hllSparseMaxBytes.
+type HyperLogLog struct {
+	// card is the cached cardinality, in little endian.
+	card [8]byte
+	// sparse are the opcodes of the sparse registers, nil once dense.
+	sparse []byte
+	dense  []byte
+}
+
+// hllRun is a run of registers holding the same value.
+type hllRun struct {
+	value  byte
+	length int
+}
+
+// NewHyperLogLog returns an empty HyperLogLog with sparse registers, its
+// cached cardinality is stale like the one of a HyperLogLog Redis creates.
+func NewHyperLogLog() *HyperLogLog {
+	h := &HyperLogLog{sparse: appendZeroOpcode(nil, hllRegisters)}
+	h.card[7] |= hllStaleCard
+
+	return h
+}
+
+// ParseHyperLogLog reads a HYLL string, ErrNotHyperLogLog if it isn't one
+// and ErrCorruptHyperLogLog if its sparse registers don't add up.
+func ParseHyperLogLog(text string) (*HyperLogLog, error) {
+	if len(text) < hllHeaderSize || text[:len(hllMagic)] != hllMagic {
+		return nil, ErrNotHyperLogLog
+	}
+
+	h := &HyperLogLog{}
+	copy(h.card[:], text[8:hllHeaderSize])
+
+	switch text[4] {
+	case hllDense:
+		if len(text) != hllDenseSize {
+			return nil, ErrNotHyperLogLog
+		}
+
+		h.dense = []byte(text[hllHeaderSize:])
+	case hllSparse:
+		if _, err := sparseOpcodes(text[hllHeaderSize:]); err != nil {
+			return nil, err
+		}
+
+		h.sparse = []byte(text[hllHeaderSize:])
+	default:
+		return nil, ErrNotHyperLogLog
+	}
+
+	return h, nil
+}
+
+// Sparse tells whether the registers are sparse.
+func (h *HyperLogLog) Sparse() bool {
+	return h.dense == nil
+}
+
+// String renders the HYLL string of h.
+func (h *HyperLogLog) String() string {
+	var b strings.Builder
+
+	encoding := byte(hllDense)
+	if h.Sparse() {
+		encoding = hllSparse
+	}
+
+	b.WriteString(hllMagic)
+	b.Write([]byte{encoding, 0, 0, 0})
+	b.Write(h.card[:])
+
+	if h.Sparse() {
+		b.Write(h.sparse)
+	} else {
+		b.Write(h.dense)
+	}
+
+	return b.String()
+}
+
+// Add adds element to h and reports whether a register changed, in which
+// case the cached cardinality is stale.
+func (h *HyperLogLog) Add(element string) bool {
+	index, count := hllPatLen(element)
+
+	if !h.set(index, count) {
+		return false
+	}
+
+	h.card[7] |= hllStaleCard
+
+	return true
+}
+
+// Merge adds the elements of others to h, every register of h becoming
+// the greatest of all. Like PFMERGE, the registers of h become dense if
+// the ones of any of others are, then are raised in increasing order. The
+// cached cardinality is stale afterwards.
+func (h *HyperLogLog) Merge(others ...*HyperLogLog) {
+	h.card[7] |= hllStaleCard
+
+	registers := make([]byte, hllRegisters)
+
+	for _, other := range others {
+		if !other.Sparse() {
+			h.ToDense()
+		}
+
+		for index, value := range other.Registers() {
+			registers[index] = max(registers[index], value)
+		}
+	}
+
+	for index, value := range registers {
+		if value > 0 {
+			h.set(index, value)
+		}
+	}
+}
+
+// Stale tells whether the cached cardinality has to be computed again.
+func (h *HyperLogLog) Stale() bool {
+	return h.card[7]&hllStaleCard != 0
+}
+
+// Count returns the estimated cardinality of h, it is cached until h
+// changes.
+func (h *HyperLogLog) Count() uint64 {
+	if !h.Stale() {
+		return binary.LittleEndian.Uint64(h.card[:])
+	}
+
+	var histogram [hllRegisterMax + 1]int
+
+	if h.Sparse() {
+		for run := range h.runs() {
+			histogram[run.value] += run.length
+		}
+	} else {
+		for index := range hllRegisters {
+			histogram[denseRegister(h.dense, index)]++
+		}
+	}
+
+	card := hllEstimate(histogram[:])
+	binary.LittleEndian.PutUint64(h.card[:], card)
+
+	return card
+}
+
+// Registers returns the value of every register.
+func (h *HyperLogLog) Registers() []byte {
+	registers := make([]byte, 0, hllRegisters)
+
+	if h.Sparse() {
+		for run := range h.runs() {
+			for range run.length {
+				registers = append(registers, run.value)
+			}
+		}
+
+		return registers
+	}
+
+	for index := range hllRegisters {
+		registers = append(registers, denseRegister(h.dense, index))
+	}
+
+	return registers
+}
+
+// ToDense makes the registers dense and reports whether they were sparse.
+func (h *HyperLogLog) ToDense() bool {
+	if !h.Sparse() {
+		return false
+	}
+
+	dense := make([]byte, hllDenseSize-hllHeaderSize)
+	index := 0
+
+	for run := range h.runs() {
+		for range run.length {
+			setDenseRegister(dense, index, run.value)
+			index++
+		}
+	}
+
+	h.sparse, h.dense = nil, dense
+
+	return true
+}
+
+// Decode renders the opcodes of the sparse registers the way PFDEBUG
+// DECODE does, e.g. "Z:16300 v:2,1 z:20".
+func (h *HyperLogLog) Decode() string {
+	ops, _ := sparseOpcodes(string(h.sparse))
+	decoded := make([]string, len(ops))
+
+	for i, op := range ops {
+		length := strconv.Itoa(op.run.length)
+
+		switch op.kind {
+		case 'v':
+			decoded[i] = "v:" + strconv.Itoa(int(op.run.value)) + "," + length
+		default:
+			decoded[i] = string(op.kind) + ":" + length
+		}
+	}
+
+	return strings.Join(decoded, " ")
+}
+
+// runs yields the runs of the sparse registers, which are valid.
+func (h *HyperLogLog) runs() iter.Seq[hllRun] {
+	return func(yield func(hllRun) bool) {
+		for p := 0; p < len(h.sparse); {
+			run, size := sparseOpcode(h.sparse[p:])
+			if !yield(run) {
+				return
+			}
+
+			p += size
+		}
+	}
+}
+
+// set raises the register at index to value and reports whether it
+// changed.
+func (h *HyperLogLog) set(index int, value byte) bool {
+	if h.Sparse() && value > hllSparseValMaxValue {
+		h.ToDense()
+	}
+
+	if !h.Sparse() {
+		if value <= denseRegister(h.dense, index) {
+			return false
+		}
+
+		setDenseRegister(h.dense, index, value)
+
+		return true
+	}
+
+	return h.setSparse(index, value)
+}
+
+// setSparse raises a sparse register the way hllSparseSet of Redis does:
+// the opcode covering index is replaced by up to 3 opcodes, then the VAL
+// opcodes around it are merged when they hold the same value. The
+// registers become dense instead if the string would grow too large.
+func (h *HyperLogLog) setSparse(index int, value byte) bool {
+	p, prev, first := 0, -1, 0
+
+	run, size := sparseOpcode(h.sparse)
+	for first+run.length <= index {
+		prev, p, first = p, p+size, first+run.length
+		run, size = sparseOpcode(h.sparse[p:])
+	}
+
+	if run.value >= value && run.value > 0 {
+		return false
+	}
+
+	last := first + run.length - 1
+
+	var seq []byte
+
+	switch {
+	case run.length == 1 && (run.value > 0 || size == 1):
+		h.sparse[p] = valOpcode(value, 1)
+		h.mergeSparse(prev)
+
+		return true
+	case run.value > 0:
+		if index > first {
+			seq = append(seq, valOpcode(run.value, index-first))
+		}
+
+		seq = append(seq, valOpcode(value, 1))
+
+		if index < last {
+			seq = append(seq, valOpcode(run.value, last-index))
+		}
+	default:
+		if index > first {
+			seq = appendZeroOpcode(seq, index-first)
+		}
+
+		seq = append(seq, valOpcode(value, 1))
+
+		if index < last {
+			seq = appendZeroOpcode(seq, last-index)
+		}
+	}
+
+	grown := hllHeaderSize + len(h.sparse) + len(seq) - size
+	if len(seq) > size && grown > hllSparseMaxBytes {
+		h.ToDense()
+
+		return h.set(index, value)
+	}
+
+	h.sparse = slices.Replace(h.sparse, p, p+size, seq...)
+	h.mergeSparse(prev)
+
+	return true
+}
+
+// mergeSparse merges the adjacent VAL opcodes holding the same value, when
+// their lengths fit a single one, among the 5 opcodes from p on, the start
+// if p is negative.
+func (h *HyperLogLog) mergeSparse(p int) {
+	p = max(p, 0)
+
+	for scan := 5; p < len(h.sparse) && scan > 0; scan-- {
+		b := h.sparse[p]
+
+		switch {
+		case b&hllSparseValBit == 0 && b&hllSparseXZeroBit != 0:
+			p += 2
+
+			continue
+		case b&hllSparseValBit == 0:
+			p++
+
+			continue
+		}
+
+		if p+1 < len(h.sparse) && h.sparse[p+1]&hllSparseValBit != 0 {
+			run, _ := sparseOpcode(h.sparse[p:])
+			next, _ := sparseOpcode(h.sparse[p+1:])
+
+			length := run.length + next.length
+			if run.value == next.value && length <= hllSparseValMaxLen {
+				h.sparse[p+1] = valOpcode(run.value, length)
+				h.sparse = slices.Delete(h.sparse, p, p+1)
+
+				continue
+			}
+		}
+
+		p++
+	}
+}
+
+// hllOpcode is a decoded opcode of the sparse encoding, its kind is 'z'
+// for ZERO, 'Z' for XZERO and 'v' for VAL.
+type hllOpcode struct {
+	kind byte
+	run  hllRun
+}
+
+// sparseOpcodes decodes sparse registers, which have to add up to the
+// number of registers.
+func sparseOpcodes(data string) (ops []hllOpcode, err error) {
+	total := 0
+
+	for i := 0; i < len(data); i++ {
+		op := hllOpcode{}
+
+		switch b := data[i]; {
+		case b&hllSparseValBit != 0:
+			op.kind = 'v'
+			op.run = hllRun{value: b>>2&0x1f + 1, length: int(b&0x3) + 1}
+		case b&hllSparseXZeroBit != 0:
+			if i++; i == len(data) {
+				return nil, ErrCorruptHyperLogLog
+			}
+
+			op.kind = 'Z'
+			op.run.length = int(b&0x3f)<<8 | int(data[i]) + 1
+		default:
+			op.kind = 'z'
+			op.run.length = int(b&0x3f) + 1
+		}
+
+		if total += op.run.length; total > hllRegisters {
+			return nil, ErrCorruptHyperLogLog
+		}
+
+		ops = append(ops, op)
+	}
+
+	if total != hllRegisters {
+		return nil, ErrCorruptHyperLogLog
+	}
+
+	return ops, nil
+}
+
+// sparseOpcode decodes the opcode at the start of data, which has to be
+// valid, and returns its run along with its size.
+func sparseOpcode(data []byte) (run hllRun, size int) {
+	switch b := data[0]; {
+	case b&hllSparseValBit != 0:
+		return hllRun{value: b>>2&0x1f + 1, length: int(b&0x3) + 1}, 1
+	case b&hllSparseXZeroBit != 0:
+		return hllRun{length: int(b&0x3f)<<8 | int(data[1]) + 1}, 2
+	default:
+		return hllRun{length: int(b&0x3f) + 1}, 1
+	}
+}
+
+// valOpcode returns the VAL opcode of length registers holding value.
+func valOpcode(value byte, length int) byte {
+	return hllSparseValBit | (value-1)<<2 | byte(length-1)
+}
+
+// appendZeroOpcode appends the opcodes of length zero registers, a ZERO
+// one if it is short enough, XZERO ones otherwise.
+func appendZeroOpcode(data []byte, length int) []byte {
+	for length > hllSparseZeroMaxLen {
+		n := min(length, hllSparseXZeroMaxLen)
+		data = append(data, hllSparseXZeroBit|byte((n-1)>>8), byte(n-1))
+		length -= n
+	}
+
+	if length > 0 {
+		data = append(data, byte(length-1))
+	}
+
+	return data
+}
+
+// denseRegister returns the register at index of dense registers, packed
+// from the least significant bits of their bytes.
+func denseRegister(dense []byte, index int) byte {
+	offset := index * hllBits
+	b, shift := offset/8, offset%8
+
+	value := uint(dense[b]) >> shift
+	if b+1 < len(dense) {
+		value |= uint(dense[b+1]) << (8 - shift)
+	}
+
+	return byte(value & hllRegisterMax)
+}
+
+func setDenseRegister(dense []byte, index int, value byte) {
+	offset := index * hllBits
+	b, shift := offset/8, offset%8
+
+	dense[b] &^= hllRegisterMax << shift
+	dense[b] |= value << shift
+
+	if b+1 < len(dense) {
+		dense[b+1] &^= hllRegisterMax >> (8 - shift)
+		dense[b+1] |= value >> (8 - shift)
+	}
+}
+
+// hllPatLen returns the register addressed by the hash of element and the
+// length of the run of zeros of the rest of the hash, plus one.
+func hllPatLen(element string) (index int, count byte) {
+	hash := murmurHash64A(element, hllHashSeed)
+	index = int(hash & (hllRegisters - 1))
+	hash = hash>>hllP | 1<<hllQ
+
+	count = 1
+	for bit := uint64(1); hash&bit == 0; bit <<= 1 {
+		count++
+	}
+
+	return index, count
+}
+
+// hllEstimate returns the cardinality estimated from the histogram of the
+// registers, with the estimator of Otmar Ertl used by Redis.
+func hllEstimate(histogram []int) uint64 {
+	m := float64(hllRegisters)
+
+	z := m * hllTau((m-float64(histogram[hllQ+1]))/m)
+
+	for j := hllQ; j >= 1; j-- {
+		z += float64(histogram[j])
+		z *= 0.5
+	}
+
+	z += m * hllSigma(float64(histogram[0])/m)
+
+	return uint64(math.Round(hllAlphaInf * m * m / z))
+}
+
+func hllSigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+
+	y, z := 1.0, x
+
+	for {
+		x *= x
+		previous := z
+		z += x * y
+		y += y
+
+		if z == previous {
+			return z
+		}
+	}
+}
+
+func hllTau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+
+	y, z := 1.0, 1-x
+
+	for {
+		x = math.Sqrt(x)
+		previous := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+
+		if z == previous {
+			return z / 3
+		}
+	}
+}
+
+// murmurHash64A is the 64 bits MurmurHash2 of Austin Appleby, reading
+// blocks in little endian as Redis does.
+func murmurHash64A(data string, seed uint64) uint64 {
+	const (
+		m = 0xc6a4a7935bd1e995
+		r = 47
+	)
+
+	h := seed ^ uint64(len(data))*m
+
+	for ; len(data) >= 8; data = data[8:] {
+		k := binary.LittleEndian.Uint64([]byte(data[:8]))
+		k *= m
+		k ^= k >> r
+		k *= m
+
+		h ^= k
+		h *= m
+	}
+
+	if len(data) > 0 {
+		for i := len(data) - 1; i >= 0; i-- {
+			h ^= uint64(data[i]) << (8 * i)
+		}
+
+		h *= m
+	}
+
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+
+	return h
+}
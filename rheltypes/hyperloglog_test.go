@@ -0,0 +1,176 @@
+package rheltypes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// The golden strings come from hyperloglog.c of Redis 7, fed the same
+// elements.
+
+// addElements adds elem:0 to elem:n-1 to a new HyperLogLog.
+func addElements(n int) *HyperLogLog {
+	h := NewHyperLogLog()
+
+	for i := range n {
+		h.Add(fmt.Sprintf("elem:%d", i))
+	}
+
+	return h
+}
+
+func TestHyperLogLogGolden(t *testing.T) {
+	h := NewHyperLogLog()
+	for _, element := range []string{"a", "b", "c"} {
+		h.Add(element)
+	}
+
+	empty := "48594c4c010000000000000000000080" + "7fff"
+	got := hex.EncodeToString([]byte(NewHyperLogLog().String()))
+
+	if got != empty {
+		t.Errorf("empty HyperLogLog is %s, want %s", got, empty)
+	}
+
+	want := "48594c4c010000000000000000000080" + "60f38050b1844bfb80425a"
+	if got := hex.EncodeToString([]byte(h.String())); got != want {
+		t.Errorf("HyperLogLog of a, b and c is %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		elements int
+		sparse   bool
+		want     string
+	}{
+		{100, true, "ba8037d338810b508350c8c83fec9cef" +
+			"cd4810af1ca8db363cce643da01b9472"},
+		{5000, false, "f69f24de6ae684a675686e245a7f9859" +
+			"03165fb40ac8c7c405a2909d1ce84f39"},
+	}
+
+	for _, test := range tests {
+		h := addElements(test.elements)
+
+		if h.Sparse() != test.sparse {
+			t.Errorf("%d elements: sparse is %v, want %v",
+				test.elements, h.Sparse(), test.sparse)
+		}
+
+		sum := sha256.Sum256([]byte(h.String()))
+		if got := hex.EncodeToString(sum[:]); got != test.want {
+			t.Errorf("%d elements: SHA-256 is %s, want %s",
+				test.elements, got, test.want)
+		}
+	}
+}
+
+// TestHyperLogLogSparseMerge raises registers next to each other, the VAL
+// opcodes holding the same value being merged up to 4 registers.
+func TestHyperLogLogSparseMerge(t *testing.T) {
+	h := NewHyperLogLog()
+	for _, index := range []int{0, 2, 3, 4, 5, 1} {
+		h.set(index, 2)
+	}
+
+	if got, want := h.Decode(), "v:2,2 v:2,4 Z:16378"; got != want {
+		t.Errorf("opcodes are %q, want %q", got, want)
+	}
+
+	want := "48594c4c010000000000000000000080" + "85877ff9"
+	if got := hex.EncodeToString([]byte(h.String())); got != want {
+		t.Errorf("HyperLogLog is %s, want %s", got, want)
+	}
+}
+
+func TestHyperLogLogPromotion(t *testing.T) {
+	h := NewHyperLogLog()
+	h.set(10, hllSparseValMaxValue)
+
+	if !h.Sparse() {
+		t.Fatalf("register of %d made the registers dense",
+			hllSparseValMaxValue)
+	}
+
+	h.set(11, hllSparseValMaxValue+1)
+
+	if h.Sparse() {
+		t.Fatalf("register of %d kept the registers sparse",
+			hllSparseValMaxValue+1)
+	}
+
+	registers := h.Registers()
+	if registers[10] != hllSparseValMaxValue ||
+		registers[11] != hllSparseValMaxValue+1 {
+		t.Errorf("registers 10 and 11 are %d and %d after promotion",
+			registers[10], registers[11])
+	}
+
+	h = NewHyperLogLog()
+
+	for i := 0; h.Sparse(); i++ {
+		before := len(h.String())
+		h.Add(fmt.Sprintf("elem:%d", i))
+
+		if h.Sparse() && len(h.String()) > hllSparseMaxBytes {
+			t.Fatalf("sparse HyperLogLog grew to %d bytes",
+				len(h.String()))
+		}
+
+		if !h.Sparse() && before+3 <= hllSparseMaxBytes {
+			t.Fatalf("%d bytes long HyperLogLog became dense", before)
+		}
+	}
+}
+
+func TestHyperLogLogStaleCard(t *testing.T) {
+	h := addElements(1000)
+	if !h.Stale() {
+		t.Fatal("cached cardinality is valid after adding elements")
+	}
+
+	count := h.Count()
+	if h.Stale() || h.String()[15]&hllStaleCard != 0 {
+		t.Fatal("cached cardinality is stale after counting")
+	}
+
+	parsed, err := ParseHyperLogLog(h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Count() != count {
+		t.Errorf("cached cardinality is %d, want %d", parsed.Count(), count)
+	}
+
+	if parsed.Add("elem:0") || parsed.Stale() {
+		t.Error("adding a known element made the cardinality stale")
+	}
+
+	if !parsed.Add("new element") || !parsed.Stale() {
+		t.Error("adding a new element kept the cardinality valid")
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	sparse, dense := addElements(100), addElements(5000)
+
+	h := NewHyperLogLog()
+	h.Merge(sparse)
+
+	registers := h.String()[hllHeaderSize:]
+	if !h.Sparse() || registers != sparse.String()[hllHeaderSize:] {
+		t.Error("merging a sparse HyperLogLog into an empty one changed it")
+	}
+
+	h.Merge(dense)
+
+	if h.Sparse() {
+		t.Error("merging a dense HyperLogLog kept the registers sparse")
+	}
+
+	if h.String()[hllHeaderSize:] != dense.String()[hllHeaderSize:] {
+		t.Error("registers differ from the ones of the dense HyperLogLog")
+	}
+}
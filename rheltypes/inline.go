@@ -0,0 +1,158 @@
+package rheltypes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const maxInlineLength = 64 * 1024
+
+var (
+	errUnbalancedQuotes = errors.New("unbalanced quotes in request")
+	errInlineTooBig     = errors.New("too big inline request")
+)
+
+// isInline reports whether a request starts with something else than a RESP
+// prefix, e.g. a command typed into telnet or netcat.
+func isInline(data []byte) bool {
+	return len(data) > 0 && NewRhelPrefix(string(data[:1])) == UnknownPrefix
+}
+
+// readInlineLine returns the length of the first line terminated by "\n",
+// including the terminator, and the line without "\r\n" or "\n".
+func readInlineLine(data []byte) (n int, line []byte, err error) {
+	end := bytes.IndexByte(data, '\n')
+	if end == -1 {
+		if len(data) > maxInlineLength {
+			return 0, nil, errInlineTooBig
+		}
+
+		return 0, nil, ErrIncomplete
+	}
+
+	line = bytes.TrimSuffix(data[:end], []byte("\r"))
+
+	return end + 1, line, nil
+}
+
+func isInlineSpace(c byte) bool {
+	switch c {
+	case ' ', '\n', '\r', '\t', '\v', '\f':
+		return true
+	default:
+		return false
+	}
+}
+
+func isHexDigit(c byte) bool {
+	_, err := strconv.ParseUint(string(c), 16, 8)
+
+	return err == nil
+}
+
+func inlineEscape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'b':
+		return '\b'
+	case 'a':
+		return '\a'
+	default:
+		return c
+	}
+}
+
+// readInlineArg reads one argument starting at line[0], handling the double
+// and single quoting rules of redis-cli. Like sdssplitargs, a quote opens
+// anywhere in the argument but has to close at its end.
+func readInlineArg(line []byte) (arg []byte, n int, err error) {
+	arg = make([]byte, 0, len(line))
+
+	inDouble, inSingle := false, false
+
+	for n < len(line) {
+		c := line[n]
+
+		switch {
+		case inDouble:
+			switch {
+			case c == '\\' && n+3 < len(line) && line[n+1] == 'x' &&
+				isHexDigit(line[n+2]) && isHexDigit(line[n+3]):
+				b, _ := strconv.ParseUint(string(line[n+2:n+4]), 16, 8)
+				arg = append(arg, byte(b))
+				n += 3
+			case c == '\\' && n+1 < len(line):
+				n++
+				arg = append(arg, inlineEscape(line[n]))
+			case c == '"':
+				if n+1 < len(line) && !isInlineSpace(line[n+1]) {
+					return nil, n, errUnbalancedQuotes
+				}
+
+				return arg, n + 1, nil
+			default:
+				arg = append(arg, c)
+			}
+		case inSingle:
+			switch {
+			case c == '\\' && n+1 < len(line) && line[n+1] == '\'':
+				n++
+				arg = append(arg, '\'')
+			case c == '\'':
+				if n+1 < len(line) && !isInlineSpace(line[n+1]) {
+					return nil, n, errUnbalancedQuotes
+				}
+
+				return arg, n + 1, nil
+			default:
+				arg = append(arg, c)
+			}
+		case isInlineSpace(c):
+			return arg, n, nil
+		case c == '"':
+			inDouble = true
+		case c == '\'':
+			inSingle = true
+		default:
+			arg = append(arg, c)
+		}
+
+		n++
+	}
+
+	if inDouble || inSingle {
+		return nil, n, errUnbalancedQuotes
+	}
+
+	return arg, n, nil
+}
+
+// NewArrayFromInline splits an inline command into its arguments.
+func NewArrayFromInline(line []byte) (a Array, err error) {
+	a = make(Array, 0)
+
+	for i := 0; i < len(line); {
+		if isInlineSpace(line[i]) {
+			i++
+
+			continue
+		}
+
+		arg, n, err := readInlineArg(line[i:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to split inline command: %w", err)
+		}
+
+		a = append(a, NewBulkString(string(arg)))
+		i += n
+	}
+
+	return a, nil
+}
@@ -0,0 +1,101 @@
+package rheltypes
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// The expected arguments are the ones sdssplitargs of Redis splits the
+// lines into.
+
+func TestNewArrayFromInline(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+		err  error
+	}{
+		{"", []string{}, nil},
+		{"   \t ", []string{}, nil},
+		{"PING", []string{"PING"}, nil},
+		{"  SET  key\tvalue  ", []string{"SET", "key", "value"}, nil},
+		{`SET key "two words"`, []string{"SET", "key", "two words"}, nil},
+		{`SET key 'two words'`, []string{"SET", "key", "two words"}, nil},
+		{`SET "" ''`, []string{"SET", "", ""}, nil},
+		{`SET a"b c"`, []string{"SET", "ab c"}, nil},
+		{`SET a'b c'`, []string{"SET", "ab c"}, nil},
+		{`ECHO "a\nb\r\t\b\ac"`, []string{"ECHO", "a\nb\r\t\b\ac"}, nil},
+		{`ECHO "\x41\x7a\xff"`, []string{"ECHO", "Az\xff"}, nil},
+		{`ECHO "\x4"`, []string{"ECHO", "x4"}, nil},
+		{`ECHO "\"quoted\" \\ \q"`, []string{"ECHO", `"quoted" \ q`}, nil},
+		{`ECHO 'it\'s'`, []string{"ECHO", "it's"}, nil},
+		{`ECHO 'a\nb \\ c'`, []string{"ECHO", `a\nb \\ c`}, nil},
+		{`ECHO 'a\\'`, nil, errUnbalancedQuotes},
+		{`ECHO 'say "hi"'`, []string{"ECHO", `say "hi"`}, nil},
+		{`ECHO "say 'hi'"`, []string{"ECHO", "say 'hi'"}, nil},
+		{`ECHO a\nb`, []string{"ECHO", `a\nb`}, nil},
+		{`ECHO "unbalanced`, nil, errUnbalancedQuotes},
+		{`ECHO 'unbalanced`, nil, errUnbalancedQuotes},
+		{`ECHO "escaped end\"`, nil, errUnbalancedQuotes},
+		{`ECHO "closed"early`, nil, errUnbalancedQuotes},
+		{`ECHO 'closed'early`, nil, errUnbalancedQuotes},
+	}
+
+	for _, test := range tests {
+		a, err := NewArrayFromInline([]byte(test.line))
+
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: got error %v, want %v", test.line, err, test.err)
+
+			continue
+		}
+
+		got := make([]string, 0, len(a))
+		for _, arg := range a {
+			got = append(got, arg.String())
+		}
+
+		if test.err == nil && !slices.Equal(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.line, got, test.want)
+		}
+	}
+}
+
+func TestParserInline(t *testing.T) {
+	p := NewParserFromBytes([]byte("\r\n\n  \r\nPING\r\nECHO 'a b'\n"))
+
+	var got []string
+
+	for frame, err := range p.Frames() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got = append(got, frame.Value.String())
+	}
+
+	want := []string{
+		Array{NewBulkString("PING")}.String(),
+		Array{NewBulkString("ECHO"), NewBulkString("a b")}.String(),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if p.Buffered() != 0 {
+		t.Errorf("%d bytes left buffered", p.Buffered())
+	}
+
+	p = NewParserFromBytes([]byte("ECHO \"unbalanced\r\nPING\r\n"))
+
+	var protocolErr ProtocolError
+	if _, err := p.Next(); !errors.As(err, &protocolErr) ||
+		protocolErr.Reason != "unbalanced quotes in request" {
+		t.Errorf("got error %v, want unbalanced quotes", err)
+	}
+
+	if p.Buffered() != 0 {
+		t.Errorf("%d bytes left buffered after a protocol error",
+			p.Buffered())
+	}
+}
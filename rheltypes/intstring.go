@@ -0,0 +1,111 @@
+package rheltypes
+
+import (
+	"math"
+	"strconv"
+)
+
+// maxIntStringLength is the length of the longest 64 bits integer, with
+// its sign.
+const maxIntStringLength = 20
+
+// IntString is a string value holding a 64 bits integer in its canonical
+// form, the int encoding of Redis. The keyspace stores such strings this
+// way, so that counters are updated without parsing nor formatting text.
+// It is replied like the BulkString of its digits.
+type IntString int64
+
+// ParseIntString returns text as an IntString if it is the canonical form
+// of a 64 bits integer, e.g. "-12" but not "+12", "012" or " 12".
+func ParseIntString(text string) (IntString, bool) {
+	digits := text
+	if len(digits) > 0 && digits[0] == '-' {
+		digits = digits[1:]
+	}
+
+	switch {
+	case len(digits) == 0, len(text) > maxIntStringLength:
+		return 0, false
+	case digits[0] == '0' && len(text) > 1:
+		return 0, false
+	case digits[0] < '0' || digits[0] > '9':
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return IntString(n), true
+}
+
+// compactValue returns the strings that can be held as an IntString that
+// way, other values are returned unchanged.
+func compactValue(value RhelType) RhelType {
+	s, isString := value.(BulkString)
+	if !isString || s.IsNull() || len(s.Text) > maxIntStringLength {
+		return value
+	}
+
+	if n, ok := ParseIntString(string(s.Text)); ok {
+		return n
+	}
+
+	return value
+}
+
+// Add returns s increased by delta, ok is false if the result overflows.
+func (s IntString) Add(delta int64) (sum IntString, ok bool) {
+	if delta > 0 && int64(s) > math.MaxInt64-delta ||
+		delta < 0 && int64(s) < math.MinInt64-delta {
+		return s, false
+	}
+
+	return s + IntString(delta), true
+}
+
+func (s IntString) digits() []byte {
+	return strconv.AppendInt(make([]byte, 0, maxIntStringLength), int64(s), 10)
+}
+
+func (s IntString) Size() int {
+	length := len(s.digits())
+
+	return len(BulkStringPrefix) + len(strconv.Itoa(length)) +
+		len(rhelFieldDelim) + length + len(rhelFieldDelim)
+}
+
+func (s IntString) Serialize() []byte {
+	digits := s.digits()
+
+	buf := make([]byte, 0, s.Size())
+	buf = append(buf, BulkStringPrefix...)
+	buf = strconv.AppendInt(buf, int64(len(digits)), 10)
+	buf = append(buf, rhelFieldDelim...)
+	buf = append(buf, digits...)
+
+	return append(buf, rhelFieldDelim...)
+}
+
+func (s IntString) String() string {
+	return strconv.FormatInt(int64(s), 10)
+}
+
+func (s IntString) First() RhelType {
+	return s
+}
+
+func (s IntString) Integer() (int, error) {
+	return int(s), nil
+}
+
+func (s IntString) TypeName() string {
+	return "string"
+}
+
+func (s IntString) Float() (float64, error) {
+	return float64(s), nil
+}
+
+func (s IntString) isRhelType() {}
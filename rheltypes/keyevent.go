@@ -0,0 +1,37 @@
+package rheltypes
+
+// KeyEvent is a change of a key that no command asks for explicitly, it is
+// reported to the hook set by SafeMap.OnKeyEvent.
+type KeyEvent int
+
+const (
+	// KeyExpired is reported when an expired key is removed, either by the
+	// active expiry or when accessed.
+	KeyExpired KeyEvent = iota
+	// KeyAdded is reported when a key missing from the map is stored.
+	KeyAdded
+	// KeyMissed is reported when a key looked up for reading is missing.
+	KeyMissed
+)
+
+// KeyEventHook is called with the lock of the key's shard held, so it must
+// not use the map it is called by.
+type KeyEventHook func(event KeyEvent, key string)
+
+// OnKeyEvent sets the function the key events of the map are reported to,
+// nil stops reporting them.
+func (sm *SafeMap) OnKeyEvent(hook KeyEventHook) {
+	if hook == nil {
+		sm.hook.Store(nil)
+
+		return
+	}
+
+	sm.hook.Store(&hook)
+}
+
+func (sm *SafeMap) report(event KeyEvent, key string) {
+	if hook := sm.hook.Load(); hook != nil {
+		(*hook)(event, key)
+	}
+}
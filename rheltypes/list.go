@@ -0,0 +1,234 @@
+package rheltypes
+
+// listChunkSize is the number of elements held by each chunk of a list.
+const listChunkSize = 128
+
+type listChunk [listChunkSize]RhelType
+
+// List is a list value, a deque of elements split into fixed size chunks
+// like the quicklist of Redis. Pushing and popping at either end is O(1),
+// so is reaching an element by its index. The zero value is an empty
+// list.
+//
+// The elements sit at consecutive positions of the chunks, position p
+// being slot p%listChunkSize of chunk p/listChunkSize. Chunks are only
+// allocated while they hold elements, the free ones on both sides of the
+// used ones are nil.
+type List struct {
+	chunks []*listChunk
+	start  int // position of the first element
+	length int
+	bytes  int // length of the elements, for the encoding and memory
+}
+
+func NewList() *List {
+	return &List{}
+}
+
+func (l *List) Len() int {
+	return l.length
+}
+
+// PushFront inserts items at the head of the list one after the other, so
+// that the last item ends up first.
+func (l *List) PushFront(items ...RhelType) {
+	for _, item := range items {
+		if l.start == 0 {
+			l.grow()
+		}
+
+		l.start--
+		l.length++
+		l.put(l.start, item)
+	}
+}
+
+// PushBack appends items at the tail of the list.
+func (l *List) PushBack(items ...RhelType) {
+	for _, item := range items {
+		if l.start+l.length == len(l.chunks)*listChunkSize {
+			l.grow()
+		}
+
+		l.length++
+		l.put(l.start+l.length-1, item)
+	}
+}
+
+// PopFront removes and returns up to count elements from the head of the
+// list, in order.
+func (l *List) PopFront(count int) Array {
+	out := make(Array, min(count, l.length))
+
+	for i := range out {
+		out[i] = l.take(l.start)
+		l.start++
+		l.length--
+
+		if l.start%listChunkSize == 0 {
+			l.chunks[l.start/listChunkSize-1] = nil
+		}
+	}
+
+	l.reset()
+
+	return out
+}
+
+// PopBack removes and returns up to count elements from the tail of the
+// list, the last one first.
+func (l *List) PopBack(count int) Array {
+	out := make(Array, min(count, l.length))
+
+	for i := range out {
+		l.length--
+		end := l.start + l.length
+		out[i] = l.take(end)
+
+		if end%listChunkSize == 0 {
+			l.chunks[end/listChunkSize] = nil
+		}
+	}
+
+	l.reset()
+
+	return out
+}
+
+// At returns the element at index, negative indexes counting from the
+// tail, nil if it is out of range.
+func (l *List) At(index int) RhelType {
+	if index < 0 {
+		index += l.length
+	}
+
+	if index < 0 || index >= l.length {
+		return nil
+	}
+
+	return *l.slot(l.start + index)
+}
+
+// Range returns the elements from start to stop included, negative indexes
+// counting from the tail, the way LRANGE does.
+func (l *List) Range(start, stop int) Array {
+	if start < 0 {
+		start = max(start+l.length, 0)
+	}
+
+	if stop < 0 {
+		stop += l.length
+	}
+
+	stop = min(stop, l.length-1)
+
+	if start > stop {
+		return Array{}
+	}
+
+	out := make(Array, 0, stop-start+1)
+
+	for pos := l.start + start; pos <= l.start+stop; pos++ {
+		out = append(out, *l.slot(pos))
+	}
+
+	return out
+}
+
+func (l *List) Clone() *List {
+	clone := &List{
+		chunks: make([]*listChunk, len(l.chunks)),
+		start:  l.start,
+		length: l.length,
+		bytes:  l.bytes,
+	}
+
+	for i, chunk := range l.chunks {
+		if chunk != nil {
+			copied := *chunk
+			clone.chunks[i] = &copied
+		}
+	}
+
+	return clone
+}
+
+func (l *List) slot(pos int) *RhelType {
+	return &l.chunks[pos/listChunkSize][pos%listChunkSize]
+}
+
+func (l *List) put(pos int, item RhelType) {
+	if l.chunks[pos/listChunkSize] == nil {
+		l.chunks[pos/listChunkSize] = new(listChunk)
+	}
+
+	*l.slot(pos) = item
+	l.bytes += elementLength(item)
+}
+
+// take clears the slot at pos and returns its element.
+func (l *List) take(pos int) RhelType {
+	slot := l.slot(pos)
+	item := *slot
+	*slot = nil
+	l.bytes -= elementLength(item)
+
+	return item
+}
+
+// grow moves the used chunks to the middle of a larger table of chunks,
+// leaving room on both sides for as many chunks as are used, at least one.
+func (l *List) grow() {
+	first := l.start / listChunkSize
+	last := (l.start + l.length + listChunkSize - 1) / listChunkSize
+	used := last - first
+	room := max(used, 1)
+
+	chunks := make([]*listChunk, used+2*room)
+	copy(chunks[room:], l.chunks[first:last])
+
+	l.chunks = chunks
+	l.start += (room - first) * listChunkSize
+}
+
+// reset drops the chunks of an emptied list.
+func (l *List) reset() {
+	if l.length == 0 {
+		*l = List{}
+	}
+}
+
+// elementLength returns the length of an element without copying it.
+func elementLength(item RhelType) int {
+	if s, isString := item.(BulkString); isString {
+		return len(s.Text)
+	}
+
+	return len(item.String())
+}
+
+func (l *List) Size() int {
+	return l.Range(0, -1).Size()
+}
+
+func (l *List) Serialize() []byte {
+	return l.Range(0, -1).Serialize()
+}
+
+func (l *List) String() string {
+	return l.Range(0, -1).String()
+}
+
+func (l *List) First() RhelType {
+	return l.At(0)
+}
+
+func (l *List) Integer() (int, error) { return 0, nil }
+
+func (l *List) TypeName() string {
+	return "list"
+}
+
+func (l *List) Float() (float64, error) { return 0, nil }
+
+func (l *List) isRhelType() {}
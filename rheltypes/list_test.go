@@ -0,0 +1,231 @@
+package rheltypes
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// listItems returns the elements of l as strings, from the head.
+func listItems(l *List) []string {
+	items := make([]string, 0, l.Len())
+
+	for _, item := range l.Range(0, -1) {
+		items = append(items, item.String())
+	}
+
+	return items
+}
+
+func bulkStrings(items ...string) []RhelType {
+	out := make([]RhelType, len(items))
+
+	for i, item := range items {
+		out[i] = NewBulkString(item)
+	}
+
+	return out
+}
+
+// numbered returns the strings prefix0 to prefix<n-1>.
+func numbered(prefix string, n int) []string {
+	out := make([]string, n)
+
+	for i := range out {
+		out[i] = fmt.Sprint(prefix, i)
+	}
+
+	return out
+}
+
+// checkList compares l with the elements it should hold and the bytes it
+// should account for.
+func checkList(t *testing.T, l *List, want []string) {
+	t.Helper()
+
+	if got := listItems(l); !slices.Equal(got, want) {
+		t.Fatalf("got %d elements %q, want %d %q", len(got), got,
+			len(want), want)
+	}
+
+	if l.Len() != len(want) {
+		t.Fatalf("got length %d, want %d", l.Len(), len(want))
+	}
+
+	bytes := 0
+	for _, item := range want {
+		bytes += len(item)
+	}
+
+	if l.bytes != bytes {
+		t.Fatalf("got %d bytes, want %d", l.bytes, bytes)
+	}
+}
+
+func TestListPushPopAcrossChunks(t *testing.T) {
+	sizes := []int{
+		1, listChunkSize - 1, listChunkSize, listChunkSize + 1,
+		3*listChunkSize + 7,
+	}
+
+	for _, n := range sizes {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			l := NewList()
+			var want []string
+
+			// Grow on both sides, the front one first so that the head
+			// crosses chunk edges backwards.
+			for _, item := range numbered("f", n) {
+				l.PushFront(NewBulkString(item))
+				want = slices.Insert(want, 0, item)
+			}
+
+			for _, item := range numbered("b", n) {
+				l.PushBack(NewBulkString(item))
+				want = append(want, item)
+			}
+
+			checkList(t, l, want)
+
+			for len(want) > 0 {
+				front := l.PopFront(1)
+				if front[0].String() != want[0] {
+					t.Fatalf("popped %q from the front, want %q", front[0],
+						want[0])
+				}
+
+				want = want[1:]
+
+				if len(want) == 0 {
+					break
+				}
+
+				back := l.PopBack(1)
+				if back[0].String() != want[len(want)-1] {
+					t.Fatalf("popped %q from the back, want %q", back[0],
+						want[len(want)-1])
+				}
+
+				want = want[:len(want)-1]
+				checkList(t, l, want)
+			}
+
+			checkList(t, l, nil)
+
+			if l.chunks != nil {
+				t.Fatalf("kept %d chunks once empty", len(l.chunks))
+			}
+		})
+	}
+}
+
+func TestListPopCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		front bool
+		count int
+		want  []string
+		left  []string
+	}{
+		{"front none", true, 0, []string{}, numbered("e", 5)},
+		{"front some", true, 3, numbered("e", 3), []string{"e3", "e4"}},
+		{"front all", true, 5, numbered("e", 5), nil},
+		{"front more", true, 10, numbered("e", 5), nil},
+		{"back some", false, 2, []string{"e4", "e3"}, numbered("e", 3)},
+		{"back more", false, 10, []string{"e4", "e3", "e2", "e1", "e0"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewList()
+			l.PushBack(bulkStrings(numbered("e", 5)...)...)
+
+			var popped Array
+			if test.front {
+				popped = l.PopFront(test.count)
+			} else {
+				popped = l.PopBack(test.count)
+			}
+
+			got := make([]string, len(popped))
+			for i, item := range popped {
+				got[i] = item.String()
+			}
+
+			if !slices.Equal(got, test.want) {
+				t.Errorf("popped %q, want %q", got, test.want)
+			}
+
+			checkList(t, l, test.left)
+		})
+	}
+}
+
+func TestListRangeAndAt(t *testing.T) {
+	l := NewList()
+	l.PushBack(bulkStrings(numbered("e", 10)...)...)
+
+	ranges := []struct {
+		start, stop int
+		want        []string
+	}{
+		{0, -1, numbered("e", 10)},
+		{0, 2, []string{"e0", "e1", "e2"}},
+		{-3, -1, []string{"e7", "e8", "e9"}},
+		{-1, -1, []string{"e9"}},
+		{-100, 1, []string{"e0", "e1"}},
+		{8, 100, []string{"e8", "e9"}},
+		{5, 2, []string{}},
+		{10, 20, []string{}},
+		{0, -11, []string{}},
+		{-100, -50, []string{}},
+	}
+
+	for _, r := range ranges {
+		got := make([]string, 0)
+		for _, item := range l.Range(r.start, r.stop) {
+			got = append(got, item.String())
+		}
+
+		if !slices.Equal(got, r.want) {
+			t.Errorf("Range(%d, %d) = %q, want %q", r.start, r.stop, got,
+				r.want)
+		}
+	}
+
+	at := []struct {
+		index int
+		want  string
+	}{
+		{0, "e0"}, {9, "e9"}, {-1, "e9"}, {-10, "e0"}, {10, ""}, {-11, ""},
+	}
+
+	for _, a := range at {
+		got := l.At(a.index)
+
+		switch {
+		case a.want == "" && got != nil:
+			t.Errorf("At(%d) = %q, want nil", a.index, got)
+		case a.want != "" && (got == nil || got.String() != a.want):
+			t.Errorf("At(%d) = %v, want %q", a.index, got, a.want)
+		}
+	}
+}
+
+func TestListCloneIsIndependent(t *testing.T) {
+	l := NewList()
+	l.PushBack(bulkStrings(numbered("e", 2*listChunkSize)...)...)
+
+	clone := l.Clone()
+
+	l.PopFront(listChunkSize + 1)
+	l.PushBack(NewBulkString("added"))
+	clone.PopBack(1)
+	clone.PushFront(NewBulkString("first"))
+
+	want := append([]string{"first"}, numbered("e", 2*listChunkSize-1)...)
+	checkList(t, clone, want)
+
+	want = append(numbered("e", 2*listChunkSize)[listChunkSize+1:], "added")
+	checkList(t, l, want)
+}
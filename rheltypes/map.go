@@ -0,0 +1,124 @@
+package rheltypes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const mapEntrySize = 2
+
+type MapEntry struct {
+	Key   RhelType
+	Value RhelType
+}
+
+// Map is an ordered RESP3 map, replies keep the order of insertion.
+type Map []MapEntry
+
+func NewMapFromStrings(values ...string) (m Map) {
+	m = make(Map, 0, len(values)/mapEntrySize)
+
+	for i := 0; i+1 < len(values); i += mapEntrySize {
+		m = append(m, MapEntry{
+			Key:   NewBulkString(values[i]),
+			Value: NewBulkString(values[i+1]),
+		})
+	}
+
+	return
+}
+
+func NewMapFromTokens(token Token, iter *TokenIterator) (m Map, err error) {
+	length, err := token.AsSize()
+	if err == nil && (length < 0 || length > maxMultibulkLength) {
+		err = errLengthOutOfRange
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w %q: %w",
+			errInvalidMultibulkLength,
+			token.ToString(),
+			err,
+		)
+	}
+
+	m = make(Map, 0, min(length, maxPrealloc))
+
+	for range length {
+		var entry MapEntry
+
+		if entry.Key, err = rhelEncodeNested(iter); err != nil {
+			return nil, fmt.Errorf("failed to read map key: %w", err)
+		}
+
+		if entry.Value, err = rhelEncodeNested(iter); err != nil {
+			return nil, fmt.Errorf("failed to read map value: %w", err)
+		}
+
+		m = append(m, entry)
+	}
+
+	return
+}
+
+func (m *Map) Add(key string, value RhelType) {
+	*m = append(*m, MapEntry{Key: NewBulkString(key), Value: value})
+}
+
+func (m Map) Get(key string) (value RhelType, found bool) {
+	for _, entry := range m {
+		if entry.Key.String() == key {
+			return entry.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (m Map) Size() int {
+	return len(m.Serialize())
+}
+
+func (m Map) Serialize() []byte {
+	buf := make([]byte, 0, defaultIteratorBufferSize)
+
+	buf = append(buf, MapPrefix...)
+	buf = append(buf, strconv.Itoa(len(m))...)
+	buf = append(buf, rhelFieldDelim...)
+
+	for _, entry := range m {
+		buf = append(buf, entry.Key.Serialize()...)
+		buf = append(buf, entry.Value.Serialize()...)
+	}
+
+	return buf
+}
+
+func (m Map) String() string {
+	buf := make([]string, 0, len(m))
+	for _, entry := range m {
+		buf = append(buf, entry.Key.String()+": "+entry.Value.String())
+	}
+
+	return strings.Join(buf, ", ")
+}
+
+func (m Map) First() RhelType {
+	if len(m) == 0 {
+		return nil
+	}
+
+	return m[0].Key
+}
+
+func (m Map) Integer() (int, error) { return 0, nil }
+
+func (m Map) TypeName() string {
+	return "map"
+}
+
+func (m Map) Float() (float64, error) { return 0, nil }
+
+func (m Map) isRhelType() {}
@@ -0,0 +1,37 @@
+package rheltypes
+
+// Approximate overheads in bytes of the structures holding the keyspace,
+// they only need to be close enough to compare the total to maxmemory.
+const (
+	entryOverhead        = 96
+	valueOverhead        = 16
+	elementOverhead      = 24
+	streamItemOverhead   = 64
+	sortedMemberOverhead = 64
+)
+
+// MemoryUsage estimates the bytes held by a value of the keyspace.
+func MemoryUsage(value RhelType) int {
+	switch v := value.(type) {
+	case IntString:
+		return valueOverhead
+	case BulkString:
+		return valueOverhead + len(v.Text)
+	case *List:
+		return valueOverhead + v.bytes +
+			v.length*(elementOverhead+valueOverhead)
+	case Stream:
+		return valueOverhead + v.bytes + len(v.items)*streamItemOverhead +
+			v.fields*2*elementOverhead
+	case SortedSet:
+		return valueOverhead + v.bytes +
+			len(v.members)*sortedMemberOverhead
+	default:
+		return valueOverhead
+	}
+}
+
+// entrySize estimates the bytes held by a key and its entry.
+func entrySize(key string, entry RhelMapValue) int {
+	return entryOverhead + len(key) + MemoryUsage(entry.Value)
+}
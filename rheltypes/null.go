@@ -0,0 +1,65 @@
+package rheltypes
+
+// Null is the RESP3 null, RESP2 clients receive a null bulk string.
+type Null struct{}
+
+func (n Null) Size() int {
+	return len(NullPrefix) + len(rhelFieldDelim)
+}
+
+func (n Null) Serialize() []byte {
+	return append([]byte(NullPrefix), rhelFieldDelim...)
+}
+
+func (n Null) String() string {
+	return ""
+}
+
+func (n Null) First() RhelType {
+	return n
+}
+
+func (n Null) Integer() (int, error) { return 0, nil }
+
+func (n Null) TypeName() string {
+	return "null"
+}
+
+func (n Null) Float() (float64, error) { return 0, nil }
+
+func (n Null) resp2() RhelType {
+	return NewNullBulkString()
+}
+
+func (n Null) isRhelType() {}
+
+// NullArray is the RESP2 null array, *-1, RESP3 clients receive a Null.
+type NullArray struct{}
+
+func (n NullArray) Size() int {
+	return len(ArrayPrefix) + len("-1") + len(rhelFieldDelim)
+}
+
+func (n NullArray) Serialize() []byte {
+	buf := append([]byte(ArrayPrefix), "-1"...)
+
+	return append(buf, rhelFieldDelim...)
+}
+
+func (n NullArray) String() string {
+	return ""
+}
+
+func (n NullArray) First() RhelType {
+	return n
+}
+
+func (n NullArray) Integer() (int, error) { return 0, nil }
+
+func (n NullArray) TypeName() string {
+	return "null"
+}
+
+func (n NullArray) Float() (float64, error) { return 0, nil }
+
+func (n NullArray) isRhelType() {}
@@ -0,0 +1,188 @@
+package rheltypes
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+)
+
+const (
+	// maxBulkLength is the longest bulk string accepted, the default
+	// proto-max-bulk-len of Redis.
+	maxBulkLength = 512 << 20
+	// maxMultibulkLength is the largest aggregate accepted, as in Redis.
+	maxMultibulkLength = math.MaxInt32
+	// maxPrealloc bounds the room reserved for the elements of an aggregate
+	// before they arrive, its length being up to the peer.
+	maxPrealloc = 1024
+)
+
+var (
+	errInvalidBulkLength      = errors.New("invalid bulk length")
+	errInvalidMultibulkLength = errors.New("invalid multibulk length")
+	errLengthOutOfRange       = errors.New("length out of range")
+)
+
+// ProtocolError is a request that can't be decoded. Reason is the short
+// explanation replied to the client, the wrapped error holds the details
+// meant for the server log.
+type ProtocolError struct {
+	Reason string
+	err    error
+}
+
+func NewProtocolError(reason string, err error) ProtocolError {
+	return ProtocolError{Reason: reason, err: err}
+}
+
+// newProtocolError names the reason of a decoding failure after the errors
+// Redis reports, other failures are invalid requests.
+func newProtocolError(err error) ProtocolError {
+	for _, known := range []error{
+		errInvalidBulkLength,
+		errInvalidMultibulkLength,
+		errUnbalancedQuotes,
+		errInlineTooBig,
+	} {
+		if errors.Is(err, known) {
+			return NewProtocolError(known.Error(), err)
+		}
+	}
+
+	return NewProtocolError("invalid request", err)
+}
+
+func (e ProtocolError) Error() string {
+	return fmt.Sprintf("protocol error: %s: %s", e.Reason, e.err)
+}
+
+func (e ProtocolError) Unwrap() error {
+	return e.err
+}
+
+// Frame is a single decoded value together with the raw bytes it was
+// decoded from.
+type Frame struct {
+	Value RhelType
+	Raw   []byte
+}
+
+// Parser incrementally decodes values from a byte stream, e.g. a TCP
+// connection. Data is fed as it arrives, and only complete frames are
+// yielded, partial ones stay buffered until the rest of them is fed.
+type Parser struct {
+	buf  []byte
+	wait int
+}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+func NewParserFromBytes(data []byte) (p *Parser) {
+	p = NewParser()
+	p.Feed(data)
+
+	return
+}
+
+func (p *Parser) Feed(data []byte) {
+	p.buf = append(p.buf, data...)
+}
+
+// Buffered returns the number of bytes waiting to be decoded.
+func (p *Parser) Buffered() int {
+	return len(p.buf)
+}
+
+// Next decodes the next complete frame, ErrIncomplete is returned when
+// more data has to be fed first. Input that does not start with a RESP
+// prefix is decoded as an inline command, each line becoming an Array.
+func (p *Parser) Next() (frame Frame, err error) {
+	if len(p.buf) == 0 || len(p.buf) < p.wait {
+		return frame, ErrIncomplete
+	}
+
+	if isInline(p.buf) {
+		return p.nextInline()
+	}
+
+	tokens := NewTokenIterator(p.buf)
+
+	frame.Value, err = RhelEncode(tokens)
+
+	switch {
+	case errors.Is(err, ErrIncomplete):
+		p.wait = tokens.Offset() + tokens.Missing()
+
+		return frame, ErrIncomplete
+	case err != nil:
+		p.buf, p.wait = nil, 0
+
+		return frame, newProtocolError(err)
+	case frame.Value == nil:
+		return frame, ErrIncomplete
+	}
+
+	p.consume(&frame, tokens.Offset())
+
+	return frame, nil
+}
+
+func (p *Parser) nextInline() (frame Frame, err error) {
+	for isInline(p.buf) {
+		n, line, err := readInlineLine(p.buf)
+		if errors.Is(err, ErrIncomplete) {
+			p.wait = len(p.buf) + 1
+
+			return frame, err
+		} else if err != nil {
+			p.buf, p.wait = nil, 0
+
+			return frame, newProtocolError(err)
+		}
+
+		args, err := NewArrayFromInline(line)
+		if err != nil {
+			p.buf, p.wait = nil, 0
+
+			return frame, newProtocolError(err)
+		}
+
+		p.consume(&frame, n)
+
+		if len(args) > 0 {
+			frame.Value = args
+
+			return frame, nil
+		}
+	}
+
+	return p.Next()
+}
+
+func (p *Parser) consume(frame *Frame, n int) {
+	frame.Raw, p.buf, p.wait = p.buf[:n:n], p.buf[n:], 0
+
+	if len(p.buf) == 0 {
+		p.buf = nil
+	}
+}
+
+// Frames yields every complete frame buffered so far. A protocol error is
+// yielded once and ends the iteration.
+func (p *Parser) Frames() iter.Seq2[Frame, error] {
+	return func(yield func(Frame, error) bool) {
+		for {
+			frame, err := p.Next()
+			if errors.Is(err, ErrIncomplete) {
+				return
+			}
+
+			if !yield(frame, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package rheltypes
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stream holds frames of every kind, each one its own element.
+var stream = []string{
+	"*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$4\r\na\r\nb\r\n",
+	"PING\r\n",
+	"*2\r\n*1\r\n:1\r\n$-1\r\n",
+	"$0\r\n\r\n",
+	"%1\r\n+key\r\n,1.5\r\n",
+	"*-1\r\n",
+	"SET key \"two words\"\n",
+	"-ERR failed\r\n",
+	"*1\r\n$4\r\nPING\r\n",
+}
+
+// drain returns the frames buffered in p, failing on protocol errors.
+func drain(t *testing.T, p *Parser) (frames []Frame) {
+	t.Helper()
+
+	for frame, err := range p.Frames() {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+func TestParserIncomplete(t *testing.T) {
+	tests := []string{
+		"",
+		"*",
+		"*2\r",
+		"*2\r\n",
+		"*2\r\n$3\r\nGET\r\n",
+		"*2\r\n$3\r\nGET\r\n$3\r\nke",
+		"$5\r\nhello",
+		"$5\r\nhello\r",
+		":12",
+		"GET key",
+		"GET key\r",
+	}
+
+	for _, test := range tests {
+		p := NewParserFromBytes([]byte(test))
+
+		if _, err := p.Next(); !errors.Is(err, ErrIncomplete) {
+			t.Errorf("%q: got error %v, want ErrIncomplete", test, err)
+		}
+
+		if p.Buffered() != len(test) {
+			t.Errorf("%q: %d bytes buffered, want %d",
+				test, p.Buffered(), len(test))
+		}
+	}
+}
+
+func TestParserPipelined(t *testing.T) {
+	p := NewParserFromBytes([]byte(strings.Join(stream, "")))
+	frames := drain(t, p)
+
+	if len(frames) != len(stream) {
+		t.Fatalf("got %d frames, want %d", len(frames), len(stream))
+	}
+
+	for i, frame := range frames {
+		if string(frame.Raw) != stream[i] {
+			t.Errorf("frame %d is %q, want %q", i, frame.Raw, stream[i])
+		}
+	}
+
+	if p.Buffered() != 0 {
+		t.Errorf("%d bytes left buffered", p.Buffered())
+	}
+
+	want := Array{
+		NewBulkString("SET"),
+		NewBulkString("key"),
+		NewBulkString("two words"),
+	}
+	if got := frames[6].Value; got.String() != want.String() {
+		t.Errorf("inline command decoded as %v, want %v", got, want)
+	}
+}
+
+// TestParserSplit feeds the stream in two reads split at every offset, and
+// byte by byte, which has to decode the same frames as a single read.
+func TestParserSplit(t *testing.T) {
+	data := []byte(strings.Join(stream, ""))
+	want := drain(t, NewParserFromBytes(data))
+
+	for offset := range len(data) + 1 {
+		p := NewParserFromBytes(data[:offset])
+		got := drain(t, p)
+
+		p.Feed(data[offset:])
+		got = append(got, drain(t, p)...)
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("split at %d: got %v, want %v", offset, got, want)
+		}
+	}
+
+	p := NewParser()
+
+	var got []Frame
+
+	for _, b := range data {
+		p.Feed([]byte{b})
+		got = append(got, drain(t, p)...)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("fed byte by byte: got %v, want %v", got, want)
+	}
+}
+
+func TestParserLengths(t *testing.T) {
+	tests := []struct {
+		data   string
+		reason string
+	}{
+		{"$536870913\r\n", "invalid bulk length"},
+		{"$99999999999999999999\r\n", "invalid bulk length"},
+		{"$-2\r\n", "invalid bulk length"},
+		{"$abc\r\n", "invalid bulk length"},
+		{"*1\r\n$536870913\r\n", "invalid bulk length"},
+		{"*2147483648\r\n", "invalid multibulk length"},
+		{"*-2\r\n", "invalid multibulk length"},
+		{"%-1\r\n", "invalid multibulk length"},
+		{"%2147483648\r\n", "invalid multibulk length"},
+
+		// The largest lengths wait for their content, which isn't
+		// allocated upfront.
+		{"$536870912\r\n", ""},
+		{"*2147483647\r\n", ""},
+		{"%2147483647\r\n", ""},
+	}
+
+	for _, test := range tests {
+		p := NewParserFromBytes([]byte(test.data))
+		_, err := p.Next()
+
+		if test.reason == "" {
+			if !errors.Is(err, ErrIncomplete) {
+				t.Errorf("%q: got error %v, want ErrIncomplete",
+					test.data, err)
+			}
+
+			continue
+		}
+
+		var protocolErr ProtocolError
+		if !errors.As(err, &protocolErr) || protocolErr.Reason != test.reason {
+			t.Errorf("%q: got error %v, want %q", test.data, err, test.reason)
+		}
+
+		if p.Buffered() != 0 {
+			t.Errorf("%q: %d bytes left buffered after a protocol error",
+				test.data, p.Buffered())
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package rheltypes
+
+import (
+	"fmt"
+)
+
+// Push is an out of band RESP3 message, e.g. a pub/sub delivery.
+type Push []RhelType
+
+func NewPushFromStrings(values []string) Push {
+	return Push(NewArrayFromStrings(values))
+}
+
+func NewPushFromTokens(token Token, iter *TokenIterator) (Push, error) {
+	a, err := NewArrayFromTokens(token, iter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create push: %w", err)
+	}
+
+	return Push(a), nil
+}
+
+func (p Push) Size() int {
+	return len(p.Serialize())
+}
+
+func (p Push) Serialize() []byte {
+	buf := Array(p).Serialize()
+
+	return append([]byte(PushPrefix), buf[len(ArrayPrefix):]...)
+}
+
+func (p Push) String() string {
+	return Array(p).String()
+}
+
+func (p Push) First() RhelType {
+	return Array(p).First()
+}
+
+func (p Push) At(index int) RhelType {
+	return Array(p).At(index)
+}
+
+func (p Push) Integer() (int, error) { return 0, nil }
+
+func (p Push) TypeName() string {
+	return "push"
+}
+
+func (p Push) Float() (float64, error) { return 0, nil }
+
+func (p Push) isRhelType() {}
@@ -25,18 +25,55 @@ var (
 	ErrorPrefix        = rhelPrefix("-")
 	IntegerPrefix      = rhelPrefix(":")
 	SimpleStringPrefix = rhelPrefix("+")
+	MapPrefix          = rhelPrefix("%")
+	SetPrefix          = rhelPrefix("~")
+	DoublePrefix       = rhelPrefix(",")
+	NullPrefix         = rhelPrefix("_")
+	BooleanPrefix      = rhelPrefix("#")
+	BigNumberPrefix    = rhelPrefix("(")
+	VerbatimPrefix     = rhelPrefix("=")
+	PushPrefix         = rhelPrefix(">")
 	UnknownPrefix      = rhelPrefix("")
 )
 
 func NewRhelPrefix(p string) rhelPrefix {
 	switch rhelPrefix(p) {
-	case SimpleStringPrefix, BulkStringPrefix, ArrayPrefix, IntegerPrefix:
+	case SimpleStringPrefix,
+		BulkStringPrefix,
+		ArrayPrefix,
+		IntegerPrefix,
+		ErrorPrefix,
+		MapPrefix,
+		SetPrefix,
+		DoublePrefix,
+		NullPrefix,
+		BooleanPrefix,
+		BigNumberPrefix,
+		VerbatimPrefix,
+		PushPrefix:
 		return rhelPrefix(p)
 	default:
 		return UnknownPrefix
 	}
 }
 
+// Protocol is the RESP version negotiated by a client with HELLO.
+type Protocol int
+
+const (
+	Resp2 Protocol = 2
+	Resp3 Protocol = 3
+)
+
+func NewProtocol(version int) (p Protocol, ok bool) {
+	switch p = Protocol(version); p {
+	case Resp2, Resp3:
+		return p, true
+	default:
+		return Resp2, false
+	}
+}
+
 type PrefixError struct {
 	expected rhelPrefix
 	detected rhelPrefix
@@ -70,7 +107,119 @@ func RhelEncode(iter *TokenIterator) (RhelType, error) {
 		return NewBulkStringFromTokens(token, iter)
 	case IntegerPrefix:
 		return NewIntegerFromTokens(token)
+	case ErrorPrefix:
+		return NewErrorFromTokens(token)
+	case MapPrefix:
+		return NewMapFromTokens(token, iter)
+	case SetPrefix:
+		return NewSetFromTokens(token, iter)
+	case DoublePrefix:
+		return NewDoubleFromTokens(token)
+	case NullPrefix:
+		return Null{}, nil
+	case BooleanPrefix:
+		return NewBooleanFromTokens(token)
+	case BigNumberPrefix:
+		return NewBigNumberFromTokens(token)
+	case VerbatimPrefix:
+		return NewVerbatimStringFromTokens(token, iter)
+	case PushPrefix:
+		return NewPushFromTokens(token, iter)
 	default:
 		return nil, fmt.Errorf("unsupported prefix %q", token)
 	}
 }
+
+// rhelEncodeNested decodes an element of an aggregate, running out of input
+// in the middle of an aggregate means the frame is incomplete.
+func rhelEncodeNested(iter *TokenIterator) (RhelType, error) {
+	value, err := RhelEncode(iter)
+	if err == nil && value == nil {
+		return nil, ErrIncomplete
+	}
+
+	return value, err
+}
+
+// ToProtocol converts a reply into the shape expected by a client speaking
+// the given protocol. RESP2 clients get RESP3 only types downgraded to their
+// RESP2 counterparts, RESP3 clients get null bulk strings as native nulls.
+func ToProtocol(value RhelType, proto Protocol) RhelType {
+	switch v := value.(type) {
+	case Array:
+		out := make(Array, len(v))
+
+		for i, item := range v {
+			out[i] = ToProtocol(item, proto)
+		}
+
+		return out
+	case Map:
+		if proto == Resp2 {
+			out := make(Array, 0, len(v)*mapEntrySize)
+
+			for _, entry := range v {
+				out = append(
+					out,
+					ToProtocol(entry.Key, proto),
+					ToProtocol(entry.Value, proto),
+				)
+			}
+
+			return out
+		}
+
+		out := make(Map, len(v))
+
+		for i, entry := range v {
+			out[i] = MapEntry{
+				Key:   ToProtocol(entry.Key, proto),
+				Value: ToProtocol(entry.Value, proto),
+			}
+		}
+
+		return out
+	case Set:
+		out := Set(ToProtocol(Array(v), proto).(Array))
+		if proto == Resp2 {
+			return Array(out)
+		}
+
+		return out
+	case Push:
+		out := Push(ToProtocol(Array(v), proto).(Array))
+		if proto == Resp2 {
+			return Array(out)
+		}
+
+		return out
+	case BulkString:
+		if proto == Resp3 && v.IsNull() {
+			return Null{}
+		}
+	case NullArray:
+		if proto == Resp3 {
+			return Null{}
+		}
+	case Double, Null, Boolean, BigNumber, VerbatimString:
+		if proto == Resp2 {
+			return v.(resp3Type).resp2()
+		}
+	}
+
+	return value
+}
+
+// SerializeProtocol serializes a reply for a client speaking proto.
+func SerializeProtocol(value RhelType, proto Protocol) []byte {
+	if value == nil {
+		return nil
+	}
+
+	return ToProtocol(value, proto).Serialize()
+}
+
+// resp3Type is implemented by scalar types that only exist in RESP3.
+type resp3Type interface {
+	resp2() RhelType
+}
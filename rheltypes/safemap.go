@@ -3,7 +3,8 @@ package rheltypes
 import (
 	"iter"
 	"maps"
-	"sync"
+	"math/rand/v2"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,23 +15,63 @@ func currentTime() int64 {
 type RhelMapValue struct {
 	Value      RhelType
 	Expiration int64
+	access     *accessInfo
+	size       int
 }
 
 func (v RhelMapValue) IsExpired() bool {
 	return v.Expiration > 0 && currentTime() >= v.Expiration
 }
 
+// Idle returns the time elapsed since the key was last accessed.
+func (v RhelMapValue) Idle() time.Duration {
+	if v.access == nil {
+		return 0
+	}
+
+	return time.Duration(v.access.idle(currentTime())) * time.Millisecond
+}
+
+// Frequency returns the logarithmic access counter of the key, as used by
+// the LFU eviction policies.
+func (v RhelMapValue) Frequency() int {
+	if v.access == nil {
+		return 0
+	}
+
+	return int(v.access.frequency(currentTime()))
+}
+
+// ComputeAction tells SafeMap.Compute what to do with a key.
+type ComputeAction int
+
+const (
+	ComputeKeep ComputeAction = iota
+	ComputeStore
+	ComputeDelete
+)
+
+// SafeMap is a keyspace safe for concurrent use. Its keys are split into
+// shards locked independently, operations on several keys lock their
+// shards in increasing order and iterations work on a snapshot.
 type SafeMap struct {
-	mu     sync.RWMutex
-	data   map[string]RhelMapValue
-	ticker *time.Ticker
-	done   chan struct{}
+	shards   [numShards]shard
+	size     atomic.Int64
+	used     atomic.Int64
+	expired  atomic.Int64
+	hook     atomic.Pointer[KeyEventHook]
+	stats    expireCycleStats
+	interval time.Duration
+	ticker   *time.Ticker
+	done     chan struct{}
 }
 
+// NewSafeMap creates a map running an active expiry cycle every
+// cleanupInterval, expired keys are only removed when accessed if it is 0.
 func NewSafeMap(cleanupInterval time.Duration) *SafeMap {
 	sm := &SafeMap{
-		data: make(map[string]RhelMapValue),
-		done: make(chan struct{}),
+		interval: cleanupInterval,
+		done:     make(chan struct{}),
 	}
 
 	if cleanupInterval > 0 {
@@ -42,23 +83,97 @@ func NewSafeMap(cleanupInterval time.Duration) *SafeMap {
 }
 
 func (sm *SafeMap) SetToExpire(key string, value RhelType, px int64) {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
 
 	if px > 0 {
 		px += currentTime()
 	}
 
-	sm.data[key] = RhelMapValue{
+	sm.store(s, key, RhelMapValue{
 		Value:      value,
 		Expiration: px,
-	}
+	})
 }
 
 func (sm *SafeMap) Set(key string, value RhelType) {
 	sm.SetToExpire(key, value, 0)
 }
 
+// Update stores value under key, keeping the expiration of the value it
+// replaces if any.
+func (sm *SafeMap) Update(key string, value RhelType) {
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
+
+	entry := RhelMapValue{Value: value}
+
+	if current, found := s.get(key); found && !current.IsExpired() {
+		entry.Expiration = current.Expiration
+	}
+
+	sm.store(s, key, entry)
+}
+
+// Compute atomically replaces the value of key by the one fn derives from
+// the current one, found is false when key is missing or expired. The
+// returned action decides whether next is stored, key deleted or nothing
+// changed. A value stored with an expiration in the past is removed.
+func (sm *SafeMap) Compute(
+	key string,
+	fn func(current RhelMapValue, found bool) (RhelMapValue, ComputeAction),
+) {
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
+
+	current, found := s.get(key)
+	if found && sm.expire(s, key) {
+		current, found = RhelMapValue{}, false
+	}
+
+	switch next, action := fn(current, found); action {
+	case ComputeStore:
+		if next.IsExpired() {
+			sm.remove(s, key)
+		} else {
+			sm.store(s, key, next)
+		}
+	case ComputeDelete:
+		sm.remove(s, key)
+	}
+}
+
+// Expire sets the absolute expiration of key, 0 removing it, provided that
+// accept approves the current one. A key given an expiration in the past is
+// removed right away. It reports whether the expiration was set.
+func (sm *SafeMap) Expire(
+	key string,
+	expiration int64,
+	accept func(current int64) bool,
+) bool {
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
+
+	entry, found := s.get(key)
+	if !found || sm.expire(s, key) {
+		return false
+	}
+
+	if accept != nil && !accept(entry.Expiration) {
+		return false
+	}
+
+	entry.Expiration = expiration
+
+	if entry.IsExpired() {
+		sm.remove(s, key)
+	} else {
+		sm.store(s, key, entry)
+	}
+
+	return true
+}
+
 func (sm *SafeMap) SetString(key, value string, px int64) {
 	rhelValue := NewBulkString(value)
 
@@ -66,37 +181,195 @@ func (sm *SafeMap) SetString(key, value string, px int64) {
 }
 
 func (sm *SafeMap) SetStringValue(key, value string, expiration int64) {
-	rhelValue := NewBulkString(value)
+	sm.SetValue(key, NewBulkString(value), expiration)
+}
 
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
+// SetValue stores value under key, expiring at the expiration timestamp in
+// milliseconds if it isn't 0.
+func (sm *SafeMap) SetValue(key string, value RhelType, expiration int64) {
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
 
-	sm.data[key] = RhelMapValue{
-		Value:      rhelValue,
+	sm.store(s, key, RhelMapValue{
+		Value:      value,
 		Expiration: expiration,
-	}
+	})
 }
 
 func (sm *SafeMap) Get(key string) (value RhelType, found bool) {
-	valueRaw, found := sm.getValue(key)
+	entry, found := sm.lookup(key, true)
+
+	return entry.Value, found
+}
+
+// Entry returns the value of key along with its absolute expiration.
+func (sm *SafeMap) Entry(key string) (entry RhelMapValue, found bool) {
+	return sm.lookup(key, true)
+}
+
+// Peek returns the entry of key like Entry does, without counting as an
+// access of the key nor reporting it as missed, e.g. for TYPE, OBJECT or
+// the commands about to write it.
+func (sm *SafeMap) Peek(key string) (entry RhelMapValue, found bool) {
+	return sm.lookup(key, false)
+}
+
+func (sm *SafeMap) lookup(
+	key string,
+	touch bool,
+) (entry RhelMapValue, found bool) {
+	entry, found = sm.getValue(key, touch)
+
+	if found && entry.IsExpired() && sm.deleteExpired(key) {
+		entry, found = RhelMapValue{}, false
+	}
+
+	if !found && touch {
+		sm.report(KeyMissed, key)
+	}
+
+	return entry, found
+}
+
+// SetEntryIfAbsent stores entry unless key holds a value that hasn't
+// expired yet, it reports whether entry was stored.
+func (sm *SafeMap) SetEntryIfAbsent(key string, entry RhelMapValue) bool {
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
+
+	if current, found := s.get(key); found && !current.IsExpired() {
+		return false
+	}
+
+	sm.store(s, key, entry)
+
+	return true
+}
+
+// Entries yields a snapshot of the values that haven't expired, taken
+// with every shard locked so that it reflects a single point in time.
+func (sm *SafeMap) Entries() iter.Seq2[string, RhelMapValue] {
+	unlock := sm.readLockAll()
+	snapshot := make(map[string]RhelMapValue, sm.Size())
+
+	for i := range sm.shards {
+		maps.Insert(snapshot, sm.shards[i].all())
+	}
+
+	unlock()
+
+	return func(yield func(string, RhelMapValue) bool) {
+		for key, entry := range snapshot {
+			if entry.IsExpired() {
+				continue
+			}
+
+			if !yield(key, entry) {
+				return
+			}
+		}
+	}
+}
+
+// Rename moves the value of src to dst along with its expiration, unless
+// nx is set and dst exists. It reports whether src was found and whether
+// it was renamed.
+func (sm *SafeMap) Rename(src, dst string, nx bool) (found, renamed bool) {
+	srcShard, dstShard, unlock := sm.lockKeys(src, dst)
+	defer unlock()
+
+	entry, found := srcShard.get(src)
+	if !found || sm.expire(srcShard, src) {
+		return false, false
+	}
+
+	_, exists := dstShard.get(dst)
+	if exists && !sm.expire(dstShard, dst) && nx {
+		return true, false
+	}
+
+	if src != dst {
+		sm.remove(srcShard, src)
+		sm.place(dstShard, dst, entry)
+	}
+
+	return true, true
+}
+
+// Scan returns the keys of the buckets from cursor on, stopping once count
+// keys are collected, along with the cursor to continue from. The cursor is
+// 0 once every bucket has been visited. Keys never move between buckets, so
+// a scan returns every key present during the whole scan, and only once.
+func (sm *SafeMap) Scan(cursor, count int) (next int, keys []string) {
+	for next = max(cursor, 0); next < numBuckets; {
+		if len(keys) >= count {
+			return next, keys
+		}
+
+		s := &sm.shards[shardOf(next)]
+
+		s.mu.RLock()
+		next, keys = s.scan(next, count, keys)
+		s.mu.RUnlock()
+	}
+
+	return 0, keys
+}
+
+// RandomKey returns a key that hasn't expired, if any.
+func (sm *SafeMap) RandomKey() (key string, found bool) {
+	start := rand.IntN(numBuckets)
+
+	for i := range numBuckets {
+		index := (start + i) % numBuckets
+		s := &sm.shards[shardOf(index)]
+
+		s.mu.RLock()
+		key, found = randomBucketKey(s.bucket(index))
+		s.mu.RUnlock()
+
+		if found {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+func randomBucketKey(bucket map[string]RhelMapValue) (string, bool) {
+	for key, entry := range bucket {
+		if !entry.IsExpired() {
+			return key, true
+		}
+	}
+
+	return "", false
+}
 
-	if found && valueRaw.IsExpired() && sm.deleteExpired(key) {
-		return nil, false
+// Flush removes every key.
+func (sm *SafeMap) Flush() {
+	unlock := sm.lockAll()
+	defer unlock()
+
+	for i := range sm.shards {
+		sm.shards[i].clear()
 	}
 
-	return valueRaw.Value, found
+	sm.size.Store(0)
+	sm.used.Store(0)
 }
 
 func (sm *SafeMap) Delete(key string) bool {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
 
-	_, exists := sm.data[key]
-	if exists {
-		delete(sm.data, key)
+	if _, exists := s.get(key); !exists || sm.expire(s, key) {
+		return false
 	}
 
-	return exists
+	sm.remove(s, key)
+
+	return true
 }
 
 func (sm *SafeMap) Close() {
@@ -111,29 +384,162 @@ func (sm *SafeMap) Close() {
 	close(sm.done)
 }
 
+// Size returns the number of keys, including the expired ones that haven't
+// been removed yet.
 func (sm *SafeMap) Size() int {
-	return len(sm.data)
+	return int(sm.size.Load())
 }
 
-func (sm *SafeMap) Keys() iter.Seq[string] {
-	return maps.Keys(sm.data)
+func (sm *SafeMap) shardFor(key string) *shard {
+	return &sm.shards[shardOf(bucketOf(key))]
+}
+
+// lockKey write-locks the shard of key and returns it.
+func (sm *SafeMap) lockKey(key string) *shard {
+	s := sm.shardFor(key)
+	s.mu.Lock()
+
+	return s
 }
 
-func (sm *SafeMap) getValue(key string) (value RhelMapValue, found bool) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
-	value, found = sm.data[key]
+// lockKeys write-locks the shards of two keys, lowest shard first so that
+// concurrent calls can't deadlock, a shard shared by both only once.
+func (sm *SafeMap) lockKeys(
+	first, second string,
+) (firstShard, secondShard *shard, unlock func()) {
+	firstShard, secondShard = sm.shardFor(first), sm.shardFor(second)
+
+	if firstShard == secondShard {
+		firstShard.mu.Lock()
+
+		return firstShard, secondShard, firstShard.mu.Unlock
+	}
+
+	lower, upper := firstShard, secondShard
+	if shardOf(bucketOf(first)) > shardOf(bucketOf(second)) {
+		lower, upper = upper, lower
+	}
+
+	lower.mu.Lock()
+	upper.mu.Lock()
+
+	return firstShard, secondShard, func() {
+		upper.mu.Unlock()
+		lower.mu.Unlock()
+	}
+}
+
+// lockAll and readLockAll lock every shard in increasing order.
+func (sm *SafeMap) lockAll() (unlock func()) {
+	for i := range sm.shards {
+		sm.shards[i].mu.Lock()
+	}
+
+	return func() {
+		for i := range sm.shards {
+			sm.shards[i].mu.Unlock()
+		}
+	}
+}
+
+func (sm *SafeMap) readLockAll() (unlock func()) {
+	for i := range sm.shards {
+		sm.shards[i].mu.RLock()
+	}
+
+	return func() {
+		for i := range sm.shards {
+			sm.shards[i].mu.RUnlock()
+		}
+	}
+}
+
+func (sm *SafeMap) getValue(
+	key string,
+	touch bool,
+) (value RhelMapValue, found bool) {
+	s := sm.shardFor(key)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if value, found = s.get(key); found && touch {
+		value.access.touch()
+	}
 
 	return
 }
 
 func (sm *SafeMap) deleteExpired(key string) (deleted bool) {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-	v, found := sm.data[key]
+	s := sm.lockKey(key)
+	defer s.mu.Unlock()
+
+	return sm.expire(s, key)
+}
+
+// store and remove keep the volatile index, the size and the memory usage
+// in sync with the data, the lock of s has to be held. A stored entry takes
+// over the access info of the one it replaces, which counts as an access,
+// unless it had expired.
+func (sm *SafeMap) store(s *shard, key string, entry RhelMapValue) {
+	entry.access = nil
+
+	if current, found := s.get(key); found && !sm.expire(s, key) {
+		entry.access = current.access
+		entry.access.touch()
+	}
+
+	sm.place(s, key, entry)
+}
+
+// place stores entry along with its access info, e.g. for a renamed key.
+// Integer strings are stored as an IntString.
+func (sm *SafeMap) place(s *shard, key string, entry RhelMapValue) {
+	if entry.access == nil {
+		entry.access = newAccessInfo()
+	}
+
+	entry.Value = compactValue(entry.Value)
+
+	if current, found := s.get(key); found {
+		sm.used.Add(-int64(current.size))
+	}
+
+	entry.size = entrySize(key, entry)
+	sm.used.Add(int64(entry.size))
+
+	if s.set(key, entry) {
+		sm.size.Add(1)
+		sm.report(KeyAdded, key)
+	}
+
+	if entry.Expiration > 0 {
+		s.volatile.add(key)
+	} else {
+		s.volatile.remove(key)
+	}
+}
+
+func (sm *SafeMap) remove(s *shard, key string) {
+	current, found := s.get(key)
+	if !found {
+		return
+	}
+
+	sm.used.Add(-int64(current.size))
+	sm.size.Add(-1)
+	s.delete(key)
+	s.volatile.remove(key)
+}
+
+// expire removes key if it has expired, the lock of s has to be held.
+func (sm *SafeMap) expire(s *shard, key string) (deleted bool) {
+	v, found := s.get(key)
 
 	if deleted = found && v.IsExpired(); deleted {
-		delete(sm.data, key)
+		sm.remove(s, key)
+		sm.expired.Add(1)
+		sm.report(KeyExpired, key)
 	}
 
 	return
@@ -143,15 +549,9 @@ func (sm *SafeMap) cleanupExpired() {
 	for {
 		select {
 		case <-sm.ticker.C:
-			sm.scanAndDelete()
+			sm.activeExpireCycle()
 		case <-sm.done:
 			return
 		}
 	}
 }
-
-func (sm *SafeMap) scanAndDelete() {
-	for key := range sm.data {
-		go sm.deleteExpired(key)
-	}
-}
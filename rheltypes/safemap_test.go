@@ -0,0 +1,62 @@
+package rheltypes
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestScanSparse(t *testing.T) {
+	sm := NewSafeMap(0)
+	sm.Set("a", NewBulkString("1"))
+	sm.Set("b", NewBulkString("2"))
+
+	next, keys := sm.Scan(0, 100)
+	if next != 0 || len(keys) != 2 {
+		t.Fatalf("got cursor %d and keys %q, want 0 and 2 keys", next, keys)
+	}
+
+	next, keys = sm.Scan(0, 1)
+	if len(keys) != 1 {
+		t.Fatalf("got keys %q with cursor %d, want 1 key", keys, next)
+	}
+}
+
+func TestScanVisitsEveryKeyOnce(t *testing.T) {
+	sm := NewSafeMap(0)
+
+	for i := range 1000 {
+		sm.Set(fmt.Sprint("key:", i), NewBulkString("v"))
+	}
+
+	for i := range 500 {
+		sm.Delete(fmt.Sprint("key:", i))
+	}
+
+	seen := map[string]int{}
+	cursor := 0
+
+	for {
+		next, keys := sm.Scan(cursor, 10)
+		if next != 0 && len(keys) == 0 {
+			t.Fatalf("got an empty page at cursor %d", cursor)
+		}
+
+		for _, key := range keys {
+			seen[key]++
+		}
+
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+
+	if len(seen) != 500 {
+		t.Fatalf("got %d keys, want 500", len(seen))
+	}
+
+	for key, times := range seen {
+		if times != 1 {
+			t.Errorf("got key %q %d times, want once", key, times)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package rheltypes
+
+import (
+	"fmt"
+)
+
+// Set is the RESP3 unordered collection reply.
+type Set []RhelType
+
+func NewSetFromTokens(token Token, iter *TokenIterator) (Set, error) {
+	a, err := NewArrayFromTokens(token, iter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create set: %w", err)
+	}
+
+	return Set(a), nil
+}
+
+func (s Set) Size() int {
+	return len(s.Serialize())
+}
+
+func (s Set) Serialize() []byte {
+	buf := Array(s).Serialize()
+
+	return append([]byte(SetPrefix), buf[len(ArrayPrefix):]...)
+}
+
+func (s Set) String() string {
+	return Array(s).String()
+}
+
+func (s Set) First() RhelType {
+	return Array(s).First()
+}
+
+func (s Set) Integer() (int, error) { return 0, nil }
+
+func (s Set) TypeName() string {
+	return "set"
+}
+
+func (s Set) Float() (float64, error) { return 0, nil }
+
+func (s Set) isRhelType() {}
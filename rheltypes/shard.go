@@ -0,0 +1,131 @@
+package rheltypes
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"sync"
+)
+
+// Keys are spread by their hash into a fixed number of buckets, a key
+// always lives in the same bucket so that a scan can resume from the bucket
+// it stopped at. Buckets are grouped into shards, each one guarded by its
+// own lock so that keys of different shards are written concurrently. The
+// buckets of a shard have to fit the 64 bits of its occupied bitmap.
+const (
+	bucketBits      = 12
+	numBuckets      = 1 << bucketBits
+	shardBits       = 6
+	numShards       = 1 << shardBits
+	bucketsPerShard = numBuckets / numShards
+)
+
+var bucketSeed = maphash.MakeSeed()
+
+func bucketOf(key string) int {
+	return int(maphash.String(bucketSeed, key) >> (64 - bucketBits))
+}
+
+// shardOf returns the shard holding bucket, every shard holds a contiguous
+// range of buckets.
+func shardOf(bucket int) int {
+	return bucket / bucketsPerShard
+}
+
+// shard holds the keys of its buckets along with the ones of them having an
+// expiration, its methods expect the lock to be held. Buckets are allocated
+// on first use, bit i of occupied is set while bucket i holds keys so that
+// scans skip the empty ones.
+type shard struct {
+	mu       sync.RWMutex
+	buckets  [bucketsPerShard]map[string]RhelMapValue
+	occupied uint64
+	volatile volatileIndex
+}
+
+func (s *shard) get(key string) (entry RhelMapValue, found bool) {
+	entry, found = s.buckets[bucketOf(key)%bucketsPerShard][key]
+
+	return
+}
+
+// set stores entry and reports whether key is a new one.
+func (s *shard) set(key string, entry RhelMapValue) (added bool) {
+	index := bucketOf(key) % bucketsPerShard
+
+	bucket := s.buckets[index]
+	if bucket == nil {
+		bucket = make(map[string]RhelMapValue)
+		s.buckets[index] = bucket
+	}
+
+	_, found := bucket[key]
+	bucket[key] = entry
+	s.occupied |= 1 << index
+
+	return !found
+}
+
+func (s *shard) delete(key string) {
+	index := bucketOf(key) % bucketsPerShard
+
+	delete(s.buckets[index], key)
+
+	if len(s.buckets[index]) == 0 {
+		s.occupied &^= 1 << index
+	}
+}
+
+func (s *shard) clear() {
+	clear(s.buckets[:])
+	s.occupied = 0
+	s.volatile.reset()
+}
+
+// scan appends the keys of the buckets of the shard from the bucket at
+// index on, until keys holds count of them, and returns the index of the
+// bucket to continue from. Empty buckets are skipped without being visited.
+func (s *shard) scan(
+	index, count int,
+	keys []string,
+) (next int, scanned []string) {
+	first := index - index%bucketsPerShard
+	occupied := s.occupied &^ (1<<(index%bucketsPerShard) - 1)
+
+	for occupied != 0 && len(keys) < count {
+		bucket := bits.TrailingZeros64(occupied)
+		occupied &^= 1 << bucket
+
+		for key, entry := range s.buckets[bucket] {
+			if !entry.IsExpired() {
+				keys = append(keys, key)
+			}
+		}
+
+		next = first + bucket + 1
+	}
+
+	if occupied == 0 {
+		next = first + bucketsPerShard
+	}
+
+	return next, keys
+}
+
+// bucket returns the bucket at index among all the buckets of the map, it
+// has to be one of the shard.
+func (s *shard) bucket(index int) map[string]RhelMapValue {
+	return s.buckets[index%bucketsPerShard]
+}
+
+func (s *shard) all() iter.Seq2[string, RhelMapValue] {
+	return func(yield func(string, RhelMapValue) bool) {
+		for _, bucket := range s.buckets {
+			for key, entry := range bucket {
+				if !yield(key, entry) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package rheltypes
 
 import (
 	"cmp"
+	"iter"
 	"log"
 	"slices"
 	"strconv"
@@ -14,6 +15,10 @@ type SortedSetMember struct {
 	score float64
 }
 
+func (m SortedSetMember) Score() float64 {
+	return m.score
+}
+
 func (m SortedSetMember) AsBulkString() BulkString {
 	return NewBulkString(strconv.FormatFloat(m.score, 'e', 16, 64))
 }
@@ -25,9 +30,12 @@ func (m SortedSetMember) asArray() Array {
 	}
 }
 
+// SortedSet is a sorted set value, bytes keeps the length of the names of
+// its members for the memory usage.
 type SortedSet struct {
 	names   map[string]*SortedSetMember
 	members []*SortedSetMember
+	bytes   int
 }
 
 func NewSortedSet() *SortedSet {
@@ -102,6 +110,7 @@ func (s *SortedSet) Add(name string, score float64) (found bool) {
 	} else {
 		member = &SortedSetMember{name: name, score: score}
 		s.names[name] = member
+		s.bytes += len(name)
 	}
 
 	s.InsertMember(member)
@@ -114,6 +123,7 @@ func (s *SortedSet) Delete(name string) (found bool) {
 
 	if member, found = s.Get(name); found {
 		delete(s.names, name)
+		s.bytes -= len(name)
 		index := s.indexMember(member)
 		s.members = slices.Delete(s.members, index, index+1)
 	}
@@ -157,6 +167,17 @@ func (s SortedSet) Range(start, stop int) (out Array) {
 	return s.asArray().Range(start, stop)
 }
 
+// Members yields the name and score of every member, by increasing score.
+func (s SortedSet) Members() iter.Seq2[string, float64] {
+	return func(yield func(string, float64) bool) {
+		for _, member := range s.members {
+			if !yield(member.name, member.score) {
+				return
+			}
+		}
+	}
+}
+
 func (s SortedSet) asArray() Array {
 	output := make(Array, s.Size())
 
@@ -168,14 +168,28 @@ func (i StreamItem) ToArray() (a Array) {
 	return
 }
 
-type Stream []StreamItem
+// StreamItems is a run of consecutive items of a stream.
+type StreamItems []StreamItem
+
+// Stream is a stream value, its items sorted by id. bytes and fields keep
+// the length of the fields and values of the items and their number, for
+// the memory usage.
+type Stream struct {
+	items  StreamItems
+	bytes  int
+	fields int
+}
 
 func NewStream() Stream {
-	return make(Stream, 0, defaultStreamCapacity)
+	return Stream{items: make(StreamItems, 0, defaultStreamCapacity)}
+}
+
+func (s Stream) Len() int {
+	return len(s.items)
 }
 
 func (s Stream) LastId() (id StreamItemId) {
-	if len(s) == 0 {
+	if len(s.items) == 0 {
 		return StreamItemId{}
 	}
 
@@ -218,7 +232,12 @@ func (s *Stream) Add(
 		return
 	}
 
-	*s = append(*s, StreamItem{id: id, values: values})
+	s.items = append(s.items, StreamItem{id: id, values: values})
+	s.fields += len(values)
+
+	for field, value := range values {
+		s.bytes += len(field) + len(value)
+	}
 
 	added = id.ToString()
 
@@ -239,9 +258,9 @@ func (s Stream) String() string {
 
 func (s Stream) At(index int) *StreamItem {
 	if index < 0 {
-		return s.At(len(s) + index)
-	} else if index < len(s) {
-		return &s[index]
+		return s.At(len(s.items) + index)
+	} else if index < len(s.items) {
+		return &s.items[index]
 	} else {
 		return nil
 	}
@@ -265,7 +284,10 @@ func helperItemIdCompare(item StreamItem, id StreamItemId) int {
 	return item.id.Cmp(id)
 }
 
-func (s Stream) Range(lower, upper string, includeLower bool) Stream {
+func (s Stream) Range(
+	lower, upper string,
+	includeLower bool,
+) StreamItems {
 	lowerId, lowerIdType := NewStreamItemId(lower)
 	upperId, upperIdType := NewStreamItemId(upper)
 
@@ -276,21 +298,22 @@ func (s Stream) Range(lower, upper string, includeLower bool) Stream {
 
 	if lowerIdType != ZeroId {
 		lowerIndex, found = slices.BinarySearchFunc(
-			s,
+			s.items,
 			lowerId,
 			helperItemIdCompare,
 		)
 
-		if (!found && lowerIndex < len(s)-1) || (found && !includeLower) {
+		if (!found && lowerIndex < len(s.items)-1) ||
+			(found && !includeLower) {
 			lowerIndex++
 		}
 	}
 
-	upperIndex := len(s)
+	upperIndex := len(s.items)
 
 	if upperIdType != EndId {
 		upperIndex, found = slices.BinarySearchFunc(
-			s,
+			s.items,
 			upperId,
 			helperItemIdCompare,
 		)
@@ -299,10 +322,14 @@ func (s Stream) Range(lower, upper string, includeLower bool) Stream {
 		}
 	}
 
-	return s[lowerIndex:upperIndex]
+	return s.items[lowerIndex:upperIndex]
 }
 
 func (s Stream) ToArray() (a Array) {
+	return s.items.ToArray()
+}
+
+func (s StreamItems) ToArray() (a Array) {
 	a = make(Array, len(s))
 
 	for i, item := range s {
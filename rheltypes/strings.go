@@ -69,14 +69,23 @@ func NewBulkStringFromTokens(
 	iter *TokenIterator,
 ) (bs BulkString, err error) {
 	bs.Length, err = token.AsSize()
+	if err == nil && (bs.Length < -1 || bs.Length > maxBulkLength) {
+		err = errLengthOutOfRange
+	}
+
 	if err != nil {
 		return bs, fmt.Errorf(
-			"failed to read bul string size from %q: %w",
-			token,
+			"%w %q: %w",
+			errInvalidBulkLength,
+			token.ToString(),
 			err,
 		)
 	}
 
+	if bs.Length < 0 {
+		return NewNullBulkString(), nil
+	}
+
 	bs.Text, err = iter.readBytes(bs.Length)
 	if err != nil {
 		return bs, fmt.Errorf(
@@ -161,6 +170,10 @@ func (s BulkString) Integer() (num int, err error) {
 	return strconv.Atoi(string(s.Text))
 }
 
+func (s BulkString) IsNull() bool {
+	return s.Length == -1
+}
+
 func (s BulkString) IsTerminated() bool {
 	return s.terminated
 }
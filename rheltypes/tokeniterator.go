@@ -3,6 +3,7 @@ package rheltypes
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"slices"
@@ -10,6 +11,10 @@ import (
 	"strings"
 )
 
+// ErrIncomplete is returned when the buffered input ends in the middle of a
+// frame and more data has to be read before it can be decoded.
+var ErrIncomplete = errors.New("incomplete frame")
+
 type Token struct {
 	Prefix rhelPrefix
 	Data   string
@@ -17,6 +22,12 @@ type Token struct {
 
 func NewToken(str string) (token Token) {
 	before, _ := strings.CutSuffix(str, string(rhelFieldDelim))
+	if len(before) == 0 {
+		token.Prefix = UnknownPrefix
+
+		return
+	}
+
 	token.Prefix = NewRhelPrefix(before[:1])
 	token.Data = before[1:]
 
@@ -39,9 +50,10 @@ func (t Token) AsSize() (i int, err error) {
 const defaultIteratorBufferSize = 256
 
 type BuffIterator struct {
-	buf    *bufio.Reader
-	done   bool
-	offset int
+	buf     *bufio.Reader
+	done    bool
+	offset  int
+	missing int
 }
 
 func NewBuffIterator(data []byte) (iter *BuffIterator) {
@@ -61,6 +73,18 @@ func (r *BuffIterator) IsDone() bool {
 	return r.done
 }
 
+// Missing is the minimal number of bytes needed to complete the frame
+// after ErrIncomplete was returned.
+func (r *BuffIterator) Missing() int {
+	return r.missing
+}
+
+func (r *BuffIterator) incomplete(missing int) error {
+	r.missing = max(missing, 1)
+
+	return ErrIncomplete
+}
+
 func (r *BuffIterator) validate(err error) error {
 	switch err {
 	case io.EOF:
@@ -80,15 +104,16 @@ func (r *BuffIterator) readBytes(n int) (b []byte, err error) {
 
 	b = make([]byte, n)
 
-	rn, err := r.buf.Read(b)
-	if err := r.validate(err); err != nil {
+	rn, err := io.ReadFull(r.buf, b)
+	r.offset += rn
+
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return nil, r.incomplete(n - rn)
+	case err != nil:
 		return nil, fmt.Errorf("failed to read %d bytes: %w", n, err)
 	}
 
-	b = b[:rn]
-
-	r.offset += rn
-
 	return
 }
 
@@ -110,9 +135,16 @@ func (r *BuffIterator) readString(delim []byte) (out string, err error) {
 		r.offset += len(temp)
 		buf = append(buf, temp...)
 
-		if err = r.validate(err); err != nil || r.IsDone() {
-			break
-		} else if identicalSlices(buf[len(buf)-delimLen:], delim) {
+		if err = r.validate(err); err != nil {
+			return out, err
+		} else if r.IsDone() {
+			if len(buf) > 0 {
+				return out, r.incomplete(delimLen)
+			}
+
+			return out, nil
+		} else if len(buf) >= delimLen &&
+			identicalSlices(buf[len(buf)-delimLen:], delim) {
 			break
 		}
 	}
@@ -125,7 +157,9 @@ func (r *BuffIterator) readString(delim []byte) (out string, err error) {
 func (r *BuffIterator) skipDelim(delim []byte) (ok bool, err error) {
 	b, err := r.buf.Peek(len(delim))
 
-	if err = r.validate(err); err != nil {
+	if errors.Is(err, io.EOF) && bytes.HasPrefix(delim, b) {
+		return ok, r.incomplete(len(delim) - len(b))
+	} else if err = r.validate(err); err != nil {
 		return ok, fmt.Errorf(
 			"failed to skip delim %q %X: %w",
 			delim,
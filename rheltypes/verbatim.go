@@ -0,0 +1,99 @@
+package rheltypes
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	verbatimFormatLength = 3
+	verbatimFormatSep    = ":"
+)
+
+const (
+	VerbatimText     = "txt"
+	VerbatimMarkdown = "mkd"
+)
+
+// VerbatimString is a RESP3 string tagged with its format, e.g. "txt".
+type VerbatimString struct {
+	Format string
+	Text   []byte
+}
+
+func NewVerbatimString(format, text string) VerbatimString {
+	return VerbatimString{Format: format, Text: []byte(text)}
+}
+
+func NewVerbatimStringFromTokens(
+	token Token,
+	iter *TokenIterator,
+) (v VerbatimString, err error) {
+	bs, err := NewBulkStringFromTokens(token, iter)
+	if err != nil {
+		return v, fmt.Errorf("failed to read verbatim string: %w", err)
+	}
+
+	prefixLength := verbatimFormatLength + len(verbatimFormatSep)
+	if len(bs.Text) < prefixLength {
+		return v, fmt.Errorf("verbatim string %q has no format", bs.Text)
+	}
+
+	v.Format = string(bs.Text[:verbatimFormatLength])
+	v.Text = bs.Text[prefixLength:]
+
+	return v, nil
+}
+
+func (v VerbatimString) length() int {
+	return verbatimFormatLength + len(verbatimFormatSep) + len(v.Text)
+}
+
+func (v VerbatimString) Size() int {
+	return len(VerbatimPrefix) +
+		len(strconv.Itoa(v.length())) +
+		len(rhelFieldDelim) +
+		v.length() +
+		len(rhelFieldDelim)
+}
+
+func (v VerbatimString) Serialize() []byte {
+	buf := make([]byte, 0, v.Size())
+	buf = fmt.Appendf(
+		buf,
+		"%s%d\r\n%s%s",
+		VerbatimPrefix,
+		v.length(),
+		v.Format,
+		verbatimFormatSep,
+	)
+	buf = append(buf, v.Text...)
+
+	return append(buf, rhelFieldDelim...)
+}
+
+func (v VerbatimString) String() string {
+	return string(v.Text)
+}
+
+func (v VerbatimString) First() RhelType {
+	return v
+}
+
+func (v VerbatimString) Integer() (int, error) {
+	return strconv.Atoi(string(v.Text))
+}
+
+func (v VerbatimString) TypeName() string {
+	return "string"
+}
+
+func (v VerbatimString) Float() (float64, error) {
+	return strconv.ParseFloat(string(v.Text), 64)
+}
+
+func (v VerbatimString) resp2() RhelType {
+	return NewBulkString(string(v.Text))
+}
+
+func (v VerbatimString) isRhelType() {}
@@ -0,0 +1,55 @@
+package rheltypes
+
+import (
+	"math/rand/v2"
+)
+
+// volatileIndex holds the keys of a SafeMap having an expiration, it lets
+// the active expiry pick random keys without scanning the whole map. The
+// zero value is an empty index.
+type volatileIndex struct {
+	keys      []string
+	positions map[string]int
+}
+
+func (v *volatileIndex) add(key string) {
+	if _, found := v.positions[key]; found {
+		return
+	}
+
+	if v.positions == nil {
+		v.positions = make(map[string]int)
+	}
+
+	v.positions[key] = len(v.keys)
+	v.keys = append(v.keys, key)
+}
+
+// remove swaps key with the last one so that removals are O(1).
+func (v *volatileIndex) remove(key string) {
+	pos, found := v.positions[key]
+	if !found {
+		return
+	}
+
+	last := len(v.keys) - 1
+
+	v.keys[pos] = v.keys[last]
+	v.positions[v.keys[pos]] = pos
+	v.keys = v.keys[:last]
+
+	delete(v.positions, key)
+}
+
+func (v *volatileIndex) random() string {
+	return v.keys[rand.IntN(len(v.keys))]
+}
+
+func (v *volatileIndex) len() int {
+	return len(v.keys)
+}
+
+func (v *volatileIndex) reset() {
+	v.keys = nil
+	clear(v.positions)
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/commands"
+	"github.com/codecrafters-io/redis-starter-go/pubsub"
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const (
+	defaultTcpBuffer      = 64 * 1024
+	defaultPublishTimeout = 50 * time.Millisecond
+)
+
+func sendResponse(
+	conn net.Conn,
+	result *commands.CommandResult,
+	session *commands.Session,
+) (err error) {
+	if _, err = conn.Write(
+		result.Serialize(session.Protocol()),
+	); err != nil {
+		err = fmt.Errorf("error sending data: %w", err)
+	} else if err = result.Err; err != nil {
+		err = fmt.Errorf("error during cmd execution: %w", err)
+	}
+
+	return
+}
+
+func (s *Server) readCommand(
+	conn net.Conn,
+	buf []byte,
+	parser *rheltypes.Parser,
+) (end bool) {
+	if n, err := conn.Read(buf); err != nil {
+		end = true
+
+		if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+			s.logger.Printf("error reading from %s: %s", conn.RemoteAddr(), err)
+		}
+
+		return
+	} else {
+		parser.Feed(buf[:n])
+	}
+
+	return
+}
+
+// publishMessage forwards the messages of the subscribed channels to conn
+// until the session unsubscribes from all of them or done is closed.
+func (s *Server) publishMessage(
+	conn net.Conn,
+	session *commands.Session,
+	transaction *commands.Transaction,
+	done <-chan struct{},
+) {
+	broker := s.instance.Broker()
+
+	for transaction.IsSubscribed() {
+		for name, sub := range transaction.IterSubscriptions(broker) {
+		inner:
+			for {
+				select {
+				case msg := <-sub.Messages:
+					if msg == nil {
+						break inner
+					}
+					conn.Write(rheltypes.SerializeProtocol(
+						rheltypes.NewPushFromStrings(
+							pushedMessage(name, msg),
+						),
+						session.Protocol(),
+					))
+
+				case <-done:
+					return
+				case <-time.After(defaultPublishTimeout):
+					break inner
+				}
+			}
+		}
+	}
+}
+
+// pushedMessage formats msg received from the subscription name, a channel
+// or a pattern.
+func pushedMessage(name string, msg pubsub.Message) []string {
+	if pm, ok := msg.(pubsub.PatternMessage); ok {
+		return []string{
+			"pmessage",
+			name,
+			pm.Channel,
+			pm.Payload.(rheltypes.RhelType).String(),
+		}
+	}
+
+	return []string{"message", name, msg.(rheltypes.RhelType).String()}
+}
+
+func (s *Server) executeCommand(
+	conn net.Conn,
+	parser *rheltypes.Parser,
+	session *commands.Session,
+	done <-chan struct{},
+) (keepConn bool, err error) {
+	pool := s.instance.Replicas()
+
+	for result := range commands.ExecuteCommand(parser, session) {
+		if err = sendResponse(conn, result, session); err != nil {
+			return keepConn, err
+		}
+
+		if result.Sub {
+			go s.publishMessage(conn, session, session.Transaction(), done)
+		}
+
+		if keep := result.KeepConnection; keep && !keepConn {
+			keepConn = true
+
+			pool.Add(conn)
+		}
+
+		if result.Resend {
+			db := session.SelectedDB()
+			selectCmd := commands.NewCmdSelect().Render(db).Serialize()
+
+			err := pool.ResendInDb(db, selectCmd, result.Command)
+			if err != nil {
+				s.logger.Printf("error propagating to replicas: %s", err)
+			}
+		}
+
+		if result.Ack != 0 {
+			pool.Ack(conn.RemoteAddr().String(), result.Ack)
+		}
+	}
+
+	return keepConn, err
+}
+
+// handleConn serves a client until it disconnects, errors only close its
+// own connection.
+func (s *Server) handleConn(conn net.Conn) {
+	var keep bool
+
+	var err error
+
+	done := make(chan struct{})
+
+	defer func() {
+		close(done)
+		s.untrack(conn)
+
+		if keep && err == nil {
+			return
+		}
+
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			s.logger.Printf("error closing %s: %s", conn.RemoteAddr(), err)
+		}
+	}()
+
+	session := commands.NewSession(s.instance)
+	parser := rheltypes.NewParser()
+	buf := make([]byte, defaultTcpBuffer)
+
+	for {
+		if end := s.readCommand(conn, buf, parser); end {
+			return
+		}
+
+		keep, err = s.executeCommand(conn, parser, session, done)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.logger.Printf(
+					"closing connection %s: %s",
+					conn.RemoteAddr(),
+					err,
+				)
+			}
+
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"log"
+)
+
+const (
+	defaultAddr      = ":6379"
+	defaultDatabases = 16
+)
+
+// Option configures a Server created by New.
+type Option func(*Server)
+
+// WithAddr sets the TCP address ListenAndServe listens on, ":6379" by
+// default.
+func WithAddr(addr string) Option {
+	return func(s *Server) {
+		s.addr = addr
+	}
+}
+
+// WithDir sets the directory holding the RDB file, it is created when
+// missing.
+func WithDir(dir string) Option {
+	return func(s *Server) {
+		s.dir = dir
+	}
+}
+
+// WithDbFilename sets the name of the RDB file loaded on start, no file is
+// used when empty.
+func WithDbFilename(name string) Option {
+	return func(s *Server) {
+		s.dbFilename = name
+	}
+}
+
+// WithDatabases sets the number of logical databases, 16 by default.
+func WithDatabases(n int) Option {
+	return func(s *Server) {
+		s.databases = n
+	}
+}
+
+// WithMaxMemory limits the memory used by the keyspace, given like the
+// maxmemory directive, e.g. "100mb". It isn't limited by default.
+func WithMaxMemory(limit string) Option {
+	return func(s *Server) {
+		s.maxMemory = limit
+	}
+}
+
+// WithMaxMemoryPolicy sets how keys are evicted once maxmemory is reached,
+// one of the maxmemory-policy values, "noeviction" by default.
+func WithMaxMemoryPolicy(policy string) Option {
+	return func(s *Server) {
+		s.maxMemoryPolicy = policy
+	}
+}
+
+// WithReplicaOf makes the server a replica of the master at address, given
+// either as "host port" like the replicaof directive or as "host:port".
+func WithReplicaOf(address string) Option {
+	return func(s *Server) {
+		s.replicaOf = address
+	}
+}
+
+// WithLogger sets the logger used to report connection failures, the
+// standard logger by default.
+func WithLogger(logger *log.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/client"
+	"github.com/codecrafters-io/redis-starter-go/commands"
+	"github.com/codecrafters-io/redis-starter-go/rheltypes"
+)
+
+const (
+	defaultDialTimeout     = 5 * time.Second
+	defaultSnapshotTimeout = 1 * time.Minute
+)
+
+// masterAddr turns the replicaof address into a dialable one, both "host
+// port" and "host:port" are accepted.
+func masterAddr(replicaOf string) string {
+	if host, port, found := strings.Cut(replicaOf, " "); found {
+		return net.JoinHostPort(host, strings.TrimSpace(port))
+	}
+
+	return replicaOf
+}
+
+// sendHandshake introduces the replica to its master, every step has to
+// succeed before the replication stream starts.
+func sendHandshake(master *client.Conn, port string) error {
+	handshakeCommands := []struct {
+		label string
+		cmd   rheltypes.Array
+	}{
+		{label: "ping", cmd: commands.NewCmdPing().Render()},
+		{
+			label: "replconf port",
+			cmd:   commands.NewCmdReplconf().Render("listening-port", port),
+		},
+		{
+			label: "replconf capa",
+			cmd:   commands.NewCmdReplconf().Render("capa", "psync2"),
+		},
+		{label: "psync", cmd: commands.NewCmdPsync().Render("?", "-1")},
+	}
+
+	ctx := context.Background()
+
+	for _, step := range handshakeCommands {
+		if _, err := master.DoArray(ctx, step.cmd); err != nil {
+			return fmt.Errorf("failed %s: %w", step.label, err)
+		}
+	}
+
+	return nil
+}
+
+// loadSnapshot replaces the databases with the RDB file the master sends
+// right after the handshake.
+func (s *Server) loadSnapshot(master *client.Conn) error {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		defaultSnapshotTimeout,
+	)
+	defer cancel()
+
+	reply, err := master.Receive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to receive snapshot: %w", err)
+	}
+
+	snapshot, isBulk := reply.(rheltypes.BulkString)
+	if !isBulk {
+		return fmt.Errorf("expected snapshot, got %T", reply)
+	}
+
+	return s.instance.LoadDbContent(snapshot.Text)
+}
+
+func (s *Server) replicaExecuteCommand(
+	conn net.Conn,
+	parser *rheltypes.Parser,
+	session *commands.Session,
+) error {
+	for result := range commands.ExecuteCommand(parser, session) {
+		if result.Err != nil {
+			return result.Err
+		}
+
+		if !result.Succeeded() {
+			s.logger.Printf("error replicating command %q", result.Command)
+		}
+
+		s.instance.Offset().Add(result.Size)
+
+		if !result.ReplicaRespond {
+			continue
+		}
+
+		if err := sendResponse(conn, result, session); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// followMaster connects to the master, performs the handshake and applies
+// the replication stream until the link or the server is closed.
+func (s *Server) followMaster(port string) {
+	defer s.untrack(nil)
+
+	dialer := net.Dialer{Timeout: defaultDialTimeout}
+
+	conn, err := dialer.Dial("tcp", masterAddr(s.replicaOf))
+	if err != nil {
+		s.logger.Printf("failed to dial master %s: %s", s.replicaOf, err)
+
+		return
+	}
+
+	if !s.track(conn) {
+		conn.Close()
+
+		return
+	}
+
+	defer func() {
+		s.untrack(conn)
+
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			s.logger.Printf("error closing master link: %s", err)
+		}
+	}()
+
+	master := client.NewConn(conn)
+
+	if err := sendHandshake(master, port); err != nil {
+		s.logger.Printf("error during master handshake: %s", err)
+
+		return
+	}
+
+	if err := s.loadSnapshot(master); err != nil {
+		s.logger.Printf("error loading master snapshot: %s", err)
+
+		return
+	}
+
+	// The commands following the snapshot may already be buffered by the
+	// parser.
+	conn, parser := master.Hijack()
+
+	session := commands.NewMasterSession(s.instance)
+	buf := make([]byte, defaultTcpBuffer)
+
+	for {
+		if err := s.replicaExecuteCommand(conn, parser, session); err != nil {
+			s.logger.Printf("closing master link: %s", err)
+
+			return
+		}
+
+		if done := s.readCommand(conn, buf, parser); done {
+			return
+		}
+	}
+}
@@ -0,0 +1,295 @@
+// Package server runs a Redis compatible server, several of them can live in
+// the same process since each one owns its keyspace, configuration,
+// replicas and pub/sub broker.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/commands"
+)
+
+const defaultMkdirMode = 0o755
+
+// ErrServerClosed is returned by Serve and ListenAndServe after Shutdown.
+var ErrServerClosed = errors.New("server: Server closed")
+
+type Server struct {
+	addr       string
+	dir        string
+	dbFilename string
+	databases  int
+	replicaOf  string
+	logger     *log.Logger
+	instance   *commands.Instance
+
+	maxMemory       string
+	maxMemoryPolicy string
+
+	mu          sync.Mutex
+	listeners   map[net.Listener]struct{}
+	conns       map[net.Conn]struct{}
+	handlers    sync.WaitGroup
+	replication sync.Once
+	closed      bool
+}
+
+// New creates a server and loads its RDB file, if any. Nothing is served
+// until ListenAndServe or Serve is called.
+func New(opts ...Option) (*Server, error) {
+	s := &Server{
+		addr:      defaultAddr,
+		databases: defaultDatabases,
+		logger:    log.Default(),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.databases < 1 {
+		return nil, fmt.Errorf("invalid number of databases %d", s.databases)
+	}
+
+	s.instance = commands.NewInstance(s.databases)
+
+	s.instance.SetRole(s.replicaOf)
+
+	if err := s.initMemory(); err != nil {
+		s.instance.Close()
+
+		return nil, err
+	}
+
+	if err := s.initDb(); err != nil {
+		s.instance.Close()
+
+		return nil, fmt.Errorf("error during db init: %w", err)
+	}
+
+	return s, nil
+}
+
+func (s *Server) initMemory() error {
+	settings := []struct{ name, value string }{
+		{"maxmemory", s.maxMemory},
+		{"maxmemory-policy", s.maxMemoryPolicy},
+	}
+
+	for _, setting := range settings {
+		if setting.value == "" {
+			continue
+		}
+
+		err := s.instance.SetConfig(setting.name, setting.value)
+		if err != nil {
+			return fmt.Errorf(
+				"invalid %s %q: %w",
+				setting.name,
+				setting.value,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) initDb() (err error) {
+	config := s.instance.Config()
+
+	if s.dir != "" {
+		config.SetString("dir", s.dir, 0)
+	}
+
+	if s.dbFilename == "" {
+		return nil
+	}
+
+	config.SetString("dbfilename", s.dbFilename, 0)
+
+	dbPath := s.dbFilename
+
+	if s.dir != "" {
+		if err = os.MkdirAll(s.dir, defaultMkdirMode); err != nil {
+			return err
+		}
+
+		dbPath = path.Join(s.dir, dbPath)
+	}
+
+	return s.instance.LoadDbFile(dbPath)
+}
+
+// Instance returns the state served by s.
+func (s *Server) Instance() *commands.Instance {
+	return s.instance
+}
+
+// ListenAndServe listens on the configured TCP address and serves it until
+// Shutdown is called.
+func (s *Server) ListenAndServe() error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen to %s: %w", s.addr, err)
+	}
+
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l until Shutdown is called, l is closed on
+// return. A replica starts following its master on the first call.
+func (s *Server) Serve(l net.Listener) error {
+	if !s.trackListener(l, true) {
+		l.Close()
+
+		return ErrServerClosed
+	}
+
+	defer s.trackListener(l, false)
+
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	if err != nil {
+		return fmt.Errorf("invalid listener address: %w", err)
+	}
+
+	s.instance.Config().SetString("port", port, 0)
+
+	if s.replicaOf != "" {
+		s.replication.Do(func() {
+			if s.track(nil) {
+				go s.followMaster(port)
+			}
+		})
+	}
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.shuttingDown() {
+				return ErrServerClosed
+			}
+
+			return fmt.Errorf("error accepting connection: %w", err)
+		}
+
+		if !s.track(conn) {
+			conn.Close()
+
+			return ErrServerClosed
+		}
+
+		go s.handleConn(conn)
+	}
+}
+
+// Shutdown stops the listeners, closes every connection and releases the
+// instance, then waits for the connection handlers to return or for ctx to
+// be done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	s.closed = true
+
+	var err error
+
+	for l := range s.listeners {
+		if closeErr := l.Close(); closeErr != nil &&
+			!errors.Is(closeErr, net.ErrClosed) {
+			err = errors.Join(err, closeErr)
+		}
+	}
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+
+	s.mu.Unlock()
+
+	// Closing the broker wakes up the commands blocked on it.
+	s.instance.Close()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.handlers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Join(err, ctx.Err())
+	}
+}
+
+func (s *Server) shuttingDown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+func (s *Server) trackListener(l net.Listener, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !add {
+		delete(s.listeners, l)
+		l.Close()
+
+		return true
+	}
+
+	if s.closed {
+		return false
+	}
+
+	s.listeners[l] = struct{}{}
+
+	return true
+}
+
+// track registers a running handler, along with its connection if any, so
+// Shutdown can close and wait for it. It reports false once the server is
+// shutting down, every successful call is paired with untrack.
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	if conn != nil {
+		s.conns[conn] = struct{}{}
+	}
+
+	s.handlers.Add(1)
+
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+
+	s.handlers.Done()
+}